@@ -0,0 +1,108 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/thep2p/go-eth-localnet/internal/contracts"
+)
+
+// stringList is a repeatable string flag.
+type stringList []string
+
+// String implements flag.Value.
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+// Set implements flag.Value.
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// runContracts dispatches contracts subcommands.
+func runContracts(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("contracts: missing subcommand")
+	}
+
+	switch args[0] {
+	case "bind":
+		return runContractsBind(args[1:], stdout)
+	default:
+		return fmt.Errorf("contracts: unknown subcommand %q", args[0])
+	}
+}
+
+// runContractsBind compiles Solidity files and generates Go bindings for their
+// contracts, written to the -out file or to stdout.
+func runContractsBind(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("contracts bind", flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	pkg := fs.String("pkg", "", "Go package name of the generated bindings (required)")
+	out := fs.String("out", "", "file to write the bindings to, stdout if empty")
+	omitBytecode := fs.Bool("omit-bytecode", false, "leave the bytecode and deploy functions out of the bindings")
+	basePath := fs.String("base-path", "", "root directory imports and sources are resolved against")
+	optimize := fs.Bool("optimize", false, "enable the solc optimizer")
+	runs := fs.Int("optimize-runs", 200, "optimizer runs, with -optimize")
+	evmVersion := fs.String("evm-version", "", "target EVM version, the compiler default if empty")
+	viaIR := fs.Bool("via-ir", false, "compile through the IR pipeline")
+	var names, remappings, includePaths, aliases stringList
+	fs.Var(&names, "contract", "contract to bind, as Name or file:Name (repeatable), all contracts if unset")
+	fs.Var(&remappings, "remap", "import remapping prefix=target (repeatable)")
+	fs.Var(&includePaths, "include-path", "additional import directory, requires -base-path (repeatable)")
+	fs.Var(&aliases, "alias", "rename an ABI identifier in Go, original=Alias (repeatable)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *pkg == "" {
+		return fmt.Errorf("contracts bind: -pkg is required")
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("contracts bind: expected at least one solidity file")
+	}
+
+	aliasMap := make(map[string]string, len(aliases))
+	for _, alias := range aliases {
+		original, renamed, ok := strings.Cut(alias, "=")
+		if !ok || original == "" || renamed == "" {
+			return fmt.Errorf("contracts bind: invalid alias %q, expected original=Alias", alias)
+		}
+		aliasMap[original] = renamed
+	}
+
+	settings := contracts.Settings{EVMVersion: *evmVersion, ViaIR: *viaIR}
+	if *optimize {
+		settings.Optimizer = contracts.Optimizer{Enabled: true, Runs: *runs}
+	}
+	artifacts, err := contracts.Compile(
+		contracts.CompileOptions{
+			Remappings:   remappings,
+			BasePath:     *basePath,
+			IncludePaths: includePaths,
+			Settings:     settings,
+		}, fs.Args()...,
+	)
+	if err != nil {
+		return fmt.Errorf("compile: %w", err)
+	}
+
+	opts := contracts.BindOptions{
+		Package:      *pkg,
+		Contracts:    names,
+		Aliases:      aliasMap,
+		OmitBytecode: *omitBytecode,
+	}
+	if *out != "" {
+		return contracts.WriteBindings(*out, artifacts, opts)
+	}
+	code, err := contracts.Bind(artifacts, opts)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(stdout, code)
+	return err
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/thep2p/go-eth-localnet/internal/contracts"
+	"github.com/thep2p/go-eth-localnet/internal/unittest"
+)
+
+// stubCompiler points the contracts compiler at a stub solc that answers any
+// input with the compiled SimpleTestContract, so the command runs without solc.
+// cf. internal/contracts/SimpleTestContract.sol
+func stubCompiler(t *testing.T) {
+	t.Helper()
+
+	tmp := unittest.NewTempDir(t)
+	t.Cleanup(tmp.Remove)
+	compilerDir := filepath.Join(tmp.Path(), "solc")
+	require.NoError(t, os.MkdirAll(compilerDir, 0755))
+
+	stub := `#!/bin/sh
+cat >/dev/null
+echo '{"contracts":{"SimpleTestContract.sol":{"SimpleTestContract":{"abi":[{"inputs":[],"name":"f","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"pure","type":"function"}],"evm":{"bytecode":{"object":"6080604052348015600e575f5ffd5b50608680601a5f395ff3fe6080604052348015600e575f5ffd5b50600436106026575f3560e01c806326121ff014602a575b5f5ffd5b60306044565b604051603b91906062565b60405180910390f35b5f602a905090565b5f819050919050565b605c81604c565b82525050565b5f60208201905060735f8301846055565b9291505056fea164736f6c634300081e000a"}}}}}}'
+`
+	require.NoError(t, os.WriteFile(filepath.Join(compilerDir, "solc-0.8.30"), []byte(stub), 0755))
+	t.Setenv(contracts.CompilerCacheDirEnv, compilerDir)
+	t.Setenv(contracts.ArtifactCacheDirEnv, filepath.Join(tmp.Path(), "artifacts"))
+}
+
+// TestContractsBind tests that bindings are printed to stdout or written to the -out file.
+func TestContractsBind(t *testing.T) {
+	stubCompiler(t)
+	source := "../../internal/contracts/SimpleTestContract.sol"
+
+	var stdout bytes.Buffer
+	require.NoError(t, run([]string{"contracts", "bind", "-pkg", "simple", source}, &stdout))
+	require.Contains(t, stdout.String(), "package simple")
+	require.Contains(t, stdout.String(), "func DeploySimpleTestContract(")
+
+	tmp := unittest.NewTempDir(t)
+	t.Cleanup(tmp.Remove)
+	out := filepath.Join(tmp.Path(), "simple", "simple.go")
+	stdout.Reset()
+	require.NoError(
+		t, run(
+			[]string{"contracts", "bind", "-pkg", "simple", "-omit-bytecode", "-alias", "f=Answer", "-out", out, source},
+			&stdout,
+		),
+	)
+	require.Empty(t, stdout.String())
+	code, err := os.ReadFile(out)
+	require.NoError(t, err)
+	require.NotContains(t, string(code), "func DeploySimpleTestContract(")
+	require.Contains(t, string(code), "func (_SimpleTestContract *SimpleTestContractCaller) Answer(")
+}
+
+// TestContractsBindErrors tests that missing or invalid arguments are rejected.
+func TestContractsBindErrors(t *testing.T) {
+	var stdout bytes.Buffer
+	require.ErrorContains(t, run([]string{"contracts"}, &stdout), "missing subcommand")
+	require.ErrorContains(t, run([]string{"contracts", "unknown"}, &stdout), "unknown subcommand")
+	require.ErrorContains(t, run([]string{"contracts", "bind", "x.sol"}, &stdout), "-pkg is required")
+	require.ErrorContains(t, run([]string{"contracts", "bind", "-pkg", "x"}, &stdout), "at least one solidity file")
+	require.ErrorContains(
+		t, run([]string{"contracts", "bind", "-pkg", "x", "-alias", "f", "x.sol"}, &stdout), "invalid alias",
+	)
+}
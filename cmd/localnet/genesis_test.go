@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/thep2p/go-eth-localnet/internal/consensus/prysm"
+	"github.com/thep2p/go-eth-localnet/internal/unittest"
+)
+
+// writeGenesisStateFixture generates a genesis state with the given number of
+// validators and writes it to a file in a temporary directory.
+func writeGenesisStateFixture(t *testing.T, validatorCount int) string {
+	t.Helper()
+
+	genesisState, err := prysm.GenerateGenesisState(unittest.ConsensusConfigFixture(t, validatorCount))
+	require.NoError(t, err)
+
+	tmp := unittest.NewTempDir(t)
+	t.Cleanup(tmp.Remove)
+	path := filepath.Join(tmp.Path(), "genesis.ssz")
+	require.NoError(t, os.WriteFile(path, genesisState, 0600))
+	return path
+}
+
+// TestGenesisInspect_Text verifies the human-readable output of genesis inspect.
+func TestGenesisInspect_Text(t *testing.T) {
+	path := writeGenesisStateFixture(t, 2)
+
+	var out bytes.Buffer
+	require.NoError(t, run([]string{"genesis", "inspect", path}, &out))
+
+	require.Contains(t, out.String(), "Genesis validators root:")
+	require.Contains(t, out.String(), "Validator count:")
+	require.Contains(t, out.String(), "WITHDRAWAL CREDENTIALS")
+	require.Contains(t, out.String(), "0x01")
+}
+
+// TestGenesisInspect_JSON verifies the JSON output of genesis inspect decodes
+// back into the inspected genesis summary.
+func TestGenesisInspect_JSON(t *testing.T) {
+	path := writeGenesisStateFixture(t, 2)
+
+	var out bytes.Buffer
+	require.NoError(t, run([]string{"genesis", "inspect", "-json", path}, &out))
+
+	var info prysm.GenesisInfo
+	require.NoError(t, json.Unmarshal(out.Bytes(), &info))
+	require.Equal(t, 2, info.ValidatorCount)
+	require.Len(t, info.Validators, 2)
+	require.NotZero(t, info.GenesisValidatorsRoot)
+}
+
+// TestGenesisInspect_Errors verifies argument and input errors are reported.
+func TestGenesisInspect_Errors(t *testing.T) {
+	var out bytes.Buffer
+
+	require.ErrorContains(t, run([]string{"genesis", "inspect"}, &out), "expected exactly one")
+	require.ErrorContains(t, run([]string{"genesis", "inspect", "does-not-exist.ssz"}, &out), "read genesis state")
+	require.ErrorContains(t, run([]string{"genesis", "bogus"}, &out), "unknown subcommand")
+	require.ErrorContains(t, run([]string{"bogus"}, &out), "unknown command")
+}
@@ -0,0 +1,53 @@
+// Command localnet provides tooling for inspecting and preparing local
+// Ethereum network artifacts.
+//
+// Usage:
+//
+//	localnet <command> <subcommand> [flags] [args]
+//
+// Commands:
+//
+//	genesis inspect   print the contents of an SSZ-encoded genesis beacon state
+//	contracts bind    generate Go bindings for Solidity contracts
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+const usage = `usage: localnet <command> <subcommand> [flags] [args]
+
+commands:
+  genesis inspect [-json] <genesis.ssz>   print the contents of an SSZ-encoded genesis beacon state
+  contracts bind -pkg <name> [-out <file>] [flags] <file.sol>...
+                                          generate Go bindings for Solidity contracts
+`
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "localnet: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run dispatches args to the matching command and writes command output to stdout.
+func run(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		_, _ = fmt.Fprint(stdout, usage)
+		return fmt.Errorf("missing command")
+	}
+
+	switch args[0] {
+	case "genesis":
+		return runGenesis(args[1:], stdout)
+	case "contracts":
+		return runContracts(args[1:], stdout)
+	case "help", "-h", "--help":
+		_, err := fmt.Fprint(stdout, usage)
+		return err
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
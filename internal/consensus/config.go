@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/go-playground/validator/v10"
 	"github.com/prysmaticlabs/prysm/v5/crypto/bls"
 )
@@ -30,6 +31,17 @@ type Config struct {
 	// GenesisRoot is the hash tree root of the genesis beacon state.
 	GenesisRoot common.Hash
 
+	// SecondsPerSlot is the slot duration of the beacon chain. Every node of a
+	// network must use the same value.
+	// Optional: zero means the mainnet 12 seconds.
+	SecondsPerSlot uint64
+
+	// ExecutionGenesis is the genesis of the paired EL node (e.g. node.NewGenesis).
+	// Its block hash is recorded in the beacon state's eth1_data.block_hash and its
+	// header becomes the latest execution payload header. If nil, the default
+	// localnet execution genesis stamped with GenesisTime is used instead.
+	ExecutionGenesis *core.Genesis
+
 	// Ports
 
 	// BeaconPort is the port for the Beacon API (typically 4000).
@@ -47,7 +59,13 @@ type Config struct {
 	// Format: http://host:port
 	EngineEndpoint string `validate:"required"`
 
-	// JWTSecret is the JWT secret for Engine API authentication.
+	// DepositContractAddress is the address of the deposit contract predeployed
+	// in the paired EL genesis (see node.WithDepositContract).
+	// Optional: zero means no deposit contract, so validators can only be added at genesis.
+	DepositContractAddress common.Address
+
+	// JWTSecret is the JWT secret for Engine API authentication, hex-encoded as
+	// in the jwt.hex file of the paired EL node (see node.Manager.GetJWTSecret).
 	// Must match the secret used by the paired EL node.
 	JWTSecret []byte `validate:"required,min=1"`
 
@@ -71,22 +89,31 @@ type Config struct {
 
 	// WithdrawalAddresses are Ethereum execution layer addresses where each
 	// validator's rewards and withdrawn stake will be sent. Must have one
-	// address per validator key.
+	// address per validator key unless Validators is set.
+	// Each address yields 0x01 credentials with a 32 ETH deposit.
 	WithdrawalAddresses []common.Address
 
+	// Validators are per-validator genesis deposit specs (credential type and balance).
+	// Optional: when set, must have one spec per validator key and takes
+	// precedence over WithdrawalAddresses.
+	Validators []ValidatorSpec
+
 	// FeeRecipient is the Ethereum address that receives transaction fees
 	// from blocks proposed by this validator.
 	FeeRecipient common.Address
 
 	// Optional: Checkpoint sync
 
-	// CheckpointSyncURL is a trusted source for checkpoint sync data.
-	// Checkpoint sync allows rapid syncing from a recent finalized checkpoint.
-	CheckpointSyncURL string
+	// CheckpointSyncURL is the Beacon API URL of a trusted beacon node, typically
+	// an earlier node of the same localnet, to start from that node's finalized
+	// state instead of genesis. prysm.Launcher starts the node from it (see
+	// prysm.ResolveSyncOrigin).
+	CheckpointSyncURL string `validate:"omitempty,url"`
 
-	// GenesisStateURL is a URL to fetch the genesis beacon state.
+	// GenesisStateURL is the Beacon API URL of a beacon node to fetch the genesis
+	// state from. Defaults to CheckpointSyncURL when checkpoint syncing.
 	// Used for bootstrapping new clients.
-	GenesisStateURL string
+	GenesisStateURL string `validate:"omitempty,url"`
 }
 
 // Validate checks that the configuration is valid for genesis state generation.
@@ -105,9 +132,40 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("at least one validator is required")
 	}
 
+	if len(c.Validators) > 0 {
+		if len(c.Validators) != len(c.ValidatorKeys) {
+			return fmt.Errorf("validator specs count (%d) must match validator keys count (%d)", len(c.Validators), len(c.ValidatorKeys))
+		}
+		for i, spec := range c.Validators {
+			if err := spec.Validate(); err != nil {
+				return fmt.Errorf("validator %d: %w", i, err)
+			}
+		}
+		return nil
+	}
+
 	if len(c.WithdrawalAddresses) != len(c.ValidatorKeys) {
 		return fmt.Errorf("withdrawal addresses count (%d) must match validator keys count (%d)", len(c.WithdrawalAddresses), len(c.ValidatorKeys))
 	}
 
 	return nil
 }
+
+// ValidatorSpecs returns the genesis deposit spec of every validator.
+//
+// Returns Validators if set; otherwise derives 0x01 specs with the default
+// 32 ETH balance from WithdrawalAddresses.
+func (c *Config) ValidatorSpecs() []ValidatorSpec {
+	if len(c.Validators) > 0 {
+		return c.Validators
+	}
+
+	specs := make([]ValidatorSpec, len(c.WithdrawalAddresses))
+	for i, addr := range c.WithdrawalAddresses {
+		specs[i] = ValidatorSpec{
+			WithdrawalType:    ExecutionWithdrawal,
+			WithdrawalAddress: addr,
+		}
+	}
+	return specs
+}
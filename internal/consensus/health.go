@@ -0,0 +1,391 @@
+package consensus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const (
+	// BeaconHealthPath is the Beacon API endpoint reporting node health.
+	BeaconHealthPath = "/eth/v1/node/health"
+	// BeaconSyncingPath is the Beacon API endpoint reporting sync status.
+	BeaconSyncingPath = "/eth/v1/node/syncing"
+	// BeaconGenesisPath is the Beacon API endpoint reporting genesis information.
+	BeaconGenesisPath = "/eth/v1/beacon/genesis"
+	// BeaconFinalityCheckpointsPath is the Beacon API endpoint reporting the
+	// justified and finalized checkpoints of the head state.
+	BeaconFinalityCheckpointsPath = "/eth/v1/beacon/states/head/finality_checkpoints"
+
+	// BeaconStartupTimeout is the maximum time to wait for a beacon node to become ready.
+	// Beacon nodes take longer than EL nodes to start: they load the genesis state
+	// and wait for the EL before serving.
+	BeaconStartupTimeout = 30 * time.Second
+
+	// probeInterval is the delay between readiness probes.
+	probeInterval = 100 * time.Millisecond
+	// probeTimeout bounds a single HTTP probe so one hung request cannot
+	// consume the whole readiness timeout.
+	probeTimeout = 2 * time.Second
+)
+
+var (
+	// ErrBeaconSyncing indicates the beacon node is up but still syncing (health 206).
+	ErrBeaconSyncing = errors.New("beacon node is syncing")
+	// ErrBeaconNotInitialized indicates the beacon node is not initialized or having issues (health 503).
+	ErrBeaconNotInitialized = errors.New("beacon node is not initialized")
+	// ErrBeaconGenesisUnknown indicates the beacon node does not know its genesis yet (genesis 404).
+	ErrBeaconGenesisUnknown = errors.New("beacon node genesis is not known")
+	// ErrExecutionOffline indicates the beacon node cannot reach its paired EL node.
+	ErrExecutionOffline = errors.New("beacon node reports execution layer offline")
+)
+
+// BeaconGenesis is the genesis information served by a beacon node.
+type BeaconGenesis struct {
+	// GenesisTime is the beacon chain genesis time.
+	GenesisTime time.Time
+	// GenesisValidatorsRoot is the hash tree root of the genesis validator registry.
+	GenesisValidatorsRoot common.Hash
+	// GenesisForkVersion is the fork version at genesis.
+	GenesisForkVersion hexutil.Bytes
+}
+
+// BeaconSyncStatus is the sync status served by a beacon node.
+type BeaconSyncStatus struct {
+	// HeadSlot is the slot of the node's head block.
+	HeadSlot uint64
+	// SyncDistance is the number of slots the head is behind the wall clock.
+	SyncDistance uint64
+	// IsSyncing is true while the node is catching up with the chain.
+	IsSyncing bool
+	// IsOptimistic is true while the head has not been verified by the EL.
+	IsOptimistic bool
+	// ELOffline is true when the node cannot reach its EL.
+	ELOffline bool
+}
+
+// BeaconCheckpoint is an epoch boundary block of the beacon chain.
+type BeaconCheckpoint struct {
+	// Epoch is the checkpoint epoch.
+	Epoch uint64
+	// Root is the root of the block at the start of the epoch.
+	Root common.Hash
+}
+
+// BeaconFinalityCheckpoints are the justified and finalized checkpoints of a beacon node's head state.
+type BeaconFinalityCheckpoints struct {
+	// PreviousJustified is the justified checkpoint as of the previous epoch.
+	PreviousJustified BeaconCheckpoint
+	// CurrentJustified is the latest justified checkpoint; its block is the safe head.
+	CurrentJustified BeaconCheckpoint
+	// Finalized is the latest finalized checkpoint.
+	Finalized BeaconCheckpoint
+}
+
+// CheckBeaconHealth probes /eth/v1/node/health once.
+//
+// Returns nil if the node is ready (200), ErrBeaconSyncing if it is syncing (206),
+// ErrBeaconNotInitialized if it is not initialized (503), or a transport error.
+func CheckBeaconHealth(ctx context.Context, endpoint string) error {
+	resp, err := beaconGet(ctx, endpoint, BeaconHealthPath)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusPartialContent:
+		return ErrBeaconSyncing
+	case http.StatusServiceUnavailable:
+		return ErrBeaconNotInitialized
+	default:
+		return fmt.Errorf("unexpected health status %d", resp.StatusCode)
+	}
+}
+
+// GetBeaconSyncStatus fetches /eth/v1/node/syncing once.
+func GetBeaconSyncStatus(ctx context.Context, endpoint string) (*BeaconSyncStatus, error) {
+	var data struct {
+		HeadSlot     string `json:"head_slot"`
+		SyncDistance string `json:"sync_distance"`
+		IsSyncing    bool   `json:"is_syncing"`
+		IsOptimistic bool   `json:"is_optimistic"`
+		ELOffline    bool   `json:"el_offline"`
+	}
+	if err := beaconGetData(ctx, endpoint, BeaconSyncingPath, &data); err != nil {
+		return nil, err
+	}
+
+	headSlot, err := strconv.ParseUint(data.HeadSlot, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parse head slot %q: %w", data.HeadSlot, err)
+	}
+	syncDistance, err := strconv.ParseUint(data.SyncDistance, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parse sync distance %q: %w", data.SyncDistance, err)
+	}
+
+	return &BeaconSyncStatus{
+		HeadSlot:     headSlot,
+		SyncDistance: syncDistance,
+		IsSyncing:    data.IsSyncing,
+		IsOptimistic: data.IsOptimistic,
+		ELOffline:    data.ELOffline,
+	}, nil
+}
+
+// GetBeaconGenesis fetches /eth/v1/beacon/genesis once.
+// Returns ErrBeaconGenesisUnknown if the node does not know its genesis yet.
+func GetBeaconGenesis(ctx context.Context, endpoint string) (*BeaconGenesis, error) {
+	var data struct {
+		GenesisTime           string        `json:"genesis_time"`
+		GenesisValidatorsRoot common.Hash   `json:"genesis_validators_root"`
+		GenesisForkVersion    hexutil.Bytes `json:"genesis_fork_version"`
+	}
+	if err := beaconGetData(ctx, endpoint, BeaconGenesisPath, &data); err != nil {
+		return nil, err
+	}
+
+	genesisTime, err := strconv.ParseInt(data.GenesisTime, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parse genesis time %q: %w", data.GenesisTime, err)
+	}
+
+	return &BeaconGenesis{
+		GenesisTime:           time.Unix(genesisTime, 0).UTC(),
+		GenesisValidatorsRoot: data.GenesisValidatorsRoot,
+		GenesisForkVersion:    data.GenesisForkVersion,
+	}, nil
+}
+
+// GetBeaconFinalityCheckpoints fetches /eth/v1/beacon/states/head/finality_checkpoints once.
+func GetBeaconFinalityCheckpoints(ctx context.Context, endpoint string) (*BeaconFinalityCheckpoints, error) {
+	var data struct {
+		PreviousJustified checkpointJSON `json:"previous_justified"`
+		CurrentJustified  checkpointJSON `json:"current_justified"`
+		Finalized         checkpointJSON `json:"finalized"`
+	}
+	if err := beaconGetData(ctx, endpoint, BeaconFinalityCheckpointsPath, &data); err != nil {
+		return nil, err
+	}
+
+	checkpoints := &BeaconFinalityCheckpoints{}
+	for _, c := range []struct {
+		name string
+		in   checkpointJSON
+		out  *BeaconCheckpoint
+	}{
+		{"previous justified", data.PreviousJustified, &checkpoints.PreviousJustified},
+		{"current justified", data.CurrentJustified, &checkpoints.CurrentJustified},
+		{"finalized", data.Finalized, &checkpoints.Finalized},
+	} {
+		epoch, err := strconv.ParseUint(c.in.Epoch, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s epoch %q: %w", c.name, c.in.Epoch, err)
+		}
+		*c.out = BeaconCheckpoint{Epoch: epoch, Root: c.in.Root}
+	}
+	return checkpoints, nil
+}
+
+// checkpointJSON is the Beacon API encoding of a checkpoint.
+type checkpointJSON struct {
+	Epoch string      `json:"epoch"`
+	Root  common.Hash `json:"root"`
+}
+
+// WaitForBeaconReady polls a beacon node until it is healthy, knows its
+// genesis, is not syncing and reaches its EL, or until timeout elapses.
+//
+// Args:
+//   - ctx: context for cancellation
+//   - endpoint: Beacon API base URL, e.g. http://127.0.0.1:4000
+//   - timeout: maximum time to wait (see BeaconStartupTimeout)
+//
+// Returns the node's genesis information once ready, or an error wrapping the
+// last failed probe if the node is not ready in time.
+func WaitForBeaconReady(ctx context.Context, endpoint string, timeout time.Duration) (*BeaconGenesis, error) {
+	var genesis *BeaconGenesis
+	err := poll(ctx, timeout, func() error {
+		if err := CheckBeaconHealth(ctx, endpoint); err != nil {
+			return fmt.Errorf("health: %w", err)
+		}
+
+		g, err := GetBeaconGenesis(ctx, endpoint)
+		if err != nil {
+			return fmt.Errorf("genesis: %w", err)
+		}
+
+		status, err := GetBeaconSyncStatus(ctx, endpoint)
+		if err != nil {
+			return fmt.Errorf("syncing: %w", err)
+		}
+		if status.IsSyncing {
+			return fmt.Errorf("syncing: %w (head slot %d, distance %d)", ErrBeaconSyncing, status.HeadSlot, status.SyncDistance)
+		}
+		if status.ELOffline {
+			return fmt.Errorf("syncing: %w", ErrExecutionOffline)
+		}
+
+		genesis = g
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("beacon node %q not ready: %w", endpoint, err)
+	}
+	return genesis, nil
+}
+
+// WaitForPairReady waits until an EL node and its paired beacon node are both ready.
+//
+// The EL is ready once its JSON-RPC endpoint answers eth_blockNumber; the beacon
+// node is ready per WaitForBeaconReady, which includes the beacon node reporting
+// its EL online. Both checks share the timeout.
+//
+// Args:
+//   - ctx: context for cancellation
+//   - rpcEndpoint: EL JSON-RPC URL, e.g. http://127.0.0.1:8545
+//   - beaconEndpoint: Beacon API base URL, e.g. http://127.0.0.1:4000
+//   - timeout: maximum time to wait for both nodes
+func WaitForPairReady(ctx context.Context, rpcEndpoint string, beaconEndpoint string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	err := poll(ctx, timeout, func() error {
+		return checkExecutionReady(ctx, rpcEndpoint)
+	})
+	if err != nil {
+		return fmt.Errorf("execution node %q not ready: %w", rpcEndpoint, err)
+	}
+
+	if _, err := WaitForBeaconReady(ctx, beaconEndpoint, time.Until(deadline)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// checkExecutionReady probes an EL JSON-RPC endpoint once with eth_blockNumber.
+func checkExecutionReady(ctx context.Context, rpcEndpoint string) error {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	body := []byte(`{"jsonrpc":"2.0","method":"eth_blockNumber","params":[],"id":1}`)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rpcEndpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var rpcResp struct {
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	if rpcResp.Error != nil {
+		return fmt.Errorf("rpc error: %s", rpcResp.Error.Message)
+	}
+	return nil
+}
+
+// poll calls probe every probeInterval until it succeeds, ctx is done or timeout elapses.
+// Returns nil on success, otherwise the last probe error.
+func poll(ctx context.Context, timeout time.Duration, probe func() error) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		err := probe()
+		if err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %s: %w", timeout, err)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", ctx.Err(), err)
+		case <-time.After(probeInterval):
+		}
+	}
+}
+
+// beaconGet issues a GET request against a Beacon API path.
+// The caller closes the response body.
+func beaconGet(ctx context.Context, endpoint string, path string) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	url := strings.TrimSuffix(endpoint, "/") + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// beaconGetData fetches a Beacon API path and decodes its "data" envelope into out.
+func beaconGetData(ctx context.Context, endpoint string, path string, out interface{}) error {
+	resp, err := beaconGet(ctx, endpoint, path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound && path == BeaconGenesisPath {
+		return ErrBeaconGenesisUnknown
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	envelope := struct {
+		Data interface{} `json:"data"`
+	}{Data: out}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// cancelOnClose releases a request context when the response body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the body and cancels the request context.
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
@@ -0,0 +1,129 @@
+package consensus_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"github.com/thep2p/go-eth-localnet/internal/consensus"
+	"github.com/thep2p/go-eth-localnet/internal/unittest"
+	"github.com/thep2p/go-eth-localnet/internal/utils"
+)
+
+// TestCheckBeaconHealth verifies health status codes map to readiness errors.
+func TestCheckBeaconHealth(t *testing.T) {
+	t.Parallel()
+
+	beacon := unittest.NewFakeBeacon(t)
+	ctx := context.Background()
+
+	require.NoError(t, consensus.CheckBeaconHealth(ctx, beacon.URL()))
+
+	beacon.SetHealthStatus(http.StatusPartialContent)
+	require.ErrorIs(t, consensus.CheckBeaconHealth(ctx, beacon.URL()), consensus.ErrBeaconSyncing)
+
+	beacon.SetHealthStatus(http.StatusServiceUnavailable)
+	require.ErrorIs(t, consensus.CheckBeaconHealth(ctx, beacon.URL()), consensus.ErrBeaconNotInitialized)
+}
+
+// TestGetBeaconGenesisAndSyncStatus verifies genesis and sync responses are decoded.
+func TestGetBeaconGenesisAndSyncStatus(t *testing.T) {
+	t.Parallel()
+
+	beacon := unittest.NewFakeBeacon(t)
+	ctx := context.Background()
+	genesisTime := time.Unix(1_700_000_123, 0)
+	root := common.HexToHash("0xabcdef")
+	beacon.SetGenesis(genesisTime, root)
+
+	genesis, err := consensus.GetBeaconGenesis(ctx, beacon.URL())
+	require.NoError(t, err)
+	require.Equal(t, genesisTime.Unix(), genesis.GenesisTime.Unix())
+	require.Equal(t, root, genesis.GenesisValidatorsRoot)
+	require.Len(t, genesis.GenesisForkVersion, 4)
+
+	beacon.SetGenesisKnown(false)
+	_, err = consensus.GetBeaconGenesis(ctx, beacon.URL())
+	require.ErrorIs(t, err, consensus.ErrBeaconGenesisUnknown)
+
+	beacon.SetSyncing(true)
+	beacon.SetELOffline(true)
+	status, err := consensus.GetBeaconSyncStatus(ctx, beacon.URL())
+	require.NoError(t, err)
+	require.True(t, status.IsSyncing)
+	require.True(t, status.ELOffline)
+	require.NotZero(t, status.SyncDistance)
+}
+
+// TestGetBeaconFinalityCheckpoints verifies decoding of the head state's finality checkpoints.
+func TestGetBeaconFinalityCheckpoints(t *testing.T) {
+	t.Parallel()
+
+	beacon := unittest.NewFakeBeacon(t)
+	beacon.SetFinality(3, 2)
+
+	checkpoints, err := consensus.GetBeaconFinalityCheckpoints(context.Background(), beacon.URL())
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), checkpoints.PreviousJustified.Epoch)
+	require.Equal(t, uint64(3), checkpoints.CurrentJustified.Epoch)
+	require.Equal(t, uint64(2), checkpoints.Finalized.Epoch)
+	require.NotEqual(t, common.Hash{}, checkpoints.Finalized.Root)
+}
+
+// TestWaitForBeaconReady verifies waiting succeeds once every probe passes and
+// times out with the last failing probe otherwise.
+func TestWaitForBeaconReady(t *testing.T) {
+	t.Parallel()
+
+	beacon := unittest.NewFakeBeacon(t)
+	ctx := context.Background()
+
+	// Not ready: the node is still syncing.
+	beacon.SetSyncing(true)
+	_, err := consensus.WaitForBeaconReady(ctx, beacon.URL(), 300*time.Millisecond)
+	require.ErrorIs(t, err, consensus.ErrBeaconSyncing)
+
+	// Not ready: the node cannot reach its EL.
+	beacon.SetSyncing(false)
+	beacon.SetELOffline(true)
+	_, err = consensus.WaitForBeaconReady(ctx, beacon.URL(), 300*time.Millisecond)
+	require.ErrorIs(t, err, consensus.ErrExecutionOffline)
+
+	// Becomes ready while waiting.
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		beacon.SetELOffline(false)
+	}()
+	genesis, err := consensus.WaitForBeaconReady(ctx, beacon.URL(), 5*time.Second)
+	require.NoError(t, err)
+	require.NotNil(t, genesis)
+}
+
+// TestWaitForBeaconReady_ContextCanceled verifies waiting stops when the context is canceled.
+func TestWaitForBeaconReady_ContextCanceled(t *testing.T) {
+	t.Parallel()
+
+	beacon := unittest.NewFakeBeacon(t)
+	beacon.SetHealthStatus(http.StatusServiceUnavailable)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	_, err := consensus.WaitForBeaconReady(ctx, beacon.URL(), time.Minute)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorIs(t, err, consensus.ErrBeaconNotInitialized)
+}
+
+// TestWaitForPairReady_ExecutionDown verifies the pair is not ready while the EL is unreachable.
+func TestWaitForPairReady_ExecutionDown(t *testing.T) {
+	t.Parallel()
+
+	beacon := unittest.NewFakeBeacon(t)
+	port := unittest.NewPort(t)
+
+	err := consensus.WaitForPairReady(context.Background(), utils.LocalAddress(port), beacon.URL(), 300*time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "execution node")
+}
@@ -0,0 +1,60 @@
+package prysm
+
+import (
+	"fmt"
+
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/thep2p/go-eth-localnet/internal/node"
+)
+
+// LocalnetConfigName is the name of the localnet beacon chain config.
+const LocalnetConfigName = "localnet"
+
+// LocalnetConfig returns the beacon chain config of a localnet.
+//
+// It is the mainnet config with every fork up to Electra active from genesis,
+// matching the execution genesis of the localnet (node.NewGenesis), which is
+// post-merge and at Prague from its first block. The fork versions are unique
+// to the localnet, so prysm's fork detection resolves localnet states and
+// blocks to this config.
+func LocalnetConfig() *params.BeaconChainConfig {
+	cfg := params.MainnetConfig().Copy()
+	cfg.ConfigName = LocalnetConfigName
+
+	// Genesis starts at Electra
+	cfg.AltairForkEpoch = 0
+	cfg.BellatrixForkEpoch = 0
+	cfg.CapellaForkEpoch = 0
+	cfg.DenebForkEpoch = 0
+	cfg.ElectraForkEpoch = 0
+	cfg.FuluForkEpoch = cfg.FarFutureEpoch
+	cfg.GenesisForkVersion = []byte{0, 0, 0, 0x4c}
+	cfg.AltairForkVersion = []byte{1, 0, 0, 0x4c}
+	cfg.BellatrixForkVersion = []byte{2, 0, 0, 0x4c}
+	cfg.CapellaForkVersion = []byte{3, 0, 0, 0x4c}
+	cfg.DenebForkVersion = []byte{4, 0, 0, 0x4c}
+	cfg.ElectraForkVersion = []byte{5, 0, 0, 0x4c}
+	cfg.FuluForkVersion = []byte{6, 0, 0, 0x4c}
+
+	// Execution chain of the localnet: post-merge from genesis
+	cfg.TerminalTotalDifficulty = "0"
+	cfg.DepositChainID = 1337
+	cfg.DepositNetworkID = 1337
+	cfg.DepositContractAddress = node.DefaultDepositContractAddress.Hex()
+
+	// Genesis holds only the configured validators and starts right away
+	cfg.MinGenesisActiveValidatorCount = 1
+	cfg.GenesisDelay = 0
+
+	cfg.InitializeForkSchedule()
+	return cfg
+}
+
+// init makes the localnet config prysm's active beacon chain config, as a prysm
+// beacon node of the localnet does. Genesis generation, deposit signing and
+// checkpoint decoding all read the active config.
+func init() {
+	if err := params.SetActive(LocalnetConfig()); err != nil {
+		panic(fmt.Sprintf("activate localnet beacon chain config: %v", err))
+	}
+}
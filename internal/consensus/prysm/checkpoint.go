@@ -0,0 +1,282 @@
+package prysm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prysmaticlabs/prysm/v5/api/client"
+	"github.com/prysmaticlabs/prysm/v5/api/client/beacon"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/encoding/ssz/detect"
+	"github.com/thep2p/go-eth-localnet/internal/consensus"
+)
+
+// ErrCheckpointMismatch indicates a checkpoint state does not belong to the
+// network described by the genesis state, or its block does not match the state.
+var ErrCheckpointMismatch = errors.New("checkpoint does not match genesis")
+
+// Checkpoint is a finalized beacon state and the block most recently applied to
+// it, downloaded from a peer's Beacon API.
+//
+// A beacon node initialized from a Checkpoint starts syncing at Slot instead of
+// replaying the chain from genesis.
+type Checkpoint struct {
+	// State is the SSZ-encoded finalized beacon state.
+	State []byte
+	// Block is the SSZ-encoded signed block at the state's latest block header.
+	Block []byte
+
+	// Slot is the slot of the finalized state.
+	Slot uint64
+	// Epoch is the epoch of the finalized state.
+	Epoch uint64
+	// StateRoot is the hash tree root of the finalized state.
+	StateRoot common.Hash
+	// BlockRoot is the hash tree root of the block.
+	BlockRoot common.Hash
+	// GenesisValidatorsRoot identifies the network the state belongs to.
+	GenesisValidatorsRoot common.Hash
+}
+
+// SyncOrigin is the data a beacon node starts from.
+type SyncOrigin struct {
+	// GenesisState is the SSZ-encoded genesis state of the network.
+	GenesisState []byte
+	// Checkpoint is the finalized checkpoint to start from, or nil to sync from genesis.
+	Checkpoint *Checkpoint
+}
+
+// StartSlot returns the slot the beacon node starts syncing from:
+// the checkpoint slot, or 0 when syncing from genesis.
+func (o *SyncOrigin) StartSlot() uint64 {
+	if o.Checkpoint == nil {
+		return 0
+	}
+	return o.Checkpoint.Slot
+}
+
+// Files SyncOrigin.Write writes into the beacon node's directory.
+const (
+	// GenesisStateFile holds the SSZ-encoded genesis state.
+	GenesisStateFile = "genesis.ssz"
+	// CheckpointStateFile holds the SSZ-encoded finalized checkpoint state.
+	CheckpointStateFile = "checkpoint_state.ssz"
+	// CheckpointBlockFile holds the SSZ-encoded finalized checkpoint block.
+	CheckpointBlockFile = "checkpoint_block.ssz"
+)
+
+// originFile is a file of a sync origin and the prysm flag that loads it.
+type originFile struct {
+	flag string
+	name string
+	data []byte
+}
+
+// Write writes the sync origin into dir and returns the prysm beacon-chain
+// flags that start a node from it: --genesis-state, plus --checkpoint-state
+// and --checkpoint-block when the origin has a checkpoint.
+//
+// Launcher.Launch passes these flags to the beacon node, so the node starts at StartSlot.
+//
+// Args:
+//   - dir: data directory of the beacon node, created if missing
+//
+// Returns the flags. All errors are CRITICAL and indicate the files could not be written.
+func (o *SyncOrigin) Write(dir string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("create sync origin directory %s: %w", dir, err)
+	}
+
+	files := []originFile{{flag: "--genesis-state", name: GenesisStateFile, data: o.GenesisState}}
+	if o.Checkpoint != nil {
+		files = append(files,
+			originFile{flag: "--checkpoint-state", name: CheckpointStateFile, data: o.Checkpoint.State},
+			originFile{flag: "--checkpoint-block", name: CheckpointBlockFile, data: o.Checkpoint.Block},
+		)
+	}
+
+	flags := make([]string, 0, len(files))
+	for _, f := range files {
+		path := filepath.Join(dir, f.name)
+		if err := os.WriteFile(path, f.data, 0644); err != nil {
+			return nil, fmt.Errorf("write %s: %w", path, err)
+		}
+		flags = append(flags, fmt.Sprintf("%s=%s", f.flag, path))
+	}
+	return flags, nil
+}
+
+// FetchCheckpoint downloads the finalized state and its latest block from a
+// beacon node, as a beacon node started with --checkpoint-sync-url does.
+//
+// The block is checked against the state's latest block header before it is
+// returned, so a Checkpoint is always a consistent state and block pair.
+//
+// Args:
+//   - ctx: context for the HTTP requests
+//   - beaconURL: Beacon API base URL of a synced beacon node, e.g. http://127.0.0.1:4000
+//
+// Returns the finalized checkpoint. All errors are CRITICAL and indicate the
+// node cannot be checkpoint-synced from beaconURL.
+func FetchCheckpoint(ctx context.Context, beaconURL string) (*Checkpoint, error) {
+	c, err := newBeaconClient(beaconURL)
+	if err != nil {
+		return nil, err
+	}
+
+	stateBytes, err := c.GetState(ctx, beacon.IdFinalized)
+	if err != nil {
+		return nil, fmt.Errorf("download finalized state from %s: %w", beaconURL, err)
+	}
+	vu, err := detect.FromState(stateBytes)
+	if err != nil {
+		return nil, fmt.Errorf("detect finalized state fork: %w", err)
+	}
+	st, err := vu.UnmarshalBeaconState(stateBytes)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal finalized state: %w", err)
+	}
+
+	// The finalized state may be ahead of its latest block (empty slots), so
+	// fetch the block by the slot of the state's latest block header.
+	header := st.LatestBlockHeader()
+	blockBytes, err := c.GetBlock(ctx, beacon.IdFromSlot(header.Slot))
+	if err != nil {
+		return nil, fmt.Errorf("download block at slot %d from %s: %w", header.Slot, beaconURL, err)
+	}
+	blk, err := vu.UnmarshalBeaconBlock(blockBytes)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal finalized block: %w", err)
+	}
+
+	bodyRoot, err := blk.Block().Body().HashTreeRoot()
+	if err != nil {
+		return nil, fmt.Errorf("compute finalized block body root: %w", err)
+	}
+	if !bytes.Equal(bodyRoot[:], header.BodyRoot) {
+		return nil, fmt.Errorf("%w: state latest block body root %#x, block body root %#x", ErrCheckpointMismatch, header.BodyRoot, bodyRoot)
+	}
+
+	stateRoot, err := st.HashTreeRoot(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("compute finalized state root: %w", err)
+	}
+	blockRoot, err := blk.Block().HashTreeRoot()
+	if err != nil {
+		return nil, fmt.Errorf("compute finalized block root: %w", err)
+	}
+
+	return &Checkpoint{
+		State:                 stateBytes,
+		Block:                 blockBytes,
+		Slot:                  uint64(st.Slot()),
+		Epoch:                 uint64(st.Slot()) / uint64(params.BeaconConfig().SlotsPerEpoch),
+		StateRoot:             common.BytesToHash(stateRoot[:]),
+		BlockRoot:             common.BytesToHash(blockRoot[:]),
+		GenesisValidatorsRoot: common.BytesToHash(st.GenesisValidatorsRoot()),
+	}, nil
+}
+
+// FetchGenesisState downloads the SSZ-encoded genesis state from a beacon node,
+// as a beacon node started with --genesis-beacon-api-url does.
+//
+// Args:
+//   - ctx: context for the HTTP request
+//   - beaconURL: Beacon API base URL of a beacon node of the network
+//
+// Returns the SSZ-encoded genesis state. All errors are CRITICAL.
+func FetchGenesisState(ctx context.Context, beaconURL string) ([]byte, error) {
+	c, err := newBeaconClient(beaconURL)
+	if err != nil {
+		return nil, err
+	}
+
+	genesisState, err := c.GetState(ctx, beacon.IdGenesis)
+	if err != nil {
+		return nil, fmt.Errorf("download genesis state from %s: %w", beaconURL, err)
+	}
+	return genesisState, nil
+}
+
+// ResolveSyncOrigin determines where a beacon node starts from.
+//
+// The genesis state is downloaded from cfg.GenesisStateURL, falling back to
+// cfg.CheckpointSyncURL, and is generated locally (see GenerateGenesisState)
+// when neither is set. When cfg.CheckpointSyncURL is set, the finalized
+// checkpoint is downloaded from it, so a node joining a running localnet
+// catches up from the last finalized state instead of replaying from genesis.
+//
+// Downloaded data is verified before use:
+//   - the genesis state root matches cfg.GenesisRoot (if set)
+//   - the genesis time matches cfg.GenesisTime
+//   - the checkpoint belongs to the same network as the genesis state
+//
+// Args:
+//   - ctx: context for the HTTP requests
+//   - cfg: consensus configuration of the joining node
+//
+// Returns the sync origin. All errors are CRITICAL; mismatches wrap
+// ErrGenesisRootMismatch, ErrGenesisTimeMismatch or ErrCheckpointMismatch.
+func ResolveSyncOrigin(ctx context.Context, cfg consensus.Config) (*SyncOrigin, error) {
+	genesisURL := cfg.GenesisStateURL
+	if genesisURL == "" {
+		genesisURL = cfg.CheckpointSyncURL
+	}
+
+	var (
+		genesisState []byte
+		err          error
+	)
+	if genesisURL == "" {
+		genesisState, err = GenerateGenesisState(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("generate genesis state: %w", err)
+		}
+	} else {
+		genesisState, err = FetchGenesisState(ctx, genesisURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	genesis, err := InspectGenesisState(genesisState)
+	if err != nil {
+		return nil, fmt.Errorf("inspect genesis state: %w", err)
+	}
+	if cfg.GenesisRoot != (common.Hash{}) && cfg.GenesisRoot != genesis.StateRoot {
+		return nil, fmt.Errorf("%w: consensus config %s, genesis state %s", ErrGenesisRootMismatch, cfg.GenesisRoot.Hex(), genesis.StateRoot.Hex())
+	}
+	if !cfg.GenesisTime.IsZero() && cfg.GenesisTime.Unix() != genesis.GenesisTime.Unix() {
+		return nil, fmt.Errorf("%w: consensus config %d, genesis state %d", ErrGenesisTimeMismatch, cfg.GenesisTime.Unix(), genesis.GenesisTime.Unix())
+	}
+
+	origin := &SyncOrigin{GenesisState: genesisState}
+	if cfg.CheckpointSyncURL == "" {
+		return origin, nil
+	}
+
+	cp, err := FetchCheckpoint(ctx, cfg.CheckpointSyncURL)
+	if err != nil {
+		return nil, err
+	}
+	if cp.GenesisValidatorsRoot != genesis.GenesisValidatorsRoot {
+		return nil, fmt.Errorf("%w: checkpoint genesis validators root %s, genesis state %s", ErrCheckpointMismatch, cp.GenesisValidatorsRoot.Hex(), genesis.GenesisValidatorsRoot.Hex())
+	}
+
+	origin.Checkpoint = cp
+	return origin, nil
+}
+
+// newBeaconClient creates a Beacon API client that accepts full beacon states.
+func newBeaconClient(beaconURL string) (*beacon.Client, error) {
+	c, err := beacon.NewClient(beaconURL, client.WithMaxBodySize(client.MaxBodySizeState))
+	if err != nil {
+		return nil, fmt.Errorf("invalid beacon api url %q: %w", beaconURL, err)
+	}
+	return c, nil
+}
@@ -0,0 +1,269 @@
+package prysm_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/blocks"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/transition"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	state_native "github.com/prysmaticlabs/prysm/v5/beacon-chain/state/state-native"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/stretchr/testify/require"
+	"github.com/thep2p/go-eth-localnet/internal/consensus"
+	"github.com/thep2p/go-eth-localnet/internal/consensus/prysm"
+	"github.com/thep2p/go-eth-localnet/internal/unittest"
+)
+
+// finalizedEpochs is how far the earlier node's chain has finalized in these tests.
+const finalizedEpochs = 2
+
+// checkpointConfigFixture builds a consensus config and its genesis state for a
+// network of validatorCount validators.
+func checkpointConfigFixture(t *testing.T, validatorCount int) (consensus.Config, []byte) {
+	t.Helper()
+
+	cfg := unittest.ConsensusConfigFixture(t, validatorCount)
+	genesisState, err := prysm.GenerateGenesisState(cfg)
+	require.NoError(t, err)
+	cfg.GenesisRoot, err = prysm.DeriveGenesisRoot(genesisState)
+	require.NoError(t, err)
+
+	return cfg, genesisState
+}
+
+// genesisBlockFixture decodes an Electra genesis state and builds its genesis block.
+func genesisBlockFixture(t *testing.T, genesisState []byte) (state.BeaconState, *ethpb.SignedBeaconBlockElectra) {
+	t.Helper()
+
+	protoState := &ethpb.BeaconStateElectra{}
+	require.NoError(t, protoState.UnmarshalSSZ(genesisState))
+	st, err := state_native.InitializeFromProtoElectra(protoState)
+	require.NoError(t, err)
+
+	genesisBlock, err := blocks.NewGenesisBlockForState(context.Background(), st)
+	require.NoError(t, err)
+	pb, err := genesisBlock.Proto()
+	require.NoError(t, err)
+	block, ok := pb.(*ethpb.SignedBeaconBlockElectra)
+	require.True(t, ok, "genesis block should be an Electra block")
+
+	return st, block
+}
+
+// serveChain makes beacon serve genesisState as its genesis and, as its
+// finalized checkpoint, the genesis state advanced to the start of
+// finalizedEpochs together with the genesis block (the latest block of that state).
+//
+// Returns the genesis block root.
+func serveChain(t *testing.T, beacon *unittest.FakeBeacon, genesisState []byte) common.Hash {
+	t.Helper()
+
+	st, genesisBlock := genesisBlockFixture(t, genesisState)
+	blockSSZ, err := genesisBlock.MarshalSSZ()
+	require.NoError(t, err)
+	blockRoot, err := genesisBlock.Block.HashTreeRoot()
+	require.NoError(t, err)
+
+	finalizedSlot := primitives.Slot(finalizedEpochs * uint64(params.BeaconConfig().SlotsPerEpoch))
+	finalized, err := transition.ProcessSlots(context.Background(), st.Copy(), finalizedSlot)
+	require.NoError(t, err)
+	finalizedSSZ, err := finalized.MarshalSSZ()
+	require.NoError(t, err)
+
+	beacon.SetState("genesis", genesisState)
+	beacon.SetState("finalized", finalizedSSZ)
+	beacon.SetBlock("0", blockSSZ)
+
+	return common.BytesToHash(blockRoot[:])
+}
+
+// TestResolveSyncOrigin_CheckpointSync verifies a node joining after finalization
+// starts from the earlier node's finalized state rather than from genesis.
+func TestResolveSyncOrigin_CheckpointSync(t *testing.T) {
+	t.Parallel()
+
+	cfg, genesisState := checkpointConfigFixture(t, 4)
+	earlier := unittest.NewFakeBeacon(t)
+	genesisBlockRoot := serveChain(t, earlier, genesisState)
+
+	// The late joiner only knows the network identity and a peer to sync from.
+	joiner := cfg
+	joiner.ValidatorKeys = nil
+	joiner.WithdrawalAddresses = nil
+	joiner.CheckpointSyncURL = earlier.URL()
+
+	origin, err := prysm.ResolveSyncOrigin(context.Background(), joiner)
+	require.NoError(t, err)
+	require.Equal(t, genesisState, origin.GenesisState)
+	require.NotNil(t, origin.Checkpoint)
+
+	slotsPerEpoch := uint64(params.BeaconConfig().SlotsPerEpoch)
+	require.Equal(t, uint64(finalizedEpochs)*slotsPerEpoch, origin.StartSlot())
+	require.Equal(t, uint64(finalizedEpochs), origin.Checkpoint.Epoch)
+	require.NotEqual(t, cfg.GenesisRoot, origin.Checkpoint.StateRoot, "checkpoint must not be the genesis state")
+	require.Equal(t, genesisBlockRoot, origin.Checkpoint.BlockRoot)
+
+	genesisInfo, err := prysm.InspectGenesisState(genesisState)
+	require.NoError(t, err)
+	require.Equal(t, genesisInfo.GenesisValidatorsRoot, origin.Checkpoint.GenesisValidatorsRoot)
+
+	// Without a checkpoint sync URL the node starts from a locally generated genesis.
+	origin, err = prysm.ResolveSyncOrigin(context.Background(), cfg)
+	require.NoError(t, err)
+	require.Nil(t, origin.Checkpoint)
+	require.Zero(t, origin.StartSlot())
+	require.Equal(t, genesisState, origin.GenesisState)
+}
+
+// TestResolveSyncOrigin_GenesisStateURL verifies the genesis state is fetched from
+// GenesisStateURL when set, independently of the checkpoint source.
+func TestResolveSyncOrigin_GenesisStateURL(t *testing.T) {
+	t.Parallel()
+
+	cfg, genesisState := checkpointConfigFixture(t, 2)
+	genesisSource := unittest.NewFakeBeacon(t)
+	genesisSource.SetState("genesis", genesisState)
+	checkpointSource := unittest.NewFakeBeacon(t)
+	serveChain(t, checkpointSource, genesisState)
+	// Fetching the genesis state from the checkpoint source would fail.
+	checkpointSource.SetState("genesis", []byte("not a state"))
+
+	cfg.GenesisStateURL = genesisSource.URL()
+	cfg.CheckpointSyncURL = checkpointSource.URL()
+
+	origin, err := prysm.ResolveSyncOrigin(context.Background(), cfg)
+	require.NoError(t, err)
+	require.Equal(t, genesisState, origin.GenesisState)
+	require.NotNil(t, origin.Checkpoint)
+
+	// A genesis-only bootstrap does not fetch a checkpoint.
+	cfg.CheckpointSyncURL = ""
+	origin, err = prysm.ResolveSyncOrigin(context.Background(), cfg)
+	require.NoError(t, err)
+	require.Nil(t, origin.Checkpoint)
+}
+
+// TestResolveSyncOrigin_Mismatches verifies data from another network or an
+// inconsistent checkpoint is rejected.
+func TestResolveSyncOrigin_Mismatches(t *testing.T) {
+	t.Parallel()
+
+	cfg, genesisState := checkpointConfigFixture(t, 2)
+	_, otherGenesisState := checkpointConfigFixture(t, 3)
+
+	t.Run("genesis root", func(t *testing.T) {
+		beacon := unittest.NewFakeBeacon(t)
+		serveChain(t, beacon, otherGenesisState)
+
+		joiner := cfg
+		joiner.CheckpointSyncURL = beacon.URL()
+		_, err := prysm.ResolveSyncOrigin(context.Background(), joiner)
+		require.ErrorIs(t, err, prysm.ErrGenesisRootMismatch)
+	})
+
+	t.Run("genesis time", func(t *testing.T) {
+		beacon := unittest.NewFakeBeacon(t)
+		serveChain(t, beacon, genesisState)
+
+		joiner := cfg
+		joiner.GenesisRoot = common.Hash{}
+		joiner.GenesisTime = cfg.GenesisTime.Add(time.Hour)
+		joiner.CheckpointSyncURL = beacon.URL()
+		_, err := prysm.ResolveSyncOrigin(context.Background(), joiner)
+		require.ErrorIs(t, err, prysm.ErrGenesisTimeMismatch)
+	})
+
+	t.Run("checkpoint from another network", func(t *testing.T) {
+		beacon := unittest.NewFakeBeacon(t)
+		serveChain(t, beacon, otherGenesisState)
+		beacon.SetState("genesis", genesisState)
+
+		joiner := cfg
+		joiner.CheckpointSyncURL = beacon.URL()
+		_, err := prysm.ResolveSyncOrigin(context.Background(), joiner)
+		require.ErrorIs(t, err, prysm.ErrCheckpointMismatch)
+	})
+
+	t.Run("block does not match state", func(t *testing.T) {
+		beacon := unittest.NewFakeBeacon(t)
+		serveChain(t, beacon, genesisState)
+		_, block := genesisBlockFixture(t, genesisState)
+		block.Block.Body.Graffiti = common.HexToHash("0x01").Bytes()
+		blockSSZ, err := block.MarshalSSZ()
+		require.NoError(t, err)
+		beacon.SetBlock("0", blockSSZ)
+
+		_, err = prysm.FetchCheckpoint(context.Background(), beacon.URL())
+		require.ErrorIs(t, err, prysm.ErrCheckpointMismatch)
+	})
+
+	t.Run("no finalized state", func(t *testing.T) {
+		beacon := unittest.NewFakeBeacon(t)
+		beacon.SetState("genesis", genesisState)
+
+		joiner := cfg
+		joiner.CheckpointSyncURL = beacon.URL()
+		_, err := prysm.ResolveSyncOrigin(context.Background(), joiner)
+		require.ErrorContains(t, err, "download finalized state")
+	})
+}
+
+// TestSyncOriginWrite verifies the beacon node flags of a late joiner load a
+// checkpoint state at the slot of the earlier node's finalized checkpoint.
+func TestSyncOriginWrite(t *testing.T) {
+	t.Parallel()
+
+	cfg, genesisState := checkpointConfigFixture(t, 4)
+	earlier := unittest.NewFakeBeacon(t)
+	serveChain(t, earlier, genesisState)
+	earlier.SetFinality(finalizedEpochs, finalizedEpochs)
+
+	joiner := cfg
+	joiner.CheckpointSyncURL = earlier.URL()
+	origin, err := prysm.ResolveSyncOrigin(context.Background(), joiner)
+	require.NoError(t, err)
+
+	tmp := unittest.NewTempDir(t)
+	t.Cleanup(tmp.Remove)
+	dir := filepath.Join(tmp.Path(), "beacon")
+	flags, err := origin.Write(dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"--genesis-state=" + filepath.Join(dir, prysm.GenesisStateFile),
+		"--checkpoint-state=" + filepath.Join(dir, prysm.CheckpointStateFile),
+		"--checkpoint-block=" + filepath.Join(dir, prysm.CheckpointBlockFile),
+	}, flags)
+
+	written, err := os.ReadFile(filepath.Join(dir, prysm.GenesisStateFile))
+	require.NoError(t, err)
+	require.Equal(t, genesisState, written)
+	written, err = os.ReadFile(filepath.Join(dir, prysm.CheckpointBlockFile))
+	require.NoError(t, err)
+	require.Equal(t, origin.Checkpoint.Block, written)
+
+	// The joiner starts at the finalized checkpoint the earlier node reports.
+	finality, err := consensus.GetBeaconFinalityCheckpoints(context.Background(), earlier.URL())
+	require.NoError(t, err)
+	finalizedSlot := finality.Finalized.Epoch * uint64(params.BeaconConfig().SlotsPerEpoch)
+	require.Equal(t, finalizedSlot, origin.StartSlot())
+
+	written, err = os.ReadFile(filepath.Join(dir, prysm.CheckpointStateFile))
+	require.NoError(t, err)
+	protoState := &ethpb.BeaconStateElectra{}
+	require.NoError(t, protoState.UnmarshalSSZ(written))
+	require.Equal(t, primitives.Slot(finalizedSlot), protoState.Slot)
+
+	// A node syncing from genesis only gets the genesis state.
+	origin, err = prysm.ResolveSyncOrigin(context.Background(), cfg)
+	require.NoError(t, err)
+	flags, err = origin.Write(dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{"--genesis-state=" + filepath.Join(dir, prysm.GenesisStateFile)}, flags)
+}
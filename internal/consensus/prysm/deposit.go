@@ -0,0 +1,172 @@
+package prysm
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	depositcontract "github.com/prysmaticlabs/prysm/v5/contracts/deposit"
+	"github.com/prysmaticlabs/prysm/v5/crypto/bls"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
+	"github.com/thep2p/go-eth-localnet/internal/consensus"
+	"github.com/thep2p/go-eth-localnet/internal/model"
+)
+
+// NewDepositData builds the signed deposit data of a validator joining after genesis.
+//
+// The deposit is signed exactly like the genesis deposits of GenerateGenesisState,
+// so the beacon chain accepts it when it processes the deposit contract log.
+//
+// Args:
+//   - secretKey: BLS secret key of the new validator
+//   - spec: withdrawal credential type, withdrawal target and deposit amount
+//
+// Returns:
+//   - depositData: the signed deposit data
+//   - root: hash tree root of the deposit data, passed to the contract as deposit_data_root
+//   - error: if the spec is invalid or signing fails; all errors are CRITICAL.
+func NewDepositData(secretKey bls.SecretKey, spec consensus.ValidatorSpec) (*ethpb.Deposit_Data, [32]byte, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, [32]byte{}, fmt.Errorf("invalid validator spec: %w", err)
+	}
+
+	withdrawalCreds, err := withdrawalCredentials(secretKey.PublicKey(), spec)
+	if err != nil {
+		return nil, [32]byte{}, err
+	}
+
+	return signDepositData(secretKey, withdrawalCreds, spec.DepositAmount())
+}
+
+// SubmitDeposit sends a deposit for a new validator to the deposit contract.
+//
+// Builds the signed deposit data (see NewDepositData) and submits an EIP-1559
+// transaction calling deposit(pubkey, withdrawal_credentials, signature,
+// deposit_data_root) with the deposit amount as value. Nonce, gas limit and fees
+// are taken from the node.
+//
+// Args:
+//   - ctx: context for the RPC calls
+//   - client: RPC client of an EL node with the deposit contract predeployed
+//   - depositContract: address of the deposit contract (see node.WithDepositContract)
+//   - sender: funded EL account paying for the deposit and gas
+//   - secretKey: BLS secret key of the new validator
+//   - spec: withdrawal credential type, withdrawal target and deposit amount
+//
+// Returns the transaction hash; the caller waits for the receipt. All errors
+// are CRITICAL and indicate the deposit was not submitted.
+func SubmitDeposit(
+	ctx context.Context,
+	client *rpc.Client,
+	depositContract common.Address,
+	sender *ecdsa.PrivateKey,
+	secretKey bls.SecretKey,
+	spec consensus.ValidatorSpec,
+) (common.Hash, error) {
+	depositData, root, err := NewDepositData(secretKey, spec)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("create deposit data: %w", err)
+	}
+
+	contractABI, err := abi.JSON(strings.NewReader(depositcontract.DepositContractABI))
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("parse deposit contract abi: %w", err)
+	}
+	callData, err := contractABI.Pack("deposit", depositData.PublicKey, depositData.WithdrawalCredentials, depositData.Signature, root)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("pack deposit call: %w", err)
+	}
+
+	// The deposit contract accounts in Gwei, the EL transfers Wei.
+	value := new(big.Int).Mul(new(big.Int).SetUint64(depositData.Amount), big.NewInt(params.GWei))
+	senderAddr := crypto.PubkeyToAddress(sender.PublicKey)
+
+	var chainID hexutil.Big
+	if err := client.CallContext(ctx, &chainID, model.EthChainID); err != nil {
+		return common.Hash{}, fmt.Errorf("get chain id: %w", err)
+	}
+
+	var nonce hexutil.Uint64
+	if err := client.CallContext(ctx, &nonce, model.EthGetTransactionCount, senderAddr, model.EthBlockPending); err != nil {
+		return common.Hash{}, fmt.Errorf("get nonce: %w", err)
+	}
+
+	var gas hexutil.Uint64
+	if err := client.CallContext(
+		ctx, &gas, model.EthEstimateGas, map[string]string{
+			model.CallContextFrom:  senderAddr.Hex(),
+			model.CallContextTo:    depositContract.Hex(),
+			model.CallContextValue: hexutil.EncodeBig(value),
+			model.CallContextData:  hexutil.Encode(callData),
+		},
+	); err != nil {
+		return common.Hash{}, fmt.Errorf("estimate gas: %w", err)
+	}
+
+	gasTipCap, gasFeeCap, err := suggestFees(ctx, client)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	tx := types.NewTx(
+		&types.DynamicFeeTx{
+			ChainID:   chainID.ToInt(),
+			Nonce:     uint64(nonce),
+			Gas:       uint64(gas),
+			GasTipCap: gasTipCap,
+			GasFeeCap: gasFeeCap,
+			To:        &depositContract,
+			Value:     value,
+			Data:      callData,
+		},
+	)
+	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(chainID.ToInt()), sender)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("sign deposit transaction: %w", err)
+	}
+	txBytes, err := signedTx.MarshalBinary()
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("marshal deposit transaction: %w", err)
+	}
+
+	var txHash common.Hash
+	if err := client.CallContext(ctx, &txHash, model.EthSendRawTransaction, hexutil.Encode(txBytes)); err != nil {
+		return common.Hash{}, fmt.Errorf("send deposit transaction: %w", err)
+	}
+
+	return txHash, nil
+}
+
+// suggestFees returns an EIP-1559 tip and fee cap: the node's suggested tip and
+// twice the latest base fee plus the tip, which survives several full blocks.
+func suggestFees(ctx context.Context, client *rpc.Client) (*big.Int, *big.Int, error) {
+	var tip hexutil.Big
+	if err := client.CallContext(ctx, &tip, model.EthMaxPriorityFeePerGas); err != nil {
+		return nil, nil, fmt.Errorf("get max priority fee: %w", err)
+	}
+
+	var head map[string]interface{}
+	if err := client.CallContext(ctx, &head, model.EthGetBlockByNumber, model.EthBlockLatest, false); err != nil {
+		return nil, nil, fmt.Errorf("get latest block: %w", err)
+	}
+	baseFeeHex, ok := head[model.BlockBaseFeePerGas].(string)
+	if !ok {
+		return nil, nil, fmt.Errorf("latest block has no base fee")
+	}
+	baseFee, err := hexutil.DecodeBig(baseFeeHex)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode base fee: %w", err)
+	}
+
+	feeCap := new(big.Int).Add(new(big.Int).Mul(baseFee, big.NewInt(2)), tip.ToInt())
+	return tip.ToInt(), feeCap, nil
+}
@@ -0,0 +1,158 @@
+package prysm_test
+
+import (
+	"context"
+	"encoding/binary"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/signing"
+	prysmparams "github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/container/trie"
+	depositcontract "github.com/prysmaticlabs/prysm/v5/contracts/deposit"
+	"github.com/stretchr/testify/require"
+	"github.com/thep2p/go-eth-localnet/internal/consensus"
+	"github.com/thep2p/go-eth-localnet/internal/consensus/prysm"
+	"github.com/thep2p/go-eth-localnet/internal/model"
+	"github.com/thep2p/go-eth-localnet/internal/node"
+	"github.com/thep2p/go-eth-localnet/internal/unittest"
+	"github.com/thep2p/go-eth-localnet/internal/utils"
+)
+
+// startNode starts a single Geth node with the given launch options and
+// returns an RPC client connected to it.
+func startNode(t *testing.T, opts ...node.LaunchOption) (context.Context, *rpc.Client) {
+	t.Helper()
+
+	ctx, manager := unittest.StartNodes(t, 1, opts...)
+	return ctx, unittest.DialNode(t, ctx, manager, 0)
+}
+
+// callDepositContract calls a view method of the deposit contract and unpacks its single result.
+func callDepositContract(t *testing.T, ctx context.Context, client *rpc.Client, method string) interface{} {
+	t.Helper()
+
+	contractABI, err := abi.JSON(strings.NewReader(depositcontract.DepositContractABI))
+	require.NoError(t, err)
+	callData, err := contractABI.Pack(method)
+	require.NoError(t, err)
+
+	var out hexutil.Bytes
+	require.NoError(
+		t, client.CallContext(
+			ctx, &out, model.CallContextEthCall, map[string]string{
+				model.CallContextTo:   node.DefaultDepositContractAddress.Hex(),
+				model.CallContextData: utils.ByteToHex(callData),
+			}, model.EthBlockLatest,
+		),
+	)
+	values, err := contractABI.Unpack(method, out)
+	require.NoError(t, err)
+	require.Len(t, values, 1)
+	return values[0]
+}
+
+// TestNewDepositData verifies deposit data for a post-genesis validator is
+// signed over the deposit domain and carries the requested amount.
+func TestNewDepositData(t *testing.T) {
+	t.Parallel()
+
+	keys, err := prysm.GenerateValidatorKeys(1)
+	require.NoError(t, err)
+	withdrawalAddr := unittest.RandomAddress(t)
+
+	depositData, root, err := prysm.NewDepositData(keys[0], consensus.ValidatorSpec{
+		WithdrawalType:    consensus.CompoundingWithdrawal,
+		WithdrawalAddress: withdrawalAddr,
+		Balance:           64_000_000_000,
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(64_000_000_000), depositData.Amount)
+	require.Equal(t, byte(0x02), depositData.WithdrawalCredentials[0])
+	require.Equal(t, withdrawalAddr.Bytes(), depositData.WithdrawalCredentials[12:])
+
+	expectedRoot, err := depositData.HashTreeRoot()
+	require.NoError(t, err)
+	require.Equal(t, expectedRoot, root)
+
+	domain, err := signing.ComputeDomain(
+		prysmparams.BeaconConfig().DomainDeposit,
+		prysmparams.BeaconConfig().GenesisForkVersion,
+		prysmparams.BeaconConfig().ZeroHash[:],
+	)
+	require.NoError(t, err)
+	require.NoError(t, depositcontract.VerifyDepositSignature(depositData, domain))
+
+	_, _, err = prysm.NewDepositData(keys[0], consensus.ValidatorSpec{WithdrawalType: consensus.ExecutionWithdrawal})
+	require.ErrorContains(t, err, "requires a withdrawal address")
+}
+
+// TestSubmitDeposit verifies that the predeployed deposit contract starts with
+// an empty deposit tree and accepts a signed deposit for a new validator.
+func TestSubmitDeposit(t *testing.T) {
+	senderKey := unittest.PrivateKeyFixture(t)
+	senderAddr := crypto.PubkeyToAddress(senderKey.PublicKey)
+	hundredEth := new(big.Int).Mul(big.NewInt(100), big.NewInt(params.Ether))
+
+	ctx, client := startNode(
+		t,
+		node.WithDepositContract(node.DefaultDepositContractAddress),
+		node.WithPreFundGenesisAccount(senderAddr, hundredEth),
+	)
+
+	// The predeployed storage must describe an empty deposit tree.
+	emptyTrie, err := trie.NewTrie(prysmparams.BeaconConfig().DepositContractTreeDepth)
+	require.NoError(t, err)
+	emptyRoot, err := emptyTrie.HashTreeRoot()
+	require.NoError(t, err)
+	require.Equal(t, emptyRoot, callDepositContract(t, ctx, client, "get_deposit_root"))
+
+	// Deposit for a validator that is not part of any genesis state.
+	keys, err := prysm.GenerateValidatorKeys(8)
+	require.NoError(t, err)
+	spec := consensus.ValidatorSpec{
+		WithdrawalType:    consensus.ExecutionWithdrawal,
+		WithdrawalAddress: unittest.RandomAddress(t),
+	}
+	txHash, err := prysm.SubmitDeposit(ctx, client, node.DefaultDepositContractAddress, senderKey, keys[7], spec)
+	require.NoError(t, err)
+
+	var receipt map[string]interface{}
+	require.Eventually(
+		t, func() bool {
+			if err := client.CallContext(ctx, &receipt, model.EthGetTransactionReceipt, txHash); err != nil {
+				return false
+			}
+			return receipt != nil && receipt[model.ReceiptBlockNumber] != nil
+		}, node.OperationTimeout, 500*time.Millisecond, "deposit receipt not available",
+	)
+	require.Equal(t, model.ReceiptTxStatusSuccess, receipt[model.ReceiptStatus])
+
+	// The contract emits exactly one DepositEvent.
+	logs, ok := receipt[model.ReceiptLogs].([]interface{})
+	require.True(t, ok)
+	require.Len(t, logs, 1)
+
+	// The deposit count is encoded as an 8-byte little-endian integer.
+	count := callDepositContract(t, ctx, client, "get_deposit_count").([]byte)
+	require.Equal(t, uint64(1), binary.LittleEndian.Uint64(count))
+
+	// The on-chain deposit root must match the tree built from our deposit data.
+	_, depositRoot, err := prysm.NewDepositData(keys[7], spec)
+	require.NoError(t, err)
+	expectedTrie, err := trie.GenerateTrieFromItems([][]byte{depositRoot[:]}, prysmparams.BeaconConfig().DepositContractTreeDepth)
+	require.NoError(t, err)
+	expectedRoot, err := expectedTrie.HashTreeRoot()
+	require.NoError(t, err)
+	require.Equal(t, expectedRoot, callDepositContract(t, ctx, client, "get_deposit_root"))
+
+	require.NotEqual(t, common.Hash(emptyRoot), common.Hash(expectedRoot))
+}
@@ -3,25 +3,45 @@ package prysm
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/altair"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/helpers"
 	"github.com/prysmaticlabs/prysm/v5/beacon-chain/core/signing"
-	state_native "github.com/prysmaticlabs/prysm/v5/beacon-chain/state/state-native"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state/stateutil"
 	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/prysmaticlabs/prysm/v5/container/trie"
 	"github.com/prysmaticlabs/prysm/v5/crypto/bls"
+	"github.com/prysmaticlabs/prysm/v5/crypto/hash"
 	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
 	"github.com/prysmaticlabs/prysm/v5/runtime/interop"
+	"github.com/prysmaticlabs/prysm/v5/runtime/version"
 	"github.com/thep2p/go-eth-localnet/internal/consensus"
+	"github.com/thep2p/go-eth-localnet/internal/node"
 )
 
-// GenerateGenesisState creates a beacon chain genesis state from configuration.
+// genesisMu serializes genesis state generation; see GenerateGenesisState.
+var genesisMu sync.Mutex
+
+// GenerateGenesisState creates an Electra beacon chain genesis state from configuration.
 //
 // Converts validator keys to Prysm deposits and generates an SSZ-encoded genesis
-// state. Each validator gets independent withdrawal credentials from cfg.WithdrawalAddresses.
+// state. Each validator gets independent withdrawal credentials and a deposit
+// amount from cfg.ValidatorSpecs (0x01 credentials and 32 ETH per
+// cfg.WithdrawalAddresses entry unless cfg.Validators is set).
+//
+// The state starts at the Electra fork, matching the Prague execution genesis of
+// the localnet, so effective balances follow the Electra rules: up to
+// MaxEffectiveBalanceElectra (2048 ETH) for 0x02 credentials and up to
+// MinActivationBalance (32 ETH) otherwise. Validators with an effective balance
+// of at least MinActivationBalance are active from genesis.
 //
 // Args:
-//   - cfg: consensus configuration containing validator keys, withdrawal addresses,
-//     genesis time, and network parameters
+//   - cfg: consensus configuration containing validator keys, withdrawal addresses
+//     or validator specs, genesis time, execution genesis and network parameters
 //
 // Returns:
 //   - SSZ-encoded genesis state (full beacon state serialized to binary format,
@@ -30,30 +50,53 @@ import (
 //
 // All errors are CRITICAL and indicate genesis state generation cannot proceed.
 func GenerateGenesisState(cfg consensus.Config) ([]byte, error) {
+	// Prysm caches committees by RANDAO seed, which at genesis is derived from the
+	// execution genesis block hash alone. States of different networks sharing an
+	// execution genesis would read each other's active validators, so generation
+	// is serialized and starts from empty caches.
+	genesisMu.Lock()
+	defer genesisMu.Unlock()
+	helpers.ClearCache()
+
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
 
-	// Create deposit data with per-validator withdrawal addresses
-	depositDataItems, depositDataRoots, err := createDepositDataWithWithdrawalAddresses(cfg.ValidatorKeys, cfg.WithdrawalAddresses)
+	// Create deposit data with per-validator withdrawal credentials and balances
+	depositDataItems, depositDataRoots, err := createDepositData(cfg.ValidatorKeys, cfg.ValidatorSpecs())
 	if err != nil {
 		return nil, fmt.Errorf("create deposit data: %w", err)
 	}
 
-	// Generate genesis state using interop helpers
-	// Needs both depositDataItems (to populate validator registry) and depositDataRoots
-	// (to build the deposit merkle tree in beacon state's eth1_data field).
-	// The roots become leaves in the deposit tree that tracks all validator deposits.
+	// The execution genesis block anchors the beacon chain: its hash becomes
+	// eth1_data.block_hash and its header the latest execution payload header.
+	elGenesis := cfg.ExecutionGenesis
+	if elGenesis == nil {
+		elGenesis = node.NewGenesis()
+		elGenesis.Timestamp = uint64(cfg.GenesisTime.Unix())
+	}
+	elBlock := elGenesis.ToBlock()
+
+	// Generate the genesis state using the premine interop helper, which processes
+	// the deposits into the validator registry and fills in the Electra fields.
 	genesisTime := uint64(cfg.GenesisTime.Unix())
-	protoState, _, err := interop.GenerateGenesisStateFromDepositData(context.Background(), genesisTime, depositDataItems, depositDataRoots)
+	st, err := interop.NewPreminedGenesis(
+		context.Background(),
+		genesisTime,
+		uint64(len(depositDataItems)),
+		0,
+		version.Electra,
+		elBlock,
+		interop.WithDepositData(depositDataItems, depositDataRoots),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("generate genesis state: %w", err)
 	}
 
-	// Wrap proto state in beacon state interface
-	st, err := state_native.InitializeFromProtoPhase0(protoState)
-	if err != nil {
-		return nil, fmt.Errorf("initialize state: %w", err)
+	// The premine filled the caches with pre-Electra effective balances
+	helpers.ClearCache()
+	if err := applyElectraGenesisRules(st, depositDataRoots, elBlock.Hash()); err != nil {
+		return nil, fmt.Errorf("apply electra genesis rules: %w", err)
 	}
 
 	// Marshal state to SSZ format (binary serialization)
@@ -68,6 +111,111 @@ func GenerateGenesisState(cfg consensus.Config) ([]byte, error) {
 	return sszBytes, nil
 }
 
+// applyElectraGenesisRules brings a premined genesis state in line with the
+// Electra genesis of the consensus specs.
+//
+// The premine helper processes the genesis deposits with pre-Electra rules, which
+// cap every effective balance at 32 ETH and only activate validators with exactly
+// 32 ETH, and records an empty deposit tree. This recomputes effective balances
+// and activations per validator, records the genesis deposits in eth1_data (so
+// the deposit snapshot matches the validator registry), and marks EIP-6110 deposit
+// requests as not started yet. If any validator changed, it then refreshes the
+// genesis validators root and sync committees, which depend on the registry.
+//
+// Args:
+//   - st: premined Electra genesis state, modified in place
+//   - depositDataRoots: hash tree roots of the genesis deposits (deposit tree leaves)
+//   - elBlockHash: hash of the execution genesis block
+//
+// All errors are CRITICAL and indicate genesis state generation cannot proceed.
+func applyElectraGenesisRules(st state.BeaconState, depositDataRoots [][]byte, elBlockHash common.Hash) error {
+	cfg := params.BeaconConfig()
+
+	changed := false
+	for i := 0; i < st.NumValidators(); i++ {
+		idx := primitives.ValidatorIndex(i)
+		balance, err := st.BalanceAtIndex(idx)
+		if err != nil {
+			return fmt.Errorf("validator %d: balance: %w", i, err)
+		}
+		v, err := st.ValidatorAtIndex(idx)
+		if err != nil {
+			return fmt.Errorf("validator %d: %w", i, err)
+		}
+		readOnly, err := st.ValidatorAtIndexReadOnly(idx)
+		if err != nil {
+			return fmt.Errorf("validator %d: %w", i, err)
+		}
+
+		// 0x02 credentials count up to MaxEffectiveBalanceElectra, others up to MinActivationBalance
+		effectiveBalance := min(balance-balance%cfg.EffectiveBalanceIncrement, helpers.ValidatorMaxEffectiveBalance(readOnly))
+		activationEpoch := cfg.FarFutureEpoch
+		if effectiveBalance >= cfg.MinActivationBalance {
+			activationEpoch = 0
+		}
+		if v.EffectiveBalance == effectiveBalance && v.ActivationEpoch == activationEpoch {
+			continue
+		}
+		changed = true
+		v.EffectiveBalance = effectiveBalance
+		v.ActivationEligibilityEpoch = activationEpoch
+		v.ActivationEpoch = activationEpoch
+		if err := st.UpdateValidatorAtIndex(idx, v); err != nil {
+			return fmt.Errorf("validator %d: update: %w", i, err)
+		}
+	}
+
+	// Record the genesis deposits in the deposit snapshot. The deposit index is
+	// already at the deposit count, so blocks need not include them again.
+	depositTree, err := trie.GenerateTrieFromItems(depositDataRoots, cfg.DepositContractTreeDepth)
+	if err != nil {
+		return fmt.Errorf("generate deposit tree: %w", err)
+	}
+	depositRoot, err := depositTree.HashTreeRoot()
+	if err != nil {
+		return fmt.Errorf("hash deposit tree: %w", err)
+	}
+	depositCount := uint64(len(depositDataRoots))
+	if err := st.SetEth1Data(&ethpb.Eth1Data{
+		DepositRoot:  depositRoot[:],
+		DepositCount: depositCount,
+		BlockHash:    elBlockHash.Bytes(),
+	}); err != nil {
+		return fmt.Errorf("set eth1 data: %w", err)
+	}
+	if err := st.SetEth1DepositIndex(depositCount); err != nil {
+		return fmt.Errorf("set eth1 deposit index: %w", err)
+	}
+	if err := st.SetDepositRequestsStartIndex(cfg.UnsetDepositRequestsStartIndex); err != nil {
+		return fmt.Errorf("set deposit requests start index: %w", err)
+	}
+
+	if !changed {
+		return nil
+	}
+
+	validatorsRoot, err := stateutil.ValidatorRegistryRoot(st.Validators())
+	if err != nil {
+		return fmt.Errorf("compute genesis validators root: %w", err)
+	}
+	if err := st.SetGenesisValidatorsRoot(validatorsRoot[:]); err != nil {
+		return fmt.Errorf("set genesis validators root: %w", err)
+	}
+
+	syncCommittee, err := altair.NextSyncCommittee(context.Background(), st)
+	if err != nil {
+		return fmt.Errorf("compute sync committee: %w", err)
+	}
+	if err := st.SetCurrentSyncCommittee(syncCommittee); err != nil {
+		return fmt.Errorf("set current sync committee: %w", err)
+	}
+	if err := st.SetNextSyncCommittee(syncCommittee); err != nil {
+		return fmt.Errorf("set next sync committee: %w", err)
+	}
+
+	return nil
+}
+
 // DeriveGenesisRoot calculates the genesis beacon state root from SSZ-encoded state.
 //
 // Args:
@@ -79,20 +227,9 @@ func GenerateGenesisState(cfg consensus.Config) ([]byte, error) {
 //
 // All errors are CRITICAL and indicate the genesis state is invalid or corrupted.
 func DeriveGenesisRoot(genesisState []byte) (common.Hash, error) {
-	if len(genesisState) == 0 {
-		return common.Hash{}, fmt.Errorf("genesis state is empty")
-	}
-
-	// Unmarshal SSZ into proto message (Phase0 for now)
-	protoState := &ethpb.BeaconState{}
-	if err := protoState.UnmarshalSSZ(genesisState); err != nil {
-		return common.Hash{}, fmt.Errorf("unmarshal ssz: %w", err)
-	}
-
-	// Wrap in beacon state interface
-	st, err := state_native.InitializeFromProtoPhase0(protoState)
+	st, err := unmarshalGenesisState(genesisState)
 	if err != nil {
-		return common.Hash{}, fmt.Errorf("initialize state: %w", err)
+		return common.Hash{}, err
 	}
 
 	// Compute hash tree root
@@ -104,14 +241,11 @@ func DeriveGenesisRoot(genesisState []byte) (common.Hash, error) {
 	return common.BytesToHash(root[:]), nil
 }
 
-// createDepositDataWithWithdrawalAddresses creates deposit data for validators.
+// createDepositData creates deposit data for validators.
 //
 // Deposit data is a cryptographically signed structure proving validator ownership.
 // It contains: validator public key (BLS12-381), withdrawal credentials, stake amount
-// (32 ETH), and BLS signature over these fields.
-//
-// Withdrawal address is a standard 20-byte Ethereum account address where validator
-// rewards and stake are sent when the validator exits or receives withdrawals.
+// (32 ETH unless the spec overrides it), and BLS signature over these fields.
 //
 // The beacon state maintains a deposit merkle tree where each validator deposit is
 // one leaf. During genesis, this function computes the hash tree root of each
@@ -120,100 +254,159 @@ func DeriveGenesisRoot(genesisState []byte) (common.Hash, error) {
 //
 // Args:
 //   - secretKeys: BLS secret keys for each validator
-//   - withdrawalAddresses: Ethereum addresses for each validator's withdrawals
+//   - specs: withdrawal credential type, withdrawal target and balance for each validator
 //
 // Returns:
 //   - depositDataItems: Prysm deposit data structures for each validator
 //   - depositDataRoots: Hash tree roots of each deposit data (leaves in deposit tree)
 //   - error: Any error encountered during processing, all errors are CRITICAL.
-func createDepositDataWithWithdrawalAddresses(
+func createDepositData(
 	secretKeys []bls.SecretKey,
-	withdrawalAddresses []common.Address,
+	specs []consensus.ValidatorSpec,
 ) ([]*ethpb.Deposit_Data, [][]byte, error) {
+	if len(specs) != len(secretKeys) {
+		return nil, nil, fmt.Errorf("validator specs count (%d) must match validator keys count (%d)", len(specs), len(secretKeys))
+	}
+
 	depositDataItems := make([]*ethpb.Deposit_Data, len(secretKeys))
 	depositDataRoots := make([][]byte, len(secretKeys))
 
 	for i, secretKey := range secretKeys {
-		// Create withdrawal credentials (32 bytes for SSZ merkleization)
-		// Beacon chain uses 32-byte fields for all hash-sized data to maintain
-		// uniform merkle tree chunks. Withdrawal credentials structure:
-		//
-		//   [0x01][11 zero bytes][20-byte Ethereum address]
-		//    ^^^^^               ^^^^^^^^^^^^^^^^^^^^^^^
-		//    type                  withdrawal address
-		//
-		// Type 0x01 = direct withdrawal to Ethereum address (modern standard)
-		// Type 0x00 = BLS withdrawal credentials (legacy, pre-Shanghai)
-		withdrawalCreds := make([]byte, 32)
-		withdrawalCreds[0] = params.BeaconConfig().ETH1AddressWithdrawalPrefixByte // 0x01
-		// Copy 20-byte Ethereum address into bytes 12-31 (bytes 1-11 remain zero padding)
-		copy(withdrawalCreds[12:], withdrawalAddresses[i].Bytes())
-
-		// Create deposit message (unsigned data to be staked)
-		// MaxEffectiveBalance = 32 ETH, the standard validator stake
-		depositMsg := &ethpb.DepositMessage{
-			PublicKey:             secretKey.PublicKey().Marshal(),
-			WithdrawalCredentials: withdrawalCreds,
-			Amount:                params.BeaconConfig().MaxEffectiveBalance, // 32 ETH
-		}
-
-		// Sign the deposit message to prove ownership of the validator key
-		// Domain separates signature purposes (deposit vs attestation vs block proposal)
-		domain, err := signing.ComputeDomain(
-			params.BeaconConfig().DomainDeposit,
-			params.BeaconConfig().GenesisForkVersion,
-			params.BeaconConfig().ZeroHash[:],
-		)
+		withdrawalCreds, err := withdrawalCredentials(secretKey.PublicKey(), specs[i])
 		if err != nil {
-			return nil, nil, fmt.Errorf("compute domain: %w", err)
+			return nil, nil, fmt.Errorf("validator %d: %w", i, err)
 		}
 
-		// Compute signing root: hash(depositMsg + domain) for signature
-		signingRoot, err := signing.ComputeSigningRoot(depositMsg, domain)
+		depositData, root, err := signDepositData(secretKey, withdrawalCreds, specs[i].DepositAmount())
 		if err != nil {
-			return nil, nil, fmt.Errorf("compute signing root: %w", err)
+			return nil, nil, fmt.Errorf("validator %d: %w", i, err)
 		}
 
-		// Sign with validator's BLS secret key to prove ownership
-		signature := secretKey.Sign(signingRoot[:])
+		depositDataItems[i] = depositData
+		depositDataRoots[i] = root[:] // Root of deposit data, leaf of deposit tree
+	}
+
+	return depositDataItems, depositDataRoots, nil
+}
 
-		// Create final deposit data (unsigned message + signature)
-		// This proves: "I own this validator key and authorize this configuration"
-		depositData := &ethpb.Deposit_Data{
-			PublicKey:             depositMsg.PublicKey,
-			WithdrawalCredentials: depositMsg.WithdrawalCredentials,
-			Amount:                depositMsg.Amount,
-			Signature:             signature.Marshal(),
-		}
+// withdrawalCredentials builds the 32-byte withdrawal credentials for a validator.
+//
+// Beacon chain uses 32-byte fields for all hash-sized data to maintain uniform
+// merkle tree chunks. Withdrawal credentials structure:
+//
+//	[0x01|0x02][11 zero bytes][20-byte Ethereum address]
+//	[0x00][sha256(withdrawal BLS pubkey)[1:]]
+//	 ^^^^
+//	 type
+//
+// Type 0x00 = BLS withdrawal credentials (legacy, pre-Shanghai)
+// Type 0x01 = direct withdrawal to Ethereum address (modern standard)
+// Type 0x02 = compounding withdrawal to Ethereum address (Electra)
+//
+// Args:
+//   - validatorPubkey: the validator's BLS public key, used for 0x00 credentials
+//     when the spec has no WithdrawalPubkey
+//   - spec: withdrawal credential type and target
+//
+// Returns an error if the withdrawal type is not supported; the error is CRITICAL.
+func withdrawalCredentials(validatorPubkey bls.PublicKey, spec consensus.ValidatorSpec) ([]byte, error) {
+	withdrawalCreds := make([]byte, 32)
 
-		// Compute deposit data root - two-level merkle tree structure:
-		//
-		// Level 1: Individual deposit data (this computation)
-		//     [Hash Tree Root] ← HashTreeRoot() returns this
-		//     /              \
-		//   [hash(pubkey,   [hash(amount,
-		//    withdrawal)]    signature)]
-		//
-		// Level 2: Beacon state deposit tree (built by GenerateGenesisStateFromDepositData)
-		//       [Deposit Tree Root]
-		//      /                   \
-		//   [Hash]               [Hash]
-		//   /    \               /    \
-		// [Root0][Root1]     [Root2][Root3]
-		//  ^LEAF  ^LEAF       ^LEAF  ^LEAF
-		//
-		// Note: depositDataRoots[i] is the "root" of level 1, but becomes a "leaf"
-		// in level 2. It's called a root because HashTreeRoot() returns it.
-		root, err := depositData.HashTreeRoot()
-		if err != nil {
-			return nil, nil, fmt.Errorf("hash tree root: %w", err)
+	switch spec.WithdrawalType {
+	case consensus.BLSWithdrawal:
+		withdrawalPubkey := spec.WithdrawalPubkey
+		if withdrawalPubkey == nil {
+			withdrawalPubkey = validatorPubkey
 		}
+		h := hash.Hash(withdrawalPubkey.Marshal())
+		copy(withdrawalCreds, h[:])
+		withdrawalCreds[0] = params.BeaconConfig().BLSWithdrawalPrefixByte // 0x00
+	case consensus.ExecutionWithdrawal:
+		withdrawalCreds[0] = params.BeaconConfig().ETH1AddressWithdrawalPrefixByte // 0x01
+		// Copy 20-byte Ethereum address into bytes 12-31 (bytes 1-11 remain zero padding)
+		copy(withdrawalCreds[12:], spec.WithdrawalAddress.Bytes())
+	case consensus.CompoundingWithdrawal:
+		withdrawalCreds[0] = params.BeaconConfig().CompoundingWithdrawalPrefixByte // 0x02
+		copy(withdrawalCreds[12:], spec.WithdrawalAddress.Bytes())
+	default:
+		return nil, fmt.Errorf("unsupported withdrawal type %s", spec.WithdrawalType)
+	}
 
-		depositDataItems[i] = depositData
-		depositDataRoots[i] = root[:] // Root of deposit data, leaf of deposit tree
+	return withdrawalCreds, nil
+}
+
+// signDepositData creates and signs the deposit data of a single validator.
+//
+// Args:
+//   - secretKey: the validator's BLS secret key
+//   - withdrawalCreds: 32-byte withdrawal credentials (see withdrawalCredentials)
+//   - amount: deposit amount in Gwei
+//
+// Returns:
+//   - depositData: the signed deposit data
+//   - root: hash tree root of the deposit data (leaf in the deposit tree)
+//   - error: Any error encountered during signing, all errors are CRITICAL.
+func signDepositData(secretKey bls.SecretKey, withdrawalCreds []byte, amount uint64) (*ethpb.Deposit_Data, [32]byte, error) {
+	// Create deposit message (unsigned data to be staked)
+	depositMsg := &ethpb.DepositMessage{
+		PublicKey:             secretKey.PublicKey().Marshal(),
+		WithdrawalCredentials: withdrawalCreds,
+		Amount:                amount,
 	}
 
-	return depositDataItems, depositDataRoots, nil
+	// Sign the deposit message to prove ownership of the validator key
+	// Domain separates signature purposes (deposit vs attestation vs block proposal)
+	domain, err := signing.ComputeDomain(
+		params.BeaconConfig().DomainDeposit,
+		params.BeaconConfig().GenesisForkVersion,
+		params.BeaconConfig().ZeroHash[:],
+	)
+	if err != nil {
+		return nil, [32]byte{}, fmt.Errorf("compute domain: %w", err)
+	}
+
+	// Compute signing root: hash(depositMsg + domain) for signature
+	signingRoot, err := signing.ComputeSigningRoot(depositMsg, domain)
+	if err != nil {
+		return nil, [32]byte{}, fmt.Errorf("compute signing root: %w", err)
+	}
+
+	// Sign with validator's BLS secret key to prove ownership
+	signature := secretKey.Sign(signingRoot[:])
+
+	// Create final deposit data (unsigned message + signature)
+	// This proves: "I own this validator key and authorize this configuration"
+	depositData := &ethpb.Deposit_Data{
+		PublicKey:             depositMsg.PublicKey,
+		WithdrawalCredentials: depositMsg.WithdrawalCredentials,
+		Amount:                depositMsg.Amount,
+		Signature:             signature.Marshal(),
+	}
+
+	// Compute deposit data root - two-level merkle tree structure:
+	//
+	// Level 1: Individual deposit data (this computation)
+	//     [Hash Tree Root] ← HashTreeRoot() returns this
+	//     /              \
+	//   [hash(pubkey,   [hash(amount,
+	//    withdrawal)]    signature)]
+	//
+	// Level 2: Beacon state deposit tree (built by GenerateGenesisStateFromDepositData)
+	//       [Deposit Tree Root]
+	//      /                   \
+	//   [Hash]               [Hash]
+	//   /    \               /    \
+	// [Root0][Root1]     [Root2][Root3]
+	//  ^LEAF  ^LEAF       ^LEAF  ^LEAF
+	//
+	// Note: the returned root is the "root" of level 1, but becomes a "leaf"
+	// in level 2. It's called a root because HashTreeRoot() returns it.
+	root, err := depositData.HashTreeRoot()
+	if err != nil {
+		return nil, [32]byte{}, fmt.Errorf("hash tree root: %w", err)
+	}
+
+	return depositData, root, nil
 }
 
 // GenerateValidatorKeys generates deterministic BLS validator keys for testing.
@@ -1,10 +1,13 @@
 package prysm_test
 
 import (
+	"crypto/sha256"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	ethpb "github.com/prysmaticlabs/prysm/v5/proto/prysm/v1alpha1"
 	"github.com/stretchr/testify/require"
 	"github.com/thep2p/go-eth-localnet/internal/consensus"
 	"github.com/thep2p/go-eth-localnet/internal/consensus/prysm"
@@ -126,6 +129,51 @@ func TestGenerateGenesisStateValidation(t *testing.T) {
 			}(),
 			wantError: "at least one validator",
 		},
+		{
+			name: "mismatched validator specs count",
+			cfg: func() consensus.Config {
+				cfg := baseConfig
+				cfg.Validators = []consensus.ValidatorSpec{
+					{WithdrawalType: consensus.BLSWithdrawal},
+					{WithdrawalType: consensus.BLSWithdrawal},
+				}
+				return cfg
+			}(),
+			wantError: "validator specs count",
+		},
+		{
+			name: "missing withdrawal address for compounding credentials",
+			cfg: func() consensus.Config {
+				cfg := baseConfig
+				cfg.Validators = []consensus.ValidatorSpec{
+					{WithdrawalType: consensus.CompoundingWithdrawal},
+				}
+				return cfg
+			}(),
+			wantError: "requires a withdrawal address",
+		},
+		{
+			name: "unsupported withdrawal type",
+			cfg: func() consensus.Config {
+				cfg := baseConfig
+				cfg.Validators = []consensus.ValidatorSpec{
+					{WithdrawalType: 0x03, WithdrawalAddress: withdrawalAddr},
+				}
+				return cfg
+			}(),
+			wantError: "unsupported withdrawal type 0x03",
+		},
+		{
+			name: "balance below minimum deposit",
+			cfg: func() consensus.Config {
+				cfg := baseConfig
+				cfg.Validators = []consensus.ValidatorSpec{
+					{WithdrawalType: consensus.ExecutionWithdrawal, WithdrawalAddress: withdrawalAddr, Balance: 1},
+				}
+				return cfg
+			}(),
+			wantError: "below minimum deposit amount",
+		},
 		{
 			name: "mismatched withdrawal addresses count",
 			cfg: func() consensus.Config {
@@ -135,6 +183,15 @@ func TestGenerateGenesisStateValidation(t *testing.T) {
 			}(),
 			wantError: "withdrawal addresses count",
 		},
+		{
+			name: "invalid checkpoint sync url",
+			cfg: func() consensus.Config {
+				cfg := baseConfig
+				cfg.CheckpointSyncURL = "not a url"
+				return cfg
+			}(),
+			wantError: "CheckpointSyncURL",
+		},
 	}
 
 	for _, tt := range tests {
@@ -181,6 +238,91 @@ func TestGenerateGenesisState(t *testing.T) {
 
 }
 
+// TestGenerateGenesisStateValidatorSpecs verifies that per-validator specs
+// produce the requested withdrawal credentials and balances in the genesis state,
+// with Electra effective balances and activations.
+func TestGenerateGenesisStateValidatorSpecs(t *testing.T) {
+	t.Parallel()
+
+	cfg := unittest.ConsensusConfigFixture(t, 6)
+	withdrawalAddrs := cfg.WithdrawalAddresses
+	blsWithdrawalKeys, err := prysm.GenerateValidatorKeys(7)
+	require.NoError(t, err)
+
+	const gwei = uint64(1_000_000_000)
+	cfg.WithdrawalAddresses = nil
+	cfg.Validators = []consensus.ValidatorSpec{
+		// 0x00 credentials derived from the validator's own key, default balance
+		{WithdrawalType: consensus.BLSWithdrawal},
+		// 0x00 credentials derived from a separate withdrawal key, below 32 ETH
+		{WithdrawalType: consensus.BLSWithdrawal, WithdrawalPubkey: blsWithdrawalKeys[6].PublicKey(), Balance: 16 * gwei},
+		// 0x01 credentials above 32 ETH
+		{WithdrawalType: consensus.ExecutionWithdrawal, WithdrawalAddress: withdrawalAddrs[2], Balance: 40 * gwei},
+		// 0x02 compounding credentials below 32 ETH
+		{WithdrawalType: consensus.CompoundingWithdrawal, WithdrawalAddress: withdrawalAddrs[3], Balance: 24 * gwei},
+		// 0x02 compounding credentials above 32 ETH
+		{WithdrawalType: consensus.CompoundingWithdrawal, WithdrawalAddress: withdrawalAddrs[4], Balance: 1024 * gwei},
+		// 0x02 compounding credentials above the Electra maximum effective balance
+		{WithdrawalType: consensus.CompoundingWithdrawal, WithdrawalAddress: withdrawalAddrs[5], Balance: 3000 * gwei},
+	}
+	cfg.FeeRecipient = withdrawalAddrs[2]
+
+	genesisState, err := prysm.GenerateGenesisState(cfg)
+	require.NoError(t, err)
+
+	st := &ethpb.BeaconStateElectra{}
+	require.NoError(t, st.UnmarshalSSZ(genesisState))
+	require.Len(t, st.Validators, 6)
+	require.Len(t, st.Balances, 6)
+
+	// 0x00: prefix byte followed by sha256(pubkey)[1:]
+	ownHash := sha256.Sum256(cfg.ValidatorKeys[0].PublicKey().Marshal())
+	require.Equal(t, byte(0x00), st.Validators[0].WithdrawalCredentials[0])
+	require.Equal(t, ownHash[1:], st.Validators[0].WithdrawalCredentials[1:])
+	require.Equal(t, 32*gwei, st.Balances[0])
+	require.Equal(t, 32*gwei, st.Validators[0].EffectiveBalance)
+
+	withdrawalHash := sha256.Sum256(blsWithdrawalKeys[6].PublicKey().Marshal())
+	require.Equal(t, byte(0x00), st.Validators[1].WithdrawalCredentials[0])
+	require.Equal(t, withdrawalHash[1:], st.Validators[1].WithdrawalCredentials[1:])
+	require.Equal(t, 16*gwei, st.Balances[1])
+	require.Equal(t, 16*gwei, st.Validators[1].EffectiveBalance)
+
+	// 0x01 and 0x02: prefix byte, 11 zero bytes, 20-byte address
+	require.Equal(t, byte(0x01), st.Validators[2].WithdrawalCredentials[0])
+	require.Equal(t, make([]byte, 11), st.Validators[2].WithdrawalCredentials[1:12])
+	require.Equal(t, withdrawalAddrs[2].Bytes(), st.Validators[2].WithdrawalCredentials[12:])
+	require.Equal(t, 40*gwei, st.Balances[2])
+	require.Equal(t, 32*gwei, st.Validators[2].EffectiveBalance, "0x01 effective balance should be capped at 32 ETH")
+
+	for i := 3; i < 6; i++ {
+		require.Equal(t, byte(0x02), st.Validators[i].WithdrawalCredentials[0])
+		require.Equal(t, make([]byte, 11), st.Validators[i].WithdrawalCredentials[1:12])
+		require.Equal(t, withdrawalAddrs[i].Bytes(), st.Validators[i].WithdrawalCredentials[12:])
+	}
+	require.Equal(t, 24*gwei, st.Balances[3])
+	require.Equal(t, 24*gwei, st.Validators[3].EffectiveBalance)
+	require.Equal(t, 1024*gwei, st.Balances[4])
+	require.Equal(t, 1024*gwei, st.Validators[4].EffectiveBalance, "0x02 balance above 32 ETH should count in full")
+	require.Equal(t, 3000*gwei, st.Balances[5])
+	require.Equal(t, 2048*gwei, st.Validators[5].EffectiveBalance, "0x02 effective balance should be capped at 2048 ETH")
+
+	// Validators with an effective balance of at least 32 ETH are active from genesis
+	farFuture := params.BeaconConfig().FarFutureEpoch
+	for i, active := range []bool{true, false, true, false, true, true} {
+		if active {
+			require.Zero(t, st.Validators[i].ActivationEpoch, "validator %d should be active at genesis", i)
+		} else {
+			require.Equal(t, farFuture, st.Validators[i].ActivationEpoch, "validator %d should not be active at genesis", i)
+		}
+	}
+
+	// The genesis deposits are recorded and already processed
+	require.Equal(t, uint64(6), st.Eth1Data.DepositCount)
+	require.Equal(t, uint64(6), st.Eth1DepositIndex)
+	require.Equal(t, params.BeaconConfig().UnsetDepositRequestsStartIndex, st.DepositRequestsStartIndex)
+}
+
 // TestDeriveGenesisRootValidation verifies genesis root validation.
 func TestDeriveGenesisRootValidation(t *testing.T) {
 	t.Parallel()
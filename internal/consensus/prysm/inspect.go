@@ -0,0 +1,172 @@
+package prysm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/prysmaticlabs/prysm/v5/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/v5/encoding/ssz/detect"
+	"github.com/prysmaticlabs/prysm/v5/runtime/version"
+	"github.com/thep2p/go-eth-localnet/internal/consensus"
+)
+
+// GenesisInfo is a decoded summary of an SSZ-encoded genesis beacon state.
+//
+// It contains the fields that must agree between nodes sharing a network,
+// and is used to debug mismatched genesis between nodes.
+type GenesisInfo struct {
+	// GenesisTime is the beacon chain genesis time.
+	GenesisTime time.Time `json:"genesis_time"`
+
+	// ForkVersion is the current fork version of the state.
+	ForkVersion hexutil.Bytes `json:"fork_version"`
+
+	// PreviousForkVersion is the previous fork version of the state.
+	PreviousForkVersion hexutil.Bytes `json:"previous_fork_version"`
+
+	// GenesisValidatorsRoot is the hash tree root of the genesis validator registry.
+	// Together with the fork version it forms every signing domain.
+	GenesisValidatorsRoot common.Hash `json:"genesis_validators_root"`
+
+	// StateRoot is the hash tree root of the whole state (see DeriveGenesisRoot).
+	StateRoot common.Hash `json:"state_root"`
+
+	// Eth1Data is the execution deposit snapshot embedded in the state.
+	Eth1Data Eth1DataInfo `json:"eth1_data"`
+
+	// ExecutionBlockHash is the block hash of the latest execution payload header,
+	// the execution genesis block for a genesis state. Zero for pre-Bellatrix states.
+	ExecutionBlockHash common.Hash `json:"execution_block_hash"`
+
+	// ValidatorCount is the number of validators in the registry.
+	ValidatorCount int `json:"validator_count"`
+
+	// Validators lists every validator in registry order.
+	Validators []ValidatorInfo `json:"validators"`
+}
+
+// Eth1DataInfo is the deposit snapshot of the execution chain stored in the beacon state.
+type Eth1DataInfo struct {
+	// DepositRoot is the root of the deposit merkle tree.
+	DepositRoot common.Hash `json:"deposit_root"`
+	// DepositCount is the number of deposits in the tree.
+	DepositCount uint64 `json:"deposit_count"`
+	// BlockHash is the execution block hash the snapshot was taken at.
+	BlockHash common.Hash `json:"block_hash"`
+}
+
+// ValidatorInfo describes a single validator of the genesis state.
+type ValidatorInfo struct {
+	// Index is the validator index in the registry.
+	Index int `json:"index"`
+	// Pubkey is the 48-byte BLS public key.
+	Pubkey hexutil.Bytes `json:"pubkey"`
+	// WithdrawalCredentials are the raw 32-byte withdrawal credentials.
+	WithdrawalCredentials hexutil.Bytes `json:"withdrawal_credentials"`
+	// WithdrawalType is the credential prefix, e.g. "0x01".
+	WithdrawalType string `json:"withdrawal_type"`
+	// WithdrawalAddress is the execution address for 0x01 and 0x02 credentials, nil otherwise.
+	WithdrawalAddress *common.Address `json:"withdrawal_address,omitempty"`
+	// Balance is the validator balance in Gwei.
+	Balance uint64 `json:"balance"`
+	// EffectiveBalance is the validator effective balance in Gwei.
+	EffectiveBalance uint64 `json:"effective_balance"`
+}
+
+// InspectGenesisState decodes an SSZ-encoded genesis state into a GenesisInfo.
+//
+// Args:
+//   - genesisState: SSZ-encoded (entire) genesis state bytes (output from GenerateGenesisState)
+//
+// Returns:
+//   - decoded genesis summary
+//   - Error if the genesis state is invalid or corrupted
+//
+// All errors are CRITICAL and indicate the genesis state is invalid or corrupted.
+func InspectGenesisState(genesisState []byte) (*GenesisInfo, error) {
+	st, err := unmarshalGenesisState(genesisState)
+	if err != nil {
+		return nil, err
+	}
+
+	stateRoot, err := st.HashTreeRoot(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("compute hash tree root: %w", err)
+	}
+
+	info := &GenesisInfo{
+		GenesisTime:           time.Unix(int64(st.GenesisTime()), 0).UTC(),
+		GenesisValidatorsRoot: common.BytesToHash(st.GenesisValidatorsRoot()),
+		StateRoot:             common.BytesToHash(stateRoot[:]),
+		ValidatorCount:        st.NumValidators(),
+		Validators:            make([]ValidatorInfo, 0, st.NumValidators()),
+	}
+
+	if fork := st.Fork(); fork != nil {
+		info.ForkVersion = fork.CurrentVersion
+		info.PreviousForkVersion = fork.PreviousVersion
+	}
+
+	if eth1Data := st.Eth1Data(); eth1Data != nil {
+		info.Eth1Data = Eth1DataInfo{
+			DepositRoot:  common.BytesToHash(eth1Data.DepositRoot),
+			DepositCount: eth1Data.DepositCount,
+			BlockHash:    common.BytesToHash(eth1Data.BlockHash),
+		}
+	}
+
+	if st.Version() >= version.Bellatrix {
+		header, err := st.LatestExecutionPayloadHeader()
+		if err != nil {
+			return nil, fmt.Errorf("latest execution payload header: %w", err)
+		}
+		info.ExecutionBlockHash = common.BytesToHash(header.BlockHash())
+	}
+
+	balances := st.Balances()
+	for i, v := range st.Validators() {
+		vi := ValidatorInfo{
+			Index:                 i,
+			Pubkey:                v.PublicKey,
+			WithdrawalCredentials: v.WithdrawalCredentials,
+			EffectiveBalance:      v.EffectiveBalance,
+		}
+		if i < len(balances) {
+			vi.Balance = balances[i]
+		}
+		if len(v.WithdrawalCredentials) == 32 {
+			wt := consensus.WithdrawalType(v.WithdrawalCredentials[0])
+			vi.WithdrawalType = wt.String()
+			if wt == consensus.ExecutionWithdrawal || wt == consensus.CompoundingWithdrawal {
+				addr := common.BytesToAddress(v.WithdrawalCredentials[12:])
+				vi.WithdrawalAddress = &addr
+			}
+		}
+		info.Validators = append(info.Validators, vi)
+	}
+
+	return info, nil
+}
+
+// unmarshalGenesisState decodes SSZ-encoded genesis state bytes into a beacon state.
+// All errors are CRITICAL and indicate the genesis state is invalid or corrupted.
+func unmarshalGenesisState(genesisState []byte) (state.BeaconState, error) {
+	if len(genesisState) == 0 {
+		return nil, fmt.Errorf("genesis state is empty")
+	}
+
+	// Detect the fork from the state's fork version, then unmarshal the matching state type
+	vu, err := detect.FromState(genesisState)
+	if err != nil {
+		return nil, fmt.Errorf("detect genesis state fork: %w", err)
+	}
+	st, err := vu.UnmarshalBeaconState(genesisState)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal ssz: %w", err)
+	}
+
+	return st, nil
+}
@@ -0,0 +1,84 @@
+package prysm_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/thep2p/go-eth-localnet/internal/consensus"
+	"github.com/thep2p/go-eth-localnet/internal/consensus/prysm"
+	"github.com/thep2p/go-eth-localnet/internal/node"
+	"github.com/thep2p/go-eth-localnet/internal/unittest"
+)
+
+// TestInspectGenesisState verifies that inspection reports the fields the
+// genesis state was generated with.
+func TestInspectGenesisState(t *testing.T) {
+	t.Parallel()
+
+	cfg := unittest.ConsensusConfigFixture(t, 3)
+	withdrawalAddrs := cfg.WithdrawalAddresses
+	cfg.WithdrawalAddresses = nil
+	cfg.Validators = []consensus.ValidatorSpec{
+		{WithdrawalType: consensus.BLSWithdrawal},
+		{WithdrawalType: consensus.ExecutionWithdrawal, WithdrawalAddress: withdrawalAddrs[1]},
+		{WithdrawalType: consensus.CompoundingWithdrawal, WithdrawalAddress: withdrawalAddrs[2], Balance: 1_024_000_000_000},
+	}
+
+	genesisState, err := prysm.GenerateGenesisState(cfg)
+	require.NoError(t, err)
+
+	info, err := prysm.InspectGenesisState(genesisState)
+	require.NoError(t, err)
+
+	require.Equal(t, cfg.GenesisTime.Unix(), info.GenesisTime.Unix())
+	require.Len(t, info.ForkVersion, 4)
+	require.NotZero(t, info.GenesisValidatorsRoot)
+	require.Equal(t, 3, info.ValidatorCount)
+	require.Len(t, info.Validators, 3)
+	require.Equal(t, uint64(3), info.Eth1Data.DepositCount)
+	require.NotZero(t, info.Eth1Data.DepositRoot)
+	// Anchored to the default execution genesis
+	elGenesis := node.NewGenesis()
+	elGenesis.Timestamp = uint64(cfg.GenesisTime.Unix())
+	require.Equal(t, elGenesis.ToBlock().Hash(), info.Eth1Data.BlockHash)
+	require.Equal(t, info.Eth1Data.BlockHash, info.ExecutionBlockHash)
+
+	root, err := prysm.DeriveGenesisRoot(genesisState)
+	require.NoError(t, err)
+	require.Equal(t, root, info.StateRoot)
+
+	for i, v := range info.Validators {
+		require.Equal(t, i, v.Index)
+		require.Equal(t, cfg.ValidatorKeys[i].PublicKey().Marshal(), []byte(v.Pubkey))
+		require.Len(t, v.WithdrawalCredentials, 32)
+	}
+
+	require.Equal(t, "0x00", info.Validators[0].WithdrawalType)
+	require.Nil(t, info.Validators[0].WithdrawalAddress)
+	require.Equal(t, uint64(32_000_000_000), info.Validators[0].Balance)
+
+	require.Equal(t, "0x01", info.Validators[1].WithdrawalType)
+	require.NotNil(t, info.Validators[1].WithdrawalAddress)
+	require.Equal(t, withdrawalAddrs[1], *info.Validators[1].WithdrawalAddress)
+
+	require.Equal(t, "0x02", info.Validators[2].WithdrawalType)
+	require.NotNil(t, info.Validators[2].WithdrawalAddress)
+	require.Equal(t, withdrawalAddrs[2], *info.Validators[2].WithdrawalAddress)
+	require.Equal(t, uint64(1_024_000_000_000), info.Validators[2].Balance)
+	require.Equal(t, uint64(1_024_000_000_000), info.Validators[2].EffectiveBalance)
+}
+
+// TestInspectGenesisStateValidation verifies inspection rejects empty and corrupted states.
+func TestInspectGenesisStateValidation(t *testing.T) {
+	t.Parallel()
+
+	info, err := prysm.InspectGenesisState(nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "empty")
+	require.Nil(t, info)
+
+	info, err = prysm.InspectGenesisState([]byte{0x01, 0x02, 0x03})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "detect genesis state fork")
+	require.Nil(t, info)
+}
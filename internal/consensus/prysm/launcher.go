@@ -0,0 +1,368 @@
+package prysm
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/go-playground/validator/v10"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/rs/zerolog"
+	"github.com/thep2p/go-eth-localnet/internal/consensus"
+	"github.com/thep2p/go-eth-localnet/internal/engine"
+	"github.com/thep2p/go-eth-localnet/internal/node"
+	"github.com/thep2p/go-eth-localnet/internal/utils"
+)
+
+const (
+	// BeaconChainBinary is the name of the prysm beacon node binary.
+	BeaconChainBinary = "beacon-chain"
+	// ValidatorBinary is the name of the prysm validator client binary.
+	ValidatorBinary = "validator"
+
+	// ShutdownTimeout is the maximum time a beacon node or validator client gets
+	// to exit after its context is cancelled before it is killed.
+	ShutdownTimeout = 10 * time.Second
+)
+
+// Files Launcher.Launch writes into the data directory of a beacon node, next
+// to the sync origin files (see SyncOrigin.Write).
+const (
+	// ChainConfigFile holds the beacon chain config (see ChainConfig) in YAML.
+	ChainConfigFile = "config.yaml"
+	// P2PKeyFile holds the hex-encoded P2P identity key.
+	P2PKeyFile = "p2p.key"
+	// BeaconLogFile receives the output of the beacon node.
+	BeaconLogFile = "beacon.log"
+	// ValidatorLogFile receives the output of the validator client.
+	ValidatorLogFile = "validator.log"
+)
+
+// Launcher starts prysm beacon nodes, and the validator clients attached to
+// them, as child processes of the prysm beacon-chain and validator binaries.
+type Launcher struct {
+	logger          zerolog.Logger
+	beaconBinary    string
+	validatorBinary string
+}
+
+// NewLauncher returns a Launcher running the given beacon-chain and validator
+// binaries. Pass BeaconChainBinary and ValidatorBinary to look them up in PATH.
+func NewLauncher(logger zerolog.Logger, beaconBinary string, validatorBinary string) *Launcher {
+	return &Launcher{
+		logger:          logger.With().Str("component", "prysm-launcher").Logger(),
+		beaconBinary:    beaconBinary,
+		validatorBinary: validatorBinary,
+	}
+}
+
+// BeaconNode is a running prysm beacon node and, if it has validator keys, its
+// validator client. Both stop on Stop or when the context passed to
+// Launcher.Launch is cancelled.
+type BeaconNode struct {
+	origin   *SyncOrigin
+	endpoint string
+	p2pPort  int
+	peerID   string
+	cancel   context.CancelFunc
+	done     []chan struct{}
+}
+
+// Endpoint returns the Beacon API URL of the node, e.g. http://127.0.0.1:3500.
+func (b *BeaconNode) Endpoint() string {
+	return b.endpoint
+}
+
+// SyncOrigin returns the genesis state and checkpoint the node started from.
+func (b *BeaconNode) SyncOrigin() *SyncOrigin {
+	return b.origin
+}
+
+// PeerAddress returns the multiaddr other beacon nodes of the localnet reach the
+// node at, for consensus.Config.StaticPeers.
+func (b *BeaconNode) PeerAddress() string {
+	return fmt.Sprintf("/ip4/127.0.0.1/tcp/%d/p2p/%s", b.p2pPort, b.peerID)
+}
+
+// Stop stops the beacon node and its validator client and waits for them to exit.
+func (b *BeaconNode) Stop() {
+	b.cancel()
+	b.Done()
+}
+
+// Done blocks until the beacon node and its validator client have exited.
+func (b *BeaconNode) Done() {
+	for _, done := range b.done {
+		<-done
+	}
+}
+
+// Launch starts a prysm beacon node for cfg and, if cfg has validator keys, a
+// validator client that performs their duties through it.
+//
+// The node starts from the sync origin resolved by ResolveSyncOrigin: the
+// finalized checkpoint of cfg.CheckpointSyncURL when set, so a node joining a
+// running localnet catches up from there instead of from genesis, and otherwise
+// the genesis state. It connects to the EL at cfg.EngineEndpoint, serves the
+// Beacon API on cfg.BeaconPort and gRPC on cfg.RPCPort, and peers with
+// cfg.StaticPeers on cfg.P2PPort of the loopback interface. Without
+// cfg.Bootnodes discovery is disabled. The node waits for one peer per static
+// peer before it starts syncing.
+//
+// Validator keys must be the deterministic interop keys of GenerateValidatorKeys,
+// which the validator client derives itself; the client needs cfg.RPCPort.
+//
+// Launch returns once the processes are started; wait for the node with
+// consensus.WaitForBeaconReady. Their output goes to BeaconLogFile and
+// ValidatorLogFile in cfg.DataDir.
+//
+// Args:
+//   - ctx: context for the HTTP requests of the sync origin; cancelling it stops the node
+//   - cfg: consensus configuration of the node
+//
+// Returns the running node. All errors are CRITICAL and indicate the node did not start.
+func (l *Launcher) Launch(ctx context.Context, cfg consensus.Config) (*BeaconNode, error) {
+	if err := validator.New().Struct(cfg); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	var validatorArgs []string
+	if len(cfg.ValidatorKeys) > 0 {
+		args, err := interopValidatorArgs(cfg)
+		if err != nil {
+			return nil, err
+		}
+		validatorArgs = args
+	}
+
+	origin, err := ResolveSyncOrigin(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("resolve sync origin: %w", err)
+	}
+	originFlags, err := origin.Write(cfg.DataDir)
+	if err != nil {
+		return nil, fmt.Errorf("write sync origin: %w", err)
+	}
+
+	chainConfigPath := filepath.Join(cfg.DataDir, ChainConfigFile)
+	if err := os.WriteFile(chainConfigPath, params.ConfigToYaml(ChainConfig(cfg)), 0644); err != nil {
+		return nil, fmt.Errorf("write chain config: %w", err)
+	}
+	jwtSecret, err := engine.DecodeJWTSecret(cfg.JWTSecret)
+	if err != nil {
+		return nil, fmt.Errorf("invalid jwt secret: %w", err)
+	}
+	jwtPath, err := node.WriteJWTSecret(cfg.DataDir, jwtSecret[:])
+	if err != nil {
+		return nil, fmt.Errorf("write jwt secret: %w", err)
+	}
+
+	args := []string{
+		"--accept-terms-of-use",
+		"--datadir=" + filepath.Join(cfg.DataDir, "beacon"),
+		"--chain-config-file=" + chainConfigPath,
+		"--execution-endpoint=" + cfg.EngineEndpoint,
+		"--jwt-secret=" + jwtPath,
+		"--contract-deployment-block=0",
+		fmt.Sprintf("--http-port=%d", cfg.BeaconPort),
+		"--p2p-local-ip=127.0.0.1",
+		fmt.Sprintf("--p2p-tcp-port=%d", cfg.P2PPort),
+		fmt.Sprintf("--p2p-udp-port=%d", cfg.P2PPort),
+		fmt.Sprintf("--min-sync-peers=%d", len(cfg.StaticPeers)),
+		"--minimum-peers-per-subnet=0",
+		"--disable-quic",
+		"--disable-monitoring",
+	}
+	args = append(args, originFlags...)
+	if cfg.RPCPort != 0 {
+		args = append(args, fmt.Sprintf("--rpc-port=%d", cfg.RPCPort))
+	}
+	if cfg.FeeRecipient != (common.Address{}) {
+		args = append(args, "--suggested-fee-recipient="+cfg.FeeRecipient.Hex())
+	}
+
+	// The key is set rather than left to prysm, so the peer ID is known up front.
+	p2pKey := cfg.PrivateKey
+	if p2pKey == nil {
+		p2pKey, err = crypto.GenerateKey()
+		if err != nil {
+			return nil, fmt.Errorf("generate p2p key: %w", err)
+		}
+	}
+	keyPath := filepath.Join(cfg.DataDir, P2PKeyFile)
+	if err := os.WriteFile(keyPath, []byte(hex.EncodeToString(crypto.FromECDSA(p2pKey))), 0600); err != nil {
+		return nil, fmt.Errorf("write p2p key: %w", err)
+	}
+	args = append(args, "--p2p-priv-key="+keyPath)
+	for _, peer := range cfg.StaticPeers {
+		args = append(args, "--peer="+peer)
+	}
+	if len(cfg.Bootnodes) == 0 {
+		args = append(args, "--no-discovery")
+	}
+	for _, bootnode := range cfg.Bootnodes {
+		args = append(args, "--bootstrap-node="+bootnode)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	b := &BeaconNode{
+		origin:   origin,
+		endpoint: utils.LocalAddress(cfg.BeaconPort),
+		p2pPort:  cfg.P2PPort,
+		peerID:   PeerID(&p2pKey.PublicKey),
+		cancel:   cancel,
+	}
+	beaconDone, err := l.start(ctx, BeaconChainBinary, l.beaconBinary, args, filepath.Join(cfg.DataDir, BeaconLogFile))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	b.done = append(b.done, beaconDone)
+
+	if validatorArgs != nil {
+		validatorArgs = append(validatorArgs,
+			"--accept-terms-of-use",
+			"--datadir="+filepath.Join(cfg.DataDir, "validator"),
+			"--chain-config-file="+chainConfigPath,
+			"--disable-monitoring",
+		)
+		if cfg.FeeRecipient != (common.Address{}) {
+			validatorArgs = append(validatorArgs, "--suggested-fee-recipient="+cfg.FeeRecipient.Hex())
+		}
+		validatorDone, err := l.start(ctx, ValidatorBinary, l.validatorBinary, validatorArgs, filepath.Join(cfg.DataDir, ValidatorLogFile))
+		if err != nil {
+			b.Stop()
+			return nil, err
+		}
+		b.done = append(b.done, validatorDone)
+	}
+
+	l.logger.Info().
+		Str("beacon_endpoint", b.endpoint).
+		Uint64("start_slot", origin.StartSlot()).
+		Int("validator_count", len(cfg.ValidatorKeys)).
+		Msg("beacon node started")
+	return b, nil
+}
+
+// interopValidatorArgs returns the validator client flags that derive the
+// validator keys of cfg, which must be the first interop keys.
+func interopValidatorArgs(cfg consensus.Config) ([]string, error) {
+	if cfg.RPCPort == 0 {
+		return nil, fmt.Errorf("validator client needs the beacon node rpc port")
+	}
+	interopKeys, err := GenerateValidatorKeys(len(cfg.ValidatorKeys))
+	if err != nil {
+		return nil, fmt.Errorf("generate interop keys: %w", err)
+	}
+	for i, key := range cfg.ValidatorKeys {
+		if !bytes.Equal(key.Marshal(), interopKeys[i].Marshal()) {
+			return nil, fmt.Errorf("validator key %d is not interop key %d (see GenerateValidatorKeys)", i, i)
+		}
+	}
+
+	return []string{
+		fmt.Sprintf("--interop-num-validators=%d", len(cfg.ValidatorKeys)),
+		"--interop-start-index=0",
+		fmt.Sprintf("--beacon-rpc-provider=127.0.0.1:%d", cfg.RPCPort),
+	}, nil
+}
+
+// start runs binary with args until ctx is cancelled, writing its output to logPath.
+// On cancellation the process is interrupted and killed after ShutdownTimeout.
+// Returns a channel closed once the process has exited.
+func (l *Launcher) start(ctx context.Context, name string, binary string, args []string, logPath string) (chan struct{}, error) {
+	logFile, err := os.Create(logPath)
+	if err != nil {
+		return nil, fmt.Errorf("create %s log: %w", name, err)
+	}
+
+	cmd := exec.CommandContext(ctx, binary, args...)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	cmd.WaitDelay = ShutdownTimeout
+	if err := cmd.Start(); err != nil {
+		_ = logFile.Close()
+		return nil, fmt.Errorf("start %s: %w", name, err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer func() { _ = logFile.Close() }()
+
+		err := cmd.Wait()
+		if ctx.Err() == nil {
+			l.logger.Error().Err(err).Str("process", name).Str("log", logPath).Msg("process exited")
+			return
+		}
+		l.logger.Info().Str("process", name).Msg("process stopped")
+	}()
+	return done, nil
+}
+
+// PeerID returns the libp2p peer ID of a beacon node with the given P2P key:
+// the base58-encoded identity multihash of the protobuf-encoded compressed key.
+func PeerID(key *ecdsa.PublicKey) string {
+	pub := crypto.CompressPubkey(key)
+	// libp2p PublicKey message: field 1 (type) = 2 (secp256k1), field 2 (data) = pub
+	encoded := append([]byte{0x08, 0x02, 0x12, byte(len(pub))}, pub...)
+	// Identity multihash: code 0x00, length, digest
+	multihash := append([]byte{0x00, byte(len(encoded))}, encoded...)
+	return base58Encode(multihash)
+}
+
+// base58Alphabet is the bitcoin base58 alphabet libp2p encodes peer IDs with.
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// base58Encode encodes b in base58, keeping each leading zero byte as a leading "1".
+func base58Encode(b []byte) string {
+	x := new(big.Int).SetBytes(b)
+	radix := big.NewInt(int64(len(base58Alphabet)))
+	digit := new(big.Int)
+
+	var reversed []byte
+	for x.Sign() > 0 {
+		x.DivMod(x, radix, digit)
+		reversed = append(reversed, base58Alphabet[digit.Int64()])
+	}
+	for _, c := range b {
+		if c != 0 {
+			break
+		}
+		reversed = append(reversed, base58Alphabet[0])
+	}
+
+	encoded := make([]byte, len(reversed))
+	for i, c := range reversed {
+		encoded[len(reversed)-1-i] = c
+	}
+	return string(encoded)
+}
+
+// ChainConfig returns the beacon chain config of the network cfg belongs to:
+// LocalnetConfig with the chain ID, slot duration and deposit contract of cfg.
+// Every beacon node and validator client of the network loads it.
+func ChainConfig(cfg consensus.Config) *params.BeaconChainConfig {
+	chainConfig := LocalnetConfig()
+	chainConfig.DepositChainID = cfg.ChainID
+	chainConfig.DepositNetworkID = cfg.ChainID
+	if cfg.SecondsPerSlot != 0 {
+		chainConfig.SecondsPerSlot = cfg.SecondsPerSlot
+	}
+	if cfg.DepositContractAddress != (common.Address{}) {
+		chainConfig.DepositContractAddress = cfg.DepositContractAddress.Hex()
+	}
+	return chainConfig
+}
@@ -0,0 +1,243 @@
+package prysm_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/prysmaticlabs/prysm/v5/api/client/beacon"
+	"github.com/prysmaticlabs/prysm/v5/consensus-types/primitives"
+	"github.com/stretchr/testify/require"
+	"github.com/thep2p/go-eth-localnet/internal/consensus"
+	"github.com/thep2p/go-eth-localnet/internal/consensus/prysm"
+	"github.com/thep2p/go-eth-localnet/internal/node"
+	"github.com/thep2p/go-eth-localnet/internal/unittest"
+)
+
+const (
+	// secondsPerSlot shortens slots so the localnet finalizes within minutes.
+	secondsPerSlot = 2
+	// finalityTimeout bounds the wait for the first finalized epoch, which takes
+	// four epochs of 32 slots.
+	finalityTimeout = 8 * time.Minute
+	// catchUpTimeout bounds the wait for a checkpoint-synced node to reach the head.
+	// Initial sync only reaches the head its peer reported on connecting; the
+	// blocks since then are fetched one parent at a time from the gossiped head.
+	catchUpTimeout = 4 * time.Minute
+)
+
+// launchConfigFixture returns the consensus configuration of a beacon node
+// paired with the EL node at the given index, with its own data directory and ports.
+func launchConfigFixture(t *testing.T, manager *node.Manager, index int, cfg consensus.Config) consensus.Config {
+	t.Helper()
+
+	jwtSecret, err := manager.GetJWTSecret(index)
+	require.NoError(t, err)
+
+	cfg.DataDir = unittest.NewTempDir(t).Path()
+	cfg.BeaconPort = unittest.NewPort(t)
+	cfg.P2PPort = unittest.NewPort(t)
+	cfg.RPCPort = unittest.NewPort(t)
+	cfg.EngineEndpoint = manager.GetEngineEndpoint(index)
+	cfg.JWTSecret = jwtSecret
+	return cfg
+}
+
+// launchBeaconNode launches a beacon node that is stopped when the test finishes.
+func launchBeaconNode(t *testing.T, ctx context.Context, launcher *prysm.Launcher, cfg consensus.Config) *prysm.BeaconNode {
+	t.Helper()
+
+	beaconNode, err := launcher.Launch(ctx, cfg)
+	require.NoError(t, err)
+	t.Cleanup(
+		func() {
+			unittest.RequireCallMustReturnWithinTimeout(t, beaconNode.Stop, 2*prysm.ShutdownTimeout, "beacon node shutdown failed")
+		},
+	)
+	return beaconNode
+}
+
+// TestLaunch_CheckpointSync verifies that a beacon node added to a localnet
+// after finalization starts from the finalized checkpoint of an earlier node
+// instead of genesis, and catches up with the chain from there.
+func TestLaunch_CheckpointSync(t *testing.T) {
+	t.Parallel()
+	beaconBinary, validatorBinary := unittest.RequirePrysm(t)
+	if testing.Short() {
+		t.Skip("finalizing a localnet takes minutes")
+	}
+
+	// The EL genesis is stamped with the beacon genesis time, and predeploys the
+	// deposit contract prysm follows.
+	genesisTime := time.Unix(time.Now().Add(10*time.Second).Unix(), 0)
+	opts := []node.LaunchOption{
+		node.WithDepositContract(node.DefaultDepositContractAddress),
+		func(gen *core.Genesis) {
+			gen.Timestamp = uint64(genesisTime.Unix())
+		},
+	}
+	ctx, manager := unittest.StartExternalNodes(t, 1, opts...)
+	launcher := prysm.NewLauncher(unittest.Logger(t), beaconBinary, validatorBinary)
+
+	networkCfg := unittest.ConsensusConfigFixture(t, 4)
+	networkCfg.GenesisTime = genesisTime
+	networkCfg.SecondsPerSlot = secondsPerSlot
+	networkCfg.ExecutionGenesis = node.NewGenesis(opts...)
+	networkCfg.DepositContractAddress = node.DefaultDepositContractAddress
+
+	// The first node runs every validator from genesis.
+	first := launchBeaconNode(t, ctx, launcher, launchConfigFixture(t, manager, 0, networkCfg))
+	require.Equal(t, uint64(0), first.SyncOrigin().StartSlot())
+	require.NoError(t, manager.SetBeaconEndpoint(0, first.Endpoint()))
+	require.NoError(t, manager.WaitForPairReady(ctx, 0))
+
+	require.Eventually(t, func() bool {
+		checkpoints, err := consensus.GetBeaconFinalityCheckpoints(ctx, first.Endpoint())
+		return err == nil && checkpoints.Finalized.Epoch > 0
+	}, finalityTimeout, time.Second, "first beacon node did not finalize")
+
+	// The late node runs no validators and checkpoint-syncs from the first node.
+	require.NoError(t, manager.StartNode(ctx, false, []string{manager.GetNode(0).Server().NodeInfo().Enode}, opts...))
+	lateCfg := launchConfigFixture(t, manager, 1, networkCfg)
+	lateCfg.ValidatorKeys = nil
+	lateCfg.StaticPeers = []string{first.PeerAddress()}
+	lateCfg.CheckpointSyncURL = first.Endpoint()
+	late := launchBeaconNode(t, ctx, launcher, lateCfg)
+
+	origin := late.SyncOrigin()
+	require.NotNil(t, origin.Checkpoint)
+	require.NotZero(t, origin.Checkpoint.Epoch)
+	startSlot := origin.StartSlot()
+
+	// The late node catches up with the head of the first node and its EL
+	// executes the blocks since the checkpoint.
+	require.Eventually(t, func() bool {
+		firstStatus, err := consensus.GetBeaconSyncStatus(ctx, first.Endpoint())
+		if err != nil {
+			return false
+		}
+		lateStatus, err := consensus.GetBeaconSyncStatus(ctx, late.Endpoint())
+		if err != nil {
+			return false
+		}
+		return !lateStatus.IsSyncing && !lateStatus.IsOptimistic && !lateStatus.ELOffline &&
+			lateStatus.HeadSlot+1 >= firstStatus.HeadSlot
+	}, catchUpTimeout, time.Second, "late beacon node did not catch up")
+	require.NoError(t, manager.SetBeaconEndpoint(1, late.Endpoint()))
+	require.NoError(t, manager.WaitForPairReady(ctx, 1))
+
+	// The late node started from the checkpoint: it has the checkpoint block,
+	// but none of the blocks the first node produced before it.
+	firstClient, err := beacon.NewClient(first.Endpoint())
+	require.NoError(t, err)
+	lateClient, err := beacon.NewClient(late.Endpoint())
+	require.NoError(t, err)
+
+	_, err = lateClient.GetBlock(ctx, beacon.IdFromRoot(origin.Checkpoint.BlockRoot))
+	require.NoError(t, err)
+	preCheckpointBlocks := 0
+	for slot := primitives.Slot(1); uint64(slot) < startSlot; slot++ {
+		if _, err := firstClient.GetBlock(ctx, beacon.IdFromSlot(slot)); err != nil {
+			continue
+		}
+		preCheckpointBlocks++
+		_, err := lateClient.GetBlock(ctx, beacon.IdFromSlot(slot))
+		require.Error(t, err, "late beacon node has block at slot %d before checkpoint slot %d", slot, startSlot)
+	}
+	require.NotZero(t, preCheckpointBlocks)
+
+	finality, err := consensus.GetBeaconFinalityCheckpoints(ctx, late.Endpoint())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, finality.Finalized.Epoch, origin.Checkpoint.Epoch)
+}
+
+// TestLaunch_InvalidConfig verifies configurations a beacon node cannot start
+// with are rejected before anything is launched.
+func TestLaunch_InvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	launcher := prysm.NewLauncher(unittest.Logger(t), prysm.BeaconChainBinary, prysm.ValidatorBinary)
+	validCfg := unittest.ConsensusConfigFixture(t, 2)
+	validCfg.DataDir = unittest.NewTempDir(t).Path()
+	validCfg.RPCPort = 4001
+
+	otherKeys, err := prysm.GenerateValidatorKeys(3)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name   string
+		modify func(cfg *consensus.Config)
+		errMsg string
+	}{
+		{
+			name:   "missing engine endpoint",
+			modify: func(cfg *consensus.Config) { cfg.EngineEndpoint = "" },
+			errMsg: "invalid config",
+		},
+		{
+			name:   "validators without rpc port",
+			modify: func(cfg *consensus.Config) { cfg.RPCPort = 0 },
+			errMsg: "rpc port",
+		},
+		{
+			name:   "validator keys are not the first interop keys",
+			modify: func(cfg *consensus.Config) { cfg.ValidatorKeys = otherKeys[1:] },
+			errMsg: "is not interop key 0",
+		},
+		{
+			name: "checkpoint sync url unreachable",
+			modify: func(cfg *consensus.Config) {
+				cfg.ValidatorKeys = nil
+				cfg.CheckpointSyncURL = fmt.Sprintf("http://127.0.0.1:%d", unittest.NewPort(t))
+			},
+			errMsg: "resolve sync origin",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := validCfg
+			tc.modify(&cfg)
+
+			_, err := launcher.Launch(context.Background(), cfg)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.errMsg)
+		})
+	}
+}
+
+// TestPeerID verifies peer IDs match the libp2p encoding of secp256k1 keys.
+func TestPeerID(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	require.NoError(t, err)
+	require.Equal(t, "16Uiu2HAmSH2XVgZqYHWucap5kuPzLnt2TsNQkoppVxB5eJGvaXwm", prysm.PeerID(&key.PublicKey))
+}
+
+// TestChainConfig verifies the chain config carries the network settings of the consensus config.
+func TestChainConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := unittest.ConsensusConfigFixture(t, 1)
+	chainConfig := prysm.ChainConfig(cfg)
+	require.Equal(t, prysm.LocalnetConfigName, chainConfig.ConfigName)
+	require.Equal(t, uint64(12), chainConfig.SecondsPerSlot)
+	require.Equal(t, node.DefaultDepositContractAddress.Hex(), chainConfig.DepositContractAddress)
+
+	cfg.ChainID = 31337
+	cfg.SecondsPerSlot = secondsPerSlot
+	cfg.DepositContractAddress = common.HexToAddress("0x1234")
+	chainConfig = prysm.ChainConfig(cfg)
+	require.Equal(t, uint64(31337), chainConfig.DepositChainID)
+	require.Equal(t, uint64(31337), chainConfig.DepositNetworkID)
+	require.Equal(t, uint64(secondsPerSlot), chainConfig.SecondsPerSlot)
+	require.Equal(t, cfg.DepositContractAddress.Hex(), chainConfig.DepositContractAddress)
+
+	// The localnet config itself is left untouched
+	require.Equal(t, uint64(12), prysm.LocalnetConfig().SecondsPerSlot)
+}
@@ -0,0 +1,147 @@
+package prysm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/thep2p/go-eth-localnet/internal/consensus"
+)
+
+var (
+	// ErrChainIDMismatch indicates the EL genesis chain ID differs from the consensus config.
+	ErrChainIDMismatch = errors.New("chain id mismatch")
+	// ErrGenesisTimeMismatch indicates the EL genesis timestamp differs from the CL genesis time.
+	ErrGenesisTimeMismatch = errors.New("genesis time mismatch")
+	// ErrBlockHashMismatch indicates eth1_data.block_hash or the latest execution
+	// payload header is not the EL genesis block.
+	ErrBlockHashMismatch = errors.New("execution block hash mismatch")
+	// ErrGenesisRootMismatch indicates the beacon state root differs from the configured GenesisRoot.
+	ErrGenesisRootMismatch = errors.New("genesis root mismatch")
+	// ErrNotProofOfStake indicates the EL genesis does not start post-merge.
+	ErrNotProofOfStake = errors.New("execution genesis is not proof-of-stake")
+	// ErrDepositCountMismatch indicates eth1_data.deposit_count differs from the validator count.
+	ErrDepositCountMismatch = errors.New("deposit count mismatch")
+	// ErrDepositContractMismatch indicates the EL genesis lacks the configured deposit contract.
+	ErrDepositContractMismatch = errors.New("deposit contract mismatch")
+	// ErrValidatorMismatch indicates the CL validator registry differs from the configured validators.
+	ErrValidatorMismatch = errors.New("validator mismatch")
+)
+
+// ValidateGenesisPair checks that an execution genesis and a beacon genesis state
+// describe the same network.
+//
+// Unlike consensus.Config.Validate, which only checks individual fields, this
+// cross-checks the EL genesis against the decoded CL genesis state and the
+// consensus config both were derived from:
+//   - EL chain ID matches cfg.ChainID
+//   - EL genesis starts post-merge (terminal total difficulty of zero)
+//   - EL genesis timestamp, CL genesis time and cfg.GenesisTime agree
+//   - eth1_data.block_hash and the latest execution payload header block hash
+//     are the EL genesis block hash
+//   - cfg.GenesisRoot (if set) is the beacon state root
+//   - eth1_data.deposit_count and the validator registry match cfg's validators
+//   - cfg.DepositContractAddress (if set) is the EL deposit contract and has code
+//
+// Args:
+//   - cfg: consensus configuration the CL genesis was generated from
+//   - elGenesis: execution genesis (e.g. from node.NewGenesis)
+//   - clGenesis: SSZ-encoded genesis state (output from GenerateGenesisState)
+//
+// Returns nil if the pair is consistent. Otherwise returns every inconsistency
+// joined into one error; each wraps one of the Err* sentinels above and can be
+// matched with errors.Is. All errors are CRITICAL: nodes started from an
+// inconsistent pair will not follow the same chain.
+func ValidateGenesisPair(cfg consensus.Config, elGenesis *core.Genesis, clGenesis []byte) error {
+	if elGenesis == nil || elGenesis.Config == nil {
+		return fmt.Errorf("execution genesis and its chain config are required")
+	}
+
+	info, err := InspectGenesisState(clGenesis)
+	if err != nil {
+		return fmt.Errorf("inspect consensus genesis: %w", err)
+	}
+
+	var errs []error
+
+	if elGenesis.Config.ChainID == nil || elGenesis.Config.ChainID.Uint64() != cfg.ChainID {
+		errs = append(errs, fmt.Errorf("%w: execution %v, consensus config %d", ErrChainIDMismatch, elGenesis.Config.ChainID, cfg.ChainID))
+	}
+
+	if ttd := elGenesis.Config.TerminalTotalDifficulty; ttd == nil || ttd.Sign() != 0 {
+		errs = append(errs, fmt.Errorf("%w: terminal total difficulty is %v, expected 0", ErrNotProofOfStake, ttd))
+	}
+
+	clGenesisTime := uint64(info.GenesisTime.Unix())
+	if elGenesis.Timestamp != clGenesisTime {
+		errs = append(errs, fmt.Errorf("%w: execution timestamp %d, consensus genesis time %d", ErrGenesisTimeMismatch, elGenesis.Timestamp, clGenesisTime))
+	}
+	if cfgGenesisTime := uint64(cfg.GenesisTime.Unix()); cfgGenesisTime != clGenesisTime {
+		errs = append(errs, fmt.Errorf("%w: consensus config %d, consensus genesis time %d", ErrGenesisTimeMismatch, cfgGenesisTime, clGenesisTime))
+	}
+
+	elBlockHash := elGenesis.ToBlock().Hash()
+	if info.Eth1Data.BlockHash != elBlockHash {
+		errs = append(errs, fmt.Errorf("%w: execution genesis %s, eth1_data.block_hash %s", ErrBlockHashMismatch, elBlockHash.Hex(), info.Eth1Data.BlockHash.Hex()))
+	}
+	if info.ExecutionBlockHash != elBlockHash {
+		errs = append(errs, fmt.Errorf("%w: execution genesis %s, latest execution payload header %s", ErrBlockHashMismatch, elBlockHash.Hex(), info.ExecutionBlockHash.Hex()))
+	}
+
+	if cfg.GenesisRoot != (common.Hash{}) && cfg.GenesisRoot != info.StateRoot {
+		errs = append(errs, fmt.Errorf("%w: consensus config %s, state root %s", ErrGenesisRootMismatch, cfg.GenesisRoot.Hex(), info.StateRoot.Hex()))
+	}
+
+	if info.Eth1Data.DepositCount != uint64(info.ValidatorCount) {
+		errs = append(errs, fmt.Errorf("%w: eth1_data.deposit_count %d, validator count %d", ErrDepositCountMismatch, info.Eth1Data.DepositCount, info.ValidatorCount))
+	}
+
+	if cfg.DepositContractAddress != (common.Address{}) {
+		if elGenesis.Config.DepositContractAddress != cfg.DepositContractAddress {
+			errs = append(errs, fmt.Errorf("%w: execution %s, consensus config %s", ErrDepositContractMismatch, elGenesis.Config.DepositContractAddress.Hex(), cfg.DepositContractAddress.Hex()))
+		}
+		if len(elGenesis.Alloc[cfg.DepositContractAddress].Code) == 0 {
+			errs = append(errs, fmt.Errorf("%w: no code at %s in execution genesis", ErrDepositContractMismatch, cfg.DepositContractAddress.Hex()))
+		}
+	}
+
+	errs = append(errs, validateValidators(cfg, info)...)
+
+	return errors.Join(errs...)
+}
+
+// validateValidators compares the configured validators with the genesis validator registry.
+// Returns one error per mismatching validator, each wrapping ErrValidatorMismatch.
+func validateValidators(cfg consensus.Config, info *GenesisInfo) []error {
+	if len(cfg.ValidatorKeys) != info.ValidatorCount {
+		return []error{fmt.Errorf("%w: consensus config has %d validators, genesis state has %d", ErrValidatorMismatch, len(cfg.ValidatorKeys), info.ValidatorCount)}
+	}
+
+	var errs []error
+	specs := cfg.ValidatorSpecs()
+	for i, key := range cfg.ValidatorKeys {
+		v := info.Validators[i]
+		pubkey := key.PublicKey()
+		if !bytes.Equal(pubkey.Marshal(), v.Pubkey) {
+			errs = append(errs, fmt.Errorf("%w: validator %d pubkey %#x, genesis state has %s", ErrValidatorMismatch, i, pubkey.Marshal(), v.Pubkey))
+			continue
+		}
+		if i >= len(specs) {
+			continue
+		}
+		creds, err := withdrawalCredentials(pubkey, specs[i])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w: validator %d: %v", ErrValidatorMismatch, i, err))
+			continue
+		}
+		if !bytes.Equal(creds, v.WithdrawalCredentials) {
+			errs = append(errs, fmt.Errorf("%w: validator %d withdrawal credentials %#x, genesis state has %s", ErrValidatorMismatch, i, creds, v.WithdrawalCredentials))
+		}
+		if amount := specs[i].DepositAmount(); amount != v.Balance {
+			errs = append(errs, fmt.Errorf("%w: validator %d balance %d gwei, genesis state has %d gwei", ErrValidatorMismatch, i, amount, v.Balance))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,172 @@
+package prysm_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/stretchr/testify/require"
+	"github.com/thep2p/go-eth-localnet/internal/consensus"
+	"github.com/thep2p/go-eth-localnet/internal/consensus/prysm"
+	"github.com/thep2p/go-eth-localnet/internal/node"
+	"github.com/thep2p/go-eth-localnet/internal/unittest"
+)
+
+// genesisPairFixture builds a consistent EL genesis, consensus config and CL genesis state.
+func genesisPairFixture(t *testing.T) (consensus.Config, *core.Genesis, []byte) {
+	t.Helper()
+
+	cfg := unittest.ConsensusConfigFixture(t, 2)
+	elGenesis := node.NewGenesis()
+	elGenesis.Timestamp = uint64(cfg.GenesisTime.Unix())
+	cfg.ChainID = elGenesis.Config.ChainID.Uint64()
+	cfg.ExecutionGenesis = elGenesis
+
+	clGenesis, err := prysm.GenerateGenesisState(cfg)
+	require.NoError(t, err)
+
+	cfg.GenesisRoot, err = prysm.DeriveGenesisRoot(clGenesis)
+	require.NoError(t, err)
+
+	return cfg, elGenesis, clGenesis
+}
+
+// TestValidateGenesisPair_Consistent verifies a pair derived from the same
+// configuration passes validation.
+func TestValidateGenesisPair_Consistent(t *testing.T) {
+	t.Parallel()
+
+	cfg, elGenesis, clGenesis := genesisPairFixture(t)
+	require.NoError(t, prysm.ValidateGenesisPair(cfg, elGenesis, clGenesis))
+
+	// Without an execution genesis, the CL genesis is anchored to the default localnet one.
+	defaultCfg := cfg
+	defaultCfg.ExecutionGenesis = nil
+	defaultGenesis, err := prysm.GenerateGenesisState(defaultCfg)
+	require.NoError(t, err)
+	require.NoError(t, prysm.ValidateGenesisPair(cfg, elGenesis, defaultGenesis))
+
+	// A deposit contract predeployed at the configured address is consistent too.
+	node.WithDepositContract(node.DefaultDepositContractAddress)(elGenesis)
+	cfg.DepositContractAddress = node.DefaultDepositContractAddress
+	// The predeploy changes the execution genesis hash, so the CL genesis must be regenerated.
+	cfg.GenesisRoot = common.Hash{}
+	clGenesis, err = prysm.GenerateGenesisState(cfg)
+	require.NoError(t, err)
+	require.NoError(t, prysm.ValidateGenesisPair(cfg, elGenesis, clGenesis))
+}
+
+// TestValidateGenesisPair_Mismatches verifies each inconsistency is reported
+// with its specific error.
+func TestValidateGenesisPair_Mismatches(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		mutate  func(cfg *consensus.Config, elGenesis *core.Genesis)
+		wantErr []error
+	}{
+		{
+			name: "chain id",
+			mutate: func(cfg *consensus.Config, _ *core.Genesis) {
+				cfg.ChainID = 1
+			},
+			wantErr: []error{prysm.ErrChainIDMismatch},
+		},
+		{
+			name: "execution timestamp",
+			mutate: func(_ *consensus.Config, elGenesis *core.Genesis) {
+				elGenesis.Timestamp++
+			},
+			// Changing the timestamp also changes the execution genesis block hash.
+			wantErr: []error{prysm.ErrGenesisTimeMismatch, prysm.ErrBlockHashMismatch},
+		},
+		{
+			name: "execution block hash",
+			mutate: func(_ *consensus.Config, elGenesis *core.Genesis) {
+				elGenesis.ExtraData = []byte("different genesis")
+			},
+			wantErr: []error{prysm.ErrBlockHashMismatch},
+		},
+		{
+			name: "proof of work execution genesis",
+			mutate: func(_ *consensus.Config, elGenesis *core.Genesis) {
+				chainConfig := *elGenesis.Config
+				chainConfig.TerminalTotalDifficulty = big.NewInt(1)
+				elGenesis.Config = &chainConfig
+			},
+			wantErr: []error{prysm.ErrNotProofOfStake},
+		},
+		{
+			name: "genesis root",
+			mutate: func(cfg *consensus.Config, _ *core.Genesis) {
+				cfg.GenesisRoot = common.HexToHash("0x01")
+			},
+			wantErr: []error{prysm.ErrGenesisRootMismatch},
+		},
+		{
+			name: "missing deposit contract",
+			mutate: func(cfg *consensus.Config, _ *core.Genesis) {
+				cfg.DepositContractAddress = node.DefaultDepositContractAddress
+			},
+			wantErr: []error{prysm.ErrDepositContractMismatch},
+		},
+		{
+			name: "withdrawal address",
+			mutate: func(cfg *consensus.Config, _ *core.Genesis) {
+				cfg.WithdrawalAddresses = []common.Address{cfg.WithdrawalAddresses[1], cfg.WithdrawalAddresses[0]}
+			},
+			wantErr: []error{prysm.ErrValidatorMismatch},
+		},
+		{
+			name: "validator count",
+			mutate: func(cfg *consensus.Config, _ *core.Genesis) {
+				cfg.ValidatorKeys = cfg.ValidatorKeys[:1]
+			},
+			wantErr: []error{prysm.ErrValidatorMismatch},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, elGenesis, clGenesis := genesisPairFixture(t)
+			tt.mutate(&cfg, elGenesis)
+
+			err := prysm.ValidateGenesisPair(cfg, elGenesis, clGenesis)
+			require.Error(t, err)
+			for _, want := range tt.wantErr {
+				require.True(t, errors.Is(err, want), "expected %v in %v", want, err)
+			}
+		})
+	}
+}
+
+// TestValidateGenesisPair_OtherExecutionGenesis verifies a CL genesis generated
+// from a different execution genesis is reported as not anchored to it.
+func TestValidateGenesisPair_OtherExecutionGenesis(t *testing.T) {
+	t.Parallel()
+
+	cfg, elGenesis, _ := genesisPairFixture(t)
+	other := node.NewGenesis(node.WithDepositContract(node.DefaultDepositContractAddress))
+	other.Timestamp = elGenesis.Timestamp
+	cfg.ExecutionGenesis = other
+	cfg.GenesisRoot = common.Hash{}
+	clGenesis, err := prysm.GenerateGenesisState(cfg)
+	require.NoError(t, err)
+
+	err = prysm.ValidateGenesisPair(cfg, elGenesis, clGenesis)
+	require.ErrorIs(t, err, prysm.ErrBlockHashMismatch)
+	require.NotErrorIs(t, err, prysm.ErrGenesisTimeMismatch)
+}
+
+// TestValidateGenesisPair_InvalidInput verifies missing or corrupted inputs are rejected.
+func TestValidateGenesisPair_InvalidInput(t *testing.T) {
+	t.Parallel()
+
+	cfg, elGenesis, _ := genesisPairFixture(t)
+
+	require.ErrorContains(t, prysm.ValidateGenesisPair(cfg, nil, []byte{0x01}), "execution genesis")
+	require.ErrorContains(t, prysm.ValidateGenesisPair(cfg, elGenesis, nil), "inspect consensus genesis")
+}
@@ -0,0 +1,85 @@
+package consensus
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prysmaticlabs/prysm/v5/config/params"
+	"github.com/prysmaticlabs/prysm/v5/crypto/bls"
+)
+
+// WithdrawalType identifies the withdrawal credential prefix of a validator.
+//
+// The prefix is the first byte of the 32-byte withdrawal credentials and
+// determines how the beacon chain treats the validator's stake:
+//
+//   - 0x00 (BLS): withdrawals are locked to a BLS key until the credentials
+//     are changed to an execution address (BLSToExecutionChange).
+//   - 0x01 (Execution): withdrawals go directly to an execution address.
+//   - 0x02 (Compounding): Electra credentials; rewards compound up to
+//     MaxEffectiveBalanceElectra (2048 ETH) instead of being swept at 32 ETH.
+type WithdrawalType byte
+
+const (
+	// BLSWithdrawal is the legacy (pre-Shanghai) 0x00 withdrawal credential type.
+	BLSWithdrawal WithdrawalType = 0x00
+	// ExecutionWithdrawal is the 0x01 execution address withdrawal credential type.
+	ExecutionWithdrawal WithdrawalType = 0x01
+	// CompoundingWithdrawal is the Electra 0x02 compounding withdrawal credential type.
+	CompoundingWithdrawal WithdrawalType = 0x02
+)
+
+// String returns the hex prefix of the withdrawal type, e.g. "0x01".
+func (w WithdrawalType) String() string {
+	return fmt.Sprintf("0x%02x", byte(w))
+}
+
+// ValidatorSpec describes the genesis deposit of a single validator.
+//
+// The zero ValidatorSpec is valid: BLSWithdrawal credentials committed to the
+// validator's own key and a 32 ETH deposit. ExecutionWithdrawal and
+// CompoundingWithdrawal additionally require a WithdrawalAddress.
+type ValidatorSpec struct {
+	// WithdrawalType selects the withdrawal credential prefix (0x00, 0x01 or 0x02).
+	WithdrawalType WithdrawalType
+
+	// WithdrawalAddress is the execution layer address that receives withdrawals.
+	// Required for ExecutionWithdrawal and CompoundingWithdrawal, ignored for BLSWithdrawal.
+	WithdrawalAddress common.Address
+
+	// WithdrawalPubkey is the BLS public key committed to by BLSWithdrawal credentials.
+	// If nil, the validator's own public key is used. Ignored for other types.
+	WithdrawalPubkey bls.PublicKey
+
+	// Balance is the deposit amount in Gwei.
+	// If zero, MaxEffectiveBalance (32 ETH) is used. At genesis, up to 2048 ETH
+	// counts as effective balance for CompoundingWithdrawal and up to 32 ETH otherwise.
+	Balance uint64
+}
+
+// DepositAmount returns the deposit amount in Gwei, defaulting to MaxEffectiveBalance.
+func (s ValidatorSpec) DepositAmount() uint64 {
+	if s.Balance == 0 {
+		return params.BeaconConfig().MaxEffectiveBalance
+	}
+	return s.Balance
+}
+
+// Validate checks that the spec describes a depositable validator.
+func (s ValidatorSpec) Validate() error {
+	switch s.WithdrawalType {
+	case BLSWithdrawal:
+	case ExecutionWithdrawal, CompoundingWithdrawal:
+		if s.WithdrawalAddress == (common.Address{}) {
+			return fmt.Errorf("withdrawal type %s requires a withdrawal address", s.WithdrawalType)
+		}
+	default:
+		return fmt.Errorf("unsupported withdrawal type %s", s.WithdrawalType)
+	}
+
+	if amount := s.DepositAmount(); amount < params.BeaconConfig().MinDepositAmount {
+		return fmt.Errorf("balance %d gwei is below minimum deposit amount %d gwei", amount, params.BeaconConfig().MinDepositAmount)
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package contracts
+
+import (
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/abigen"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// BindOptions configure the generation of Go bindings.
+type BindOptions struct {
+	// Package is the name of the generated Go package, e.g. storage.
+	Package string
+	// Contracts selects the contracts to bind, fully qualified as
+	// "file:ContractName" or by contract name alone (see Artifacts.Contract).
+	// Empty binds every contract of the compilation.
+	Contracts []string
+	// Aliases renames ABI identifiers whose Go names would collide,
+	// e.g. {"_value": "RawValue"}.
+	Aliases map[string]string
+	// OmitBytecode leaves the creation bytecode and the DeployX functions out
+	// of the bindings, so they do not change with the compiler version or
+	// settings. Such bindings attach to contracts deployed with Deploy through
+	// their NewX constructors.
+	OmitBytecode bool
+}
+
+// Bind generates abigen-style Go bindings for compiled contracts: for each
+// contract X, a type with typed methods for every function, e.g.
+// X.Set(opts, v), caller, transactor and filterer types, event iterators, and
+// a DeployX function unless OmitBytecode is set.
+//
+// Args:
+//   - artifacts: the output of Compile
+//   - opts: the package name and the contracts to bind
+//
+// Returns the gofmt-ed source of a single Go file. All errors are CRITICAL and
+// indicate an invalid package name, a selected contract that is not in
+// artifacts, two selected contracts with the same name, or an ABI abigen
+// cannot bind.
+func Bind(artifacts Artifacts, opts BindOptions) (string, error) {
+	if !token.IsIdentifier(opts.Package) {
+		return "", fmt.Errorf("invalid package name %q", opts.Package)
+	}
+
+	selected, err := selectArtifacts(artifacts, opts.Contracts)
+	if err != nil {
+		return "", err
+	}
+
+	var types, abis, bins []string
+	seen := make(map[string]string)
+	for _, artifact := range selected {
+		if other, ok := seen[artifact.Name]; ok {
+			if other == artifact.Key() {
+				continue
+			}
+			return "", fmt.Errorf("contracts %s and %s would both bind to type %s, select one of them", other, artifact.Key(), artifact.Name)
+		}
+		seen[artifact.Name] = artifact.Key()
+
+		types = append(types, artifact.Name)
+		abis = append(abis, artifact.ABI)
+		if opts.OmitBytecode {
+			bins = append(bins, "")
+		} else {
+			bins = append(bins, artifact.Bin)
+		}
+	}
+
+	// Linked libraries appear in the bytecode as placeholders derived from
+	// their fully qualified names; every library of the compilation may be
+	// linked, not only the selected ones.
+	libs := make(map[string]string)
+	for key, artifact := range artifacts {
+		libs[crypto.Keccak256Hash([]byte(key)).Hex()[2:36]] = artifact.Name
+	}
+
+	code, err := abigen.Bind(types, abis, bins, nil, opts.Package, libs, opts.Aliases)
+	if err != nil {
+		return "", fmt.Errorf("generate bindings: %w", err)
+	}
+	return code, nil
+}
+
+// WriteBindings generates the Go bindings of Bind and writes them to path,
+// creating its directory if needed.
+// Returns an error if the bindings cannot be generated or written.
+func WriteBindings(path string, artifacts Artifacts, opts BindOptions) error {
+	code, err := Bind(artifacts, opts)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create bindings dir: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(code), 0644); err != nil {
+		return fmt.Errorf("write bindings: %w", err)
+	}
+	return nil
+}
+
+// selectArtifacts returns the artifacts matching names, or every artifact if
+// names is empty, in a stable order.
+func selectArtifacts(artifacts Artifacts, names []string) ([]*Artifact, error) {
+	if len(names) == 0 {
+		selected := make([]*Artifact, 0, len(artifacts))
+		for _, key := range artifacts.Keys() {
+			selected = append(selected, artifacts[key])
+		}
+		return selected, nil
+	}
+
+	selected := make([]*Artifact, 0, len(names))
+	for _, name := range names {
+		artifact, err := artifacts.Contract(name)
+		if err != nil {
+			return nil, err
+		}
+		selected = append(selected, artifact)
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].Key() < selected[j].Key()
+	})
+	return selected, nil
+}
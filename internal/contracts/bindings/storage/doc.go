@@ -0,0 +1,9 @@
+// Package storage contains the generated Go bindings of the Storage contract.
+// cf. internal/contracts/SimpleStorageContract.sol
+//
+// The bindings omit the bytecode, so they do not depend on the compiler
+// version; deploy the contract with contracts.Deploy and attach to it with
+// NewStorage.
+package storage
+
+//go:generate go run ../../../../cmd/localnet contracts bind -pkg storage -contract Storage -omit-bytecode -out storage.go ../../SimpleStorageContract.sol
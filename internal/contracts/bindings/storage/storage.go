@@ -0,0 +1,367 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package storage
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// StorageMetaData contains all meta data concerning the Storage contract.
+var StorageMetaData = &bind.MetaData{
+	ABI: "[{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"newValue\",\"type\":\"uint256\"}],\"name\":\"ValueChanged\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"v\",\"type\":\"uint256\"}],\"name\":\"set\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"value\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]",
+}
+
+// StorageABI is the input ABI used to generate the binding from.
+// Deprecated: Use StorageMetaData.ABI instead.
+var StorageABI = StorageMetaData.ABI
+
+// Storage is an auto generated Go binding around an Ethereum contract.
+type Storage struct {
+	StorageCaller     // Read-only binding to the contract
+	StorageTransactor // Write-only binding to the contract
+	StorageFilterer   // Log filterer for contract events
+}
+
+// StorageCaller is an auto generated read-only Go binding around an Ethereum contract.
+type StorageCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// StorageTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type StorageTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// StorageFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type StorageFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// StorageSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type StorageSession struct {
+	Contract     *Storage          // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// StorageCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type StorageCallerSession struct {
+	Contract *StorageCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts  // Call options to use throughout this session
+}
+
+// StorageTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type StorageTransactorSession struct {
+	Contract     *StorageTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts  // Transaction auth options to use throughout this session
+}
+
+// StorageRaw is an auto generated low-level Go binding around an Ethereum contract.
+type StorageRaw struct {
+	Contract *Storage // Generic contract binding to access the raw methods on
+}
+
+// StorageCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type StorageCallerRaw struct {
+	Contract *StorageCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// StorageTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type StorageTransactorRaw struct {
+	Contract *StorageTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewStorage creates a new instance of Storage, bound to a specific deployed contract.
+func NewStorage(address common.Address, backend bind.ContractBackend) (*Storage, error) {
+	contract, err := bindStorage(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &Storage{StorageCaller: StorageCaller{contract: contract}, StorageTransactor: StorageTransactor{contract: contract}, StorageFilterer: StorageFilterer{contract: contract}}, nil
+}
+
+// NewStorageCaller creates a new read-only instance of Storage, bound to a specific deployed contract.
+func NewStorageCaller(address common.Address, caller bind.ContractCaller) (*StorageCaller, error) {
+	contract, err := bindStorage(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &StorageCaller{contract: contract}, nil
+}
+
+// NewStorageTransactor creates a new write-only instance of Storage, bound to a specific deployed contract.
+func NewStorageTransactor(address common.Address, transactor bind.ContractTransactor) (*StorageTransactor, error) {
+	contract, err := bindStorage(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &StorageTransactor{contract: contract}, nil
+}
+
+// NewStorageFilterer creates a new log filterer instance of Storage, bound to a specific deployed contract.
+func NewStorageFilterer(address common.Address, filterer bind.ContractFilterer) (*StorageFilterer, error) {
+	contract, err := bindStorage(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &StorageFilterer{contract: contract}, nil
+}
+
+// bindStorage binds a generic wrapper to an already deployed contract.
+func bindStorage(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := StorageMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Storage *StorageRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Storage.Contract.StorageCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Storage *StorageRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Storage.Contract.StorageTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Storage *StorageRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Storage.Contract.StorageTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Storage *StorageCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Storage.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Storage *StorageTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Storage.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Storage *StorageTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Storage.Contract.contract.Transact(opts, method, params...)
+}
+
+// Value is a free data retrieval call binding the contract method 0x3fa4f245.
+//
+// Solidity: function value() view returns(uint256)
+func (_Storage *StorageCaller) Value(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _Storage.contract.Call(opts, &out, "value")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// Value is a free data retrieval call binding the contract method 0x3fa4f245.
+//
+// Solidity: function value() view returns(uint256)
+func (_Storage *StorageSession) Value() (*big.Int, error) {
+	return _Storage.Contract.Value(&_Storage.CallOpts)
+}
+
+// Value is a free data retrieval call binding the contract method 0x3fa4f245.
+//
+// Solidity: function value() view returns(uint256)
+func (_Storage *StorageCallerSession) Value() (*big.Int, error) {
+	return _Storage.Contract.Value(&_Storage.CallOpts)
+}
+
+// Set is a paid mutator transaction binding the contract method 0x60fe47b1.
+//
+// Solidity: function set(uint256 v) returns()
+func (_Storage *StorageTransactor) Set(opts *bind.TransactOpts, v *big.Int) (*types.Transaction, error) {
+	return _Storage.contract.Transact(opts, "set", v)
+}
+
+// Set is a paid mutator transaction binding the contract method 0x60fe47b1.
+//
+// Solidity: function set(uint256 v) returns()
+func (_Storage *StorageSession) Set(v *big.Int) (*types.Transaction, error) {
+	return _Storage.Contract.Set(&_Storage.TransactOpts, v)
+}
+
+// Set is a paid mutator transaction binding the contract method 0x60fe47b1.
+//
+// Solidity: function set(uint256 v) returns()
+func (_Storage *StorageTransactorSession) Set(v *big.Int) (*types.Transaction, error) {
+	return _Storage.Contract.Set(&_Storage.TransactOpts, v)
+}
+
+// StorageValueChangedIterator is returned from FilterValueChanged and is used to iterate over the raw logs and unpacked data for ValueChanged events raised by the Storage contract.
+type StorageValueChangedIterator struct {
+	Event *StorageValueChanged // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *StorageValueChangedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(StorageValueChanged)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(StorageValueChanged)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *StorageValueChangedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *StorageValueChangedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// StorageValueChanged represents a ValueChanged event raised by the Storage contract.
+type StorageValueChanged struct {
+	NewValue *big.Int
+	Raw      types.Log // Blockchain specific contextual infos
+}
+
+// FilterValueChanged is a free log retrieval operation binding the contract event 0x93fe6d397c74fdf1402a8b72e47b68512f0510d7b98a4bc4cbdf6ac7108b3c59.
+//
+// Solidity: event ValueChanged(uint256 newValue)
+func (_Storage *StorageFilterer) FilterValueChanged(opts *bind.FilterOpts) (*StorageValueChangedIterator, error) {
+
+	logs, sub, err := _Storage.contract.FilterLogs(opts, "ValueChanged")
+	if err != nil {
+		return nil, err
+	}
+	return &StorageValueChangedIterator{contract: _Storage.contract, event: "ValueChanged", logs: logs, sub: sub}, nil
+}
+
+// WatchValueChanged is a free log subscription operation binding the contract event 0x93fe6d397c74fdf1402a8b72e47b68512f0510d7b98a4bc4cbdf6ac7108b3c59.
+//
+// Solidity: event ValueChanged(uint256 newValue)
+func (_Storage *StorageFilterer) WatchValueChanged(opts *bind.WatchOpts, sink chan<- *StorageValueChanged) (event.Subscription, error) {
+
+	logs, sub, err := _Storage.contract.WatchLogs(opts, "ValueChanged")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(StorageValueChanged)
+				if err := _Storage.contract.UnpackLog(event, "ValueChanged", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseValueChanged is a log parse operation binding the contract event 0x93fe6d397c74fdf1402a8b72e47b68512f0510d7b98a4bc4cbdf6ac7108b3c59.
+//
+// Solidity: event ValueChanged(uint256 newValue)
+func (_Storage *StorageFilterer) ParseValueChanged(log types.Log) (*StorageValueChanged, error) {
+	event := new(StorageValueChanged)
+	if err := _Storage.contract.UnpackLog(event, "ValueChanged", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
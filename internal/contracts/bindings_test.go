@@ -0,0 +1,124 @@
+package contracts_test
+
+import (
+	"go/parser"
+	"go/token"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/require"
+	"github.com/thep2p/go-eth-localnet/internal/contracts"
+	"github.com/thep2p/go-eth-localnet/internal/contracts/bindings/storage"
+	"github.com/thep2p/go-eth-localnet/internal/unittest"
+)
+
+// simpleTestArtifacts returns the compiled SimpleTestContract, so binding
+// generation can be tested without solc.
+// cf. internal/contracts/SimpleTestContract.sol
+func simpleTestArtifacts() contracts.Artifacts {
+	return contracts.Artifacts{
+		"SimpleTestContract.sol:SimpleTestContract": {
+			Name:       "SimpleTestContract",
+			SourcePath: "SimpleTestContract.sol",
+			ABI:        `[{"inputs":[],"name":"f","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"pure","type":"function"}]`,
+			Bin:        "6080604052348015600e575f5ffd5b50608680601a5f395ff3fe6080604052348015600e575f5ffd5b50600436106026575f3560e01c806326121ff014602a575b5f5ffd5b60306044565b604051603b91906062565b60405180910390f35b5f602a905090565b5f819050919050565b605c81604c565b82525050565b5f60208201905060735f8301846055565b9291505056fea164736f6c634300081e000a",
+		},
+	}
+}
+
+// TestBind tests that the generated bindings are valid Go with typed methods and, unless omitted, a deploy function.
+func TestBind(t *testing.T) {
+	code, err := contracts.Bind(simpleTestArtifacts(), contracts.BindOptions{Package: "simple"})
+	require.NoError(t, err)
+
+	file, err := parser.ParseFile(token.NewFileSet(), "simple.go", code, 0)
+	require.NoError(t, err)
+	require.Equal(t, "simple", file.Name.Name)
+	require.Contains(t, code, "func (_SimpleTestContract *SimpleTestContractCaller) F(opts *bind.CallOpts) (*big.Int, error)")
+	require.Contains(t, code, "func DeploySimpleTestContract(")
+
+	code, err = contracts.Bind(simpleTestArtifacts(), contracts.BindOptions{Package: "simple", OmitBytecode: true})
+	require.NoError(t, err)
+	require.NotContains(t, code, "func DeploySimpleTestContract(")
+	require.Contains(t, code, "func NewSimpleTestContract(")
+
+	code, err = contracts.Bind(simpleTestArtifacts(), contracts.BindOptions{Package: "simple", Aliases: map[string]string{"f": "Answer"}})
+	require.NoError(t, err)
+	require.Contains(t, code, "func (_SimpleTestContract *SimpleTestContractCaller) Answer(")
+}
+
+// TestBind_Errors tests that invalid packages, unknown contracts and name clashes are rejected.
+func TestBind_Errors(t *testing.T) {
+	_, err := contracts.Bind(simpleTestArtifacts(), contracts.BindOptions{Package: "not-a-package"})
+	require.ErrorContains(t, err, "invalid package name")
+
+	_, err = contracts.Bind(simpleTestArtifacts(), contracts.BindOptions{Package: "simple", Contracts: []string{"Missing"}})
+	require.Error(t, err)
+
+	artifacts := simpleTestArtifacts()
+	clash := *artifacts["SimpleTestContract.sol:SimpleTestContract"]
+	clash.SourcePath = "Other.sol"
+	artifacts[clash.Key()] = &clash
+	_, err = contracts.Bind(artifacts, contracts.BindOptions{Package: "simple"})
+	require.ErrorContains(t, err, "would both bind to type SimpleTestContract")
+
+	_, err = contracts.Bind(
+		artifacts, contracts.BindOptions{Package: "simple", Contracts: []string{"Other.sol:SimpleTestContract"}},
+	)
+	require.NoError(t, err, "qualifying the contract should resolve the clash")
+}
+
+// TestWriteBindings tests that bindings are written to a new directory.
+func TestWriteBindings(t *testing.T) {
+	tmp := unittest.NewTempDir(t)
+	t.Cleanup(tmp.Remove)
+	path := filepath.Join(tmp.Path(), "simple", "simple.go")
+
+	require.NoError(t, contracts.WriteBindings(path, simpleTestArtifacts(), contracts.BindOptions{Package: "simple"}))
+	code, err := os.ReadFile(path)
+	require.NoError(t, err)
+	expected, err := contracts.Bind(simpleTestArtifacts(), contracts.BindOptions{Package: "simple"})
+	require.NoError(t, err)
+	require.Equal(t, expected, string(code))
+}
+
+// TestStorageBindings tests that the generated Storage bindings are up to date
+// and drive a contract deployed with Deploy.
+func TestStorageBindings(t *testing.T) {
+	unittest.RequireSolc(t)
+
+	artifact := storageArtifact(t)
+	expected, err := contracts.Bind(
+		contracts.Artifacts{artifact.Key(): artifact},
+		contracts.BindOptions{Package: "storage", Contracts: []string{"Storage"}, OmitBytecode: true},
+	)
+	require.NoError(t, err)
+	committed, err := os.ReadFile(filepath.Join("bindings", "storage", "storage.go"))
+	require.NoError(t, err)
+	require.Equal(t, expected, string(committed), "bindings are stale, run go generate ./internal/contracts/...")
+
+	key := unittest.PrivateKeyFixture(t)
+	ctx, manager, client := startNode(t, key)
+	deployed, err := contracts.Deploy(ctx, client, key, artifact)
+	require.NoError(t, err)
+
+	backend := ethclient.NewClient(client)
+	s, err := storage.NewStorage(deployed.Address(), backend)
+	require.NoError(t, err)
+	opts, err := bind.NewKeyedTransactorWithChainID(key, manager.ChainID())
+	require.NoError(t, err)
+	opts.Context = ctx
+
+	tx, err := s.Set(opts, big.NewInt(7))
+	require.NoError(t, err)
+	_, err = bind.WaitMined(ctx, backend, tx)
+	require.NoError(t, err)
+
+	value, err := s.Value(&bind.CallOpts{Context: ctx})
+	require.NoError(t, err)
+	require.Equal(t, int64(7), value.Int64())
+}
@@ -0,0 +1,381 @@
+package contracts
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/thep2p/go-eth-localnet/internal/model"
+	"github.com/thep2p/go-eth-localnet/internal/utils"
+)
+
+// receiptPollInterval is the delay between receipt lookups while waiting for a transaction to be mined.
+const receiptPollInterval = 100 * time.Millisecond
+
+// TransactOptions customise the transactions sent by Deploy and BoundContract.
+// Zero values are filled in from the node.
+type TransactOptions struct {
+	// Value is the wei sent along, e.g. to a payable function. Nil sends none.
+	Value *big.Int
+	// GasLimit is the gas limit. Zero means the node's eth_estimateGas result.
+	GasLimit uint64
+	// GasTipCap is the EIP-1559 priority fee per gas. Nil means the node's
+	// eth_maxPriorityFeePerGas suggestion.
+	GasTipCap *big.Int
+	// GasFeeCap is the EIP-1559 maximum fee per gas. Nil means twice the base
+	// fee of the latest block plus GasTipCap, so the transaction stays valid
+	// while the base fee rises for a few blocks.
+	GasFeeCap *big.Int
+	// Nonce is the account nonce. Nil means the pending nonce of the sender.
+	// Set it when sending concurrently from the same key.
+	Nonce *uint64
+}
+
+// BoundContract is a deployed contract bound to its ABI and to the key that
+// signs its transactions.
+type BoundContract struct {
+	client   *rpc.Client
+	key      *ecdsa.PrivateKey
+	from     common.Address
+	chainID  *big.Int
+	address  common.Address
+	abi      abi.ABI
+	events   *EventDecoder
+	reverts  *RevertDecoder
+	artifact *Artifact
+}
+
+// Deploy deploys a compiled contract and waits until the deployment is mined.
+//
+// Args:
+//   - ctx: bounds the deployment, including waiting for the receipt
+//   - client: RPC client of the node
+//   - key: key of the funded account that deploys the contract and signs later transactions
+//   - artifact: the compiled contract, e.g. from Artifacts.Contract
+//   - args: constructor arguments, ABI-encoded according to the artifact's ABI
+//
+// Returns the contract bound at its deployment address. All errors are
+// CRITICAL and indicate the arguments do not match the constructor, the
+// transaction could not be sent, or the deployment reverted.
+func Deploy(ctx context.Context, client *rpc.Client, key *ecdsa.PrivateKey, artifact *Artifact, args ...interface{}) (*BoundContract, error) {
+	return DeployWithOptions(ctx, client, key, artifact, TransactOptions{}, args...)
+}
+
+// DeployWithOptions is Deploy with explicit transaction options, e.g. to fund a payable constructor.
+func DeployWithOptions(
+	ctx context.Context,
+	client *rpc.Client,
+	key *ecdsa.PrivateKey,
+	artifact *Artifact,
+	opts TransactOptions,
+	args ...interface{},
+) (*BoundContract, error) {
+	contract, err := bindContract(ctx, client, key, common.Address{}, artifact)
+	if err != nil {
+		return nil, err
+	}
+
+	initCode, err := packInitCode(contract.abi, artifact, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	txHash, err := contract.send(ctx, nil, initCode, opts)
+	if err != nil {
+		return nil, fmt.Errorf("deploy %s: %w", artifact.Name, err)
+	}
+	receipt, err := contract.WaitReceipt(ctx, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("deploy %s: %w", artifact.Name, err)
+	}
+	contract.address = receipt.ContractAddress
+	return contract, nil
+}
+
+// NewBoundContract binds an already deployed contract at address to its
+// artifact and to the key that signs its transactions.
+// Returns an error if the artifact's ABI is invalid or the chain ID cannot be fetched.
+func NewBoundContract(
+	ctx context.Context,
+	client *rpc.Client,
+	key *ecdsa.PrivateKey,
+	address common.Address,
+	artifact *Artifact,
+) (*BoundContract, error) {
+	return bindContract(ctx, client, key, address, artifact)
+}
+
+// bindContract returns a BoundContract at address, fetching the chain ID from the node.
+func bindContract(ctx context.Context, client *rpc.Client, key *ecdsa.PrivateKey, address common.Address, artifact *Artifact) (*BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(artifact.ABI))
+	if err != nil {
+		return nil, fmt.Errorf("parse abi of %s: %w", artifact.Name, err)
+	}
+
+	var chainID hexutil.Big
+	if err := client.CallContext(ctx, &chainID, model.EthChainID); err != nil {
+		return nil, fmt.Errorf("get chain id: %w", err)
+	}
+
+	reverts := &RevertDecoder{errors: make(map[[4]byte]*abi.Error)}
+	reverts.add(parsed)
+
+	return &BoundContract{
+		client:   client,
+		key:      key,
+		from:     crypto.PubkeyToAddress(key.PublicKey),
+		chainID:  chainID.ToInt(),
+		address:  address,
+		abi:      parsed,
+		events:   &EventDecoder{abi: parsed},
+		reverts:  reverts,
+		artifact: artifact,
+	}, nil
+}
+
+// Address returns the address of the contract.
+func (c *BoundContract) Address() common.Address {
+	return c.address
+}
+
+// ABI returns the parsed ABI of the contract.
+func (c *BoundContract) ABI() abi.ABI {
+	return c.abi
+}
+
+// Artifact returns the compiled contract the binding was created from.
+func (c *BoundContract) Artifact() *Artifact {
+	return c.artifact
+}
+
+// Events returns the decoder of the contract's events.
+func (c *BoundContract) Events() *EventDecoder {
+	return c.events
+}
+
+// DecodeLogs decodes the logs the contract emitted, e.g. those of a receipt
+// returned by WaitReceipt, and skips the logs of other contracts.
+// Returns an error if a log of the contract cannot be decoded.
+func (c *BoundContract) DecodeLogs(logs []*types.Log) ([]*Event, error) {
+	own := make([]*types.Log, 0, len(logs))
+	for _, log := range logs {
+		if log.Address == c.address {
+			own = append(own, log)
+		}
+	}
+	return c.events.DecodeAll(own)
+}
+
+// FilterEvents fetches the events the contract emitted in a block range with
+// eth_getLogs; nil bounds mean the earliest and the latest block. names
+// restricts the events by name, all events of the ABI if empty.
+// Returns an error if an event is not in the ABI or the query fails.
+func (c *BoundContract) FilterEvents(ctx context.Context, fromBlock, toBlock *big.Int, names ...string) ([]*Event, error) {
+	return c.events.FilterLogs(
+		ctx, c.client, LogFilter{
+			Addresses: []common.Address{c.address},
+			Events:    names,
+			FromBlock: fromBlock,
+			ToBlock:   toBlock,
+		},
+	)
+}
+
+// Reverts returns the decoder of the contract's revert reasons, panics and custom errors.
+func (c *BoundContract) Reverts() *RevertDecoder {
+	return c.reverts
+}
+
+// Call executes a read-only method with eth_call against the latest block,
+// from the bound key's address, and returns the decoded outputs.
+// Returns an error if the method does not exist, the arguments do not match,
+// or the call fails; a revert is wrapped as a *RevertError.
+func (c *BoundContract) Call(ctx context.Context, method string, args ...interface{}) ([]interface{}, error) {
+	input, err := c.abi.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("pack %s arguments: %w", method, err)
+	}
+
+	var output hexutil.Bytes
+	if err := c.client.CallContext(ctx, &output, model.CallContextEthCall, c.callArgs(input, nil), model.EthBlockLatest); err != nil {
+		if revert, ok := c.reverts.FromError(err); ok {
+			return nil, fmt.Errorf("call %s: %w", method, revert)
+		}
+		return nil, fmt.Errorf("call %s: %w", method, err)
+	}
+	results, err := c.abi.Unpack(method, output)
+	if err != nil {
+		return nil, fmt.Errorf("unpack %s outputs: %w", method, err)
+	}
+	return results, nil
+}
+
+// Transact sends a transaction calling method with the given arguments and
+// returns its hash without waiting for it to be mined; see WaitReceipt.
+// Returns an error if the method does not exist, the arguments do not match,
+// or the transaction is rejected, e.g. because gas estimation fails. A
+// revert during gas estimation is wrapped as a *RevertError.
+func (c *BoundContract) Transact(ctx context.Context, method string, args ...interface{}) (common.Hash, error) {
+	return c.TransactWithOptions(ctx, TransactOptions{}, method, args...)
+}
+
+// TransactWithOptions is Transact with explicit transaction options, e.g. to send value.
+func (c *BoundContract) TransactWithOptions(ctx context.Context, opts TransactOptions, method string, args ...interface{}) (common.Hash, error) {
+	input, err := c.abi.Pack(method, args...)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("pack %s arguments: %w", method, err)
+	}
+	txHash, err := c.send(ctx, &c.address, input, opts)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("transact %s: %w", method, err)
+	}
+	return txHash, nil
+}
+
+// WaitReceipt polls the node until the transaction is mined or ctx is done.
+// Returns the receipt, and an error if the transaction reverted or ctx is done
+// first. A revert is returned with the receipt as a *RevertError, decoded by
+// replaying the transaction (see RevertDecoder.Replay). Failed polls are
+// retried; if ctx is done first, the error wraps the last of their errors.
+func (c *BoundContract) WaitReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	ticker := time.NewTicker(receiptPollInterval)
+	defer ticker.Stop()
+
+	var lastErr error
+	for {
+		var receipt *types.Receipt
+		err := c.client.CallContext(ctx, &receipt, model.EthGetTransactionReceipt, txHash)
+		if err != nil && ctx.Err() == nil {
+			lastErr = err
+		}
+		if err == nil && receipt != nil {
+			if receipt.Status != types.ReceiptStatusSuccessful {
+				revert, err := c.reverts.Replay(ctx, c.client, txHash)
+				if err != nil {
+					return receipt, fmt.Errorf("transaction %s reverted, replay failed: %w", txHash.Hex(), err)
+				}
+				return receipt, revert
+			}
+			return receipt, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return nil, fmt.Errorf("wait for receipt of %s: %w: %w", txHash.Hex(), ctx.Err(), lastErr)
+			}
+			return nil, fmt.Errorf("wait for receipt of %s: %w", txHash.Hex(), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// callArgs returns the eth_call and eth_estimateGas arguments of a message from the bound key.
+func (c *BoundContract) callArgs(input []byte, value *big.Int) map[string]string {
+	args := map[string]string{
+		model.CallContextFrom: c.from.Hex(),
+		model.CallContextData: utils.ByteToHex(input),
+	}
+	if c.address != (common.Address{}) {
+		args[model.CallContextTo] = c.address.Hex()
+	}
+	if value != nil {
+		args[model.CallContextValue] = hexutil.EncodeBig(value)
+	}
+	return args
+}
+
+// send signs and sends an EIP-1559 transaction to the given address, or a
+// contract creation if to is nil, filling in the options the caller left unset.
+func (c *BoundContract) send(ctx context.Context, to *common.Address, input []byte, opts TransactOptions) (common.Hash, error) {
+	value := opts.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	nonce := opts.Nonce
+	if nonce == nil {
+		var pending hexutil.Uint64
+		if err := c.client.CallContext(ctx, &pending, model.EthGetTransactionCount, c.from.Hex(), model.EthBlockPending); err != nil {
+			return common.Hash{}, fmt.Errorf("get nonce: %w", err)
+		}
+		n := uint64(pending)
+		nonce = &n
+	}
+
+	gasTipCap := opts.GasTipCap
+	if gasTipCap == nil {
+		var tip hexutil.Big
+		if err := c.client.CallContext(ctx, &tip, model.EthMaxPriorityFeePerGas); err != nil {
+			return common.Hash{}, fmt.Errorf("get priority fee: %w", err)
+		}
+		gasTipCap = tip.ToInt()
+	}
+
+	gasFeeCap := opts.GasFeeCap
+	if gasFeeCap == nil {
+		var head struct {
+			BaseFee *hexutil.Big `json:"baseFeePerGas"`
+		}
+		if err := c.client.CallContext(ctx, &head, model.EthGetBlockByNumber, model.EthBlockLatest, false); err != nil {
+			return common.Hash{}, fmt.Errorf("get base fee: %w", err)
+		}
+		if head.BaseFee == nil {
+			return common.Hash{}, fmt.Errorf("latest block has no base fee, the chain does not support EIP-1559")
+		}
+		gasFeeCap = new(big.Int).Add(new(big.Int).Mul(head.BaseFee.ToInt(), big.NewInt(2)), gasTipCap)
+	}
+
+	gasLimit := opts.GasLimit
+	if gasLimit == 0 {
+		args := c.callArgs(input, value)
+		delete(args, model.CallContextTo)
+		if to != nil {
+			args[model.CallContextTo] = to.Hex()
+		}
+		var estimate hexutil.Uint64
+		if err := c.client.CallContext(ctx, &estimate, model.EthEstimateGas, args); err != nil {
+			if revert, ok := c.reverts.FromError(err); ok {
+				return common.Hash{}, fmt.Errorf("estimate gas: %w", revert)
+			}
+			return common.Hash{}, fmt.Errorf("estimate gas: %w", err)
+		}
+		gasLimit = uint64(estimate)
+	}
+
+	tx, err := types.SignTx(
+		types.NewTx(&types.DynamicFeeTx{
+			ChainID:   c.chainID,
+			Nonce:     *nonce,
+			GasTipCap: gasTipCap,
+			GasFeeCap: gasFeeCap,
+			Gas:       gasLimit,
+			To:        to,
+			Value:     value,
+			Data:      input,
+		}),
+		types.LatestSignerForChainID(c.chainID), c.key,
+	)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("sign transaction: %w", err)
+	}
+	txBytes, err := tx.MarshalBinary()
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("encode transaction: %w", err)
+	}
+
+	var txHash common.Hash
+	if err := c.client.CallContext(ctx, &txHash, model.EthSendRawTransaction, utils.ByteToHex(txBytes)); err != nil {
+		return common.Hash{}, fmt.Errorf("send transaction: %w", err)
+	}
+	return txHash, nil
+}
@@ -0,0 +1,143 @@
+package contracts_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+	"github.com/thep2p/go-eth-localnet/internal/contracts"
+	"github.com/thep2p/go-eth-localnet/internal/model"
+	"github.com/thep2p/go-eth-localnet/internal/node"
+	"github.com/thep2p/go-eth-localnet/internal/unittest"
+)
+
+// startNode starts a single node with the given key's account pre-funded and
+// returns an RPC client connected to it. The node is stopped when ctx is
+// cancelled at the end of the test.
+func startNode(t *testing.T, key *ecdsa.PrivateKey, opts ...node.LaunchOption) (context.Context, *node.Manager, *rpc.Client) {
+	t.Helper()
+
+	oneEth := big.NewInt(params.Ether)
+	opts = append(opts, node.WithPreFundGenesisAccount(crypto.PubkeyToAddress(key.PublicKey), oneEth))
+	ctx, manager := unittest.StartNodes(t, 1, opts...)
+	return ctx, manager, unittest.DialNode(t, ctx, manager, 0)
+}
+
+// storageArtifact compiles the Storage contract.
+// cf. internal/contracts/SimpleStorageContract.sol
+func storageArtifact(t *testing.T) *contracts.Artifact {
+	t.Helper()
+
+	artifacts, err := contracts.Compile(contracts.CompileOptions{}, "SimpleStorageContract.sol")
+	require.NoError(t, err)
+	storage, err := artifacts.Contract("Storage")
+	require.NoError(t, err)
+	return storage
+}
+
+// TestDeploy tests deploying a contract, changing its state with Transact and reading it back with Call.
+func TestDeploy(t *testing.T) {
+	unittest.RequireSolc(t)
+
+	key := unittest.PrivateKeyFixture(t)
+	ctx, _, client := startNode(t, key)
+
+	storage, err := contracts.Deploy(ctx, client, key, storageArtifact(t))
+	require.NoError(t, err)
+	require.NotEqual(t, common.Address{}, storage.Address())
+
+	var code string
+	require.NoError(t, client.CallContext(ctx, &code, model.ReceiptGetByteCode, storage.Address().Hex(), model.EthBlockLatest))
+	require.NotEqual(t, model.AccountEmptyContract, code)
+
+	value, err := storage.Call(ctx, "value")
+	require.NoError(t, err)
+	require.Len(t, value, 1)
+	require.Zero(t, value[0].(*big.Int).Sign())
+
+	// Consecutive transactions pick up the pending nonce without waiting for each other.
+	first, err := storage.Transact(ctx, "set", big.NewInt(7))
+	require.NoError(t, err)
+	second, err := storage.Transact(ctx, "set", big.NewInt(8))
+	require.NoError(t, err)
+
+	_, err = storage.WaitReceipt(ctx, first)
+	require.NoError(t, err)
+	receipt, err := storage.WaitReceipt(ctx, second)
+	require.NoError(t, err)
+	require.Len(t, receipt.Logs, 1, "set should emit ValueChanged")
+
+	value, err = storage.Call(ctx, "value")
+	require.NoError(t, err)
+	require.Equal(t, int64(8), value[0].(*big.Int).Int64())
+
+	// A second binding of the same address sees the same state.
+	bound, err := contracts.NewBoundContract(ctx, client, key, storage.Address(), storage.Artifact())
+	require.NoError(t, err)
+	value, err = bound.Call(ctx, "value")
+	require.NoError(t, err)
+	require.Equal(t, int64(8), value[0].(*big.Int).Int64())
+}
+
+// TestDeploy_InvalidArguments tests that arguments not matching the ABI are rejected before anything is sent.
+func TestDeploy_InvalidArguments(t *testing.T) {
+	unittest.RequireSolc(t)
+
+	key := unittest.PrivateKeyFixture(t)
+	ctx, _, client := startNode(t, key)
+	artifact := storageArtifact(t)
+
+	_, err := contracts.Deploy(ctx, client, key, artifact, big.NewInt(1))
+	require.ErrorContains(t, err, "pack constructor arguments of Storage")
+
+	storage, err := contracts.Deploy(ctx, client, key, artifact)
+	require.NoError(t, err)
+
+	_, err = storage.Call(ctx, "missing")
+	require.ErrorContains(t, err, "pack missing arguments")
+	_, err = storage.Transact(ctx, "set", "seven")
+	require.ErrorContains(t, err, "pack set arguments")
+}
+
+// failingReceipts is an eth RPC service that knows the chain ID but fails every receipt query.
+type failingReceipts struct{}
+
+// ChainId implements eth_chainId.
+func (failingReceipts) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(1337))
+}
+
+// GetTransactionReceipt implements eth_getTransactionReceipt.
+func (failingReceipts) GetTransactionReceipt(common.Hash) (map[string]interface{}, error) {
+	return nil, errors.New("receipts unavailable")
+}
+
+// TestWaitReceipt_RPCError tests that a wait that times out while the node
+// fails every receipt query reports the last RPC error.
+func TestWaitReceipt_RPCError(t *testing.T) {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", failingReceipts{}))
+	t.Cleanup(server.Stop)
+	client := rpc.DialInProc(server)
+	t.Cleanup(client.Close)
+
+	bound, err := contracts.NewBoundContract(
+		context.Background(), client, unittest.PrivateKeyFixture(t), unittest.RandomAddress(t), simpleTestRuntime(),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	_, err = bound.WaitReceipt(ctx, common.HexToHash("0x01"))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorContains(t, err, "receipts unavailable")
+}
@@ -0,0 +1,259 @@
+package contracts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// ArtifactCacheDirEnv is the environment variable naming the default
+	// directory of cached compilation artifacts (see CompileOptions.ArtifactCacheDir).
+	ArtifactCacheDirEnv = "SOLC_ARTIFACT_CACHE_DIR"
+	// cacheFormat versions the cache key and entry layout; bump it when either changes.
+	cacheFormat = 2
+	// cacheLockPollInterval is the delay between checks of a compilation lock held by another process.
+	cacheLockPollInterval = 50 * time.Millisecond
+	// cacheLockStaleAge is the age after which a compilation lock is considered
+	// abandoned, e.g. by a killed test process, and is taken over.
+	cacheLockStaleAge = 2 * time.Minute
+)
+
+// importPattern matches the path of a Solidity import directive in all its forms:
+// import "p"; import "p" as X; import * as X from "p"; import {A, B as C} from "p";
+var importPattern = regexp.MustCompile(`(?m)^\s*import\s+(?:[^"';]*\s+from\s+)?["']([^"']+)["']`)
+
+// DefaultArtifactCacheDir returns the directory compilation artifacts are cached
+// in when CompileOptions.ArtifactCacheDir is empty: the directory named by the
+// SOLC_ARTIFACT_CACHE_DIR environment variable, or a go-eth-localnet directory
+// in the user's cache directory. Returns an empty string if neither is available.
+func DefaultArtifactCacheDir() string {
+	if dir := os.Getenv(ArtifactCacheDirEnv); dir != "" {
+		return dir
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "go-eth-localnet", "solc-artifacts")
+}
+
+// artifactCache is an on-disk cache of compilation artifacts. Entries are
+// content-addressed by a key covering everything that affects the output, so
+// they never need to be invalidated.
+type artifactCache struct {
+	dir string
+}
+
+// cacheKeyInput is hashed into the key of a compilation.
+type cacheKeyInput struct {
+	Format         int               `json:"format"`
+	Compiler       string            `json:"compiler"`
+	CompilerSHA256 string            `json:"compilerSha256"`
+	Settings       Settings          `json:"settings"`
+	Remappings     []string          `json:"remappings"`
+	Roots          []string          `json:"roots"`
+	Sources        map[string]string `json:"sources"`
+}
+
+// key returns the cache key of compiling solPaths with the given compiler and
+// options: a hash of the compiler version and binary, the settings, the
+// remappings and the name and contents of every source unit, including those
+// imported transitively.
+// Returns an error if an import cannot be resolved, in which case the
+// compilation should not be cached.
+func (c *artifactCache) key(compiler *Compiler, opts CompileOptions, solPaths []string) (string, error) {
+	input := cacheKeyInput{
+		Format:     cacheFormat,
+		Compiler:   compiler.Version.String(),
+		Settings:   opts.Settings,
+		Remappings: opts.Remappings,
+		Sources:    make(map[string]string),
+	}
+	binary, err := os.ReadFile(compiler.Path)
+	if err != nil {
+		return "", fmt.Errorf("read solc binary: %w", err)
+	}
+	binarySum := sha256.Sum256(binary)
+	input.CompilerSHA256 = hex.EncodeToString(binarySum[:])
+
+	// queue holds source unit names and the files they were read from.
+	type unit struct{ name, file string }
+	var queue []unit
+	for _, solPath := range solPaths {
+		name := sourceUnitName(opts.BasePath, solPath)
+		input.Roots = append(input.Roots, name)
+		queue = append(queue, unit{name: name, file: solPath})
+	}
+	sort.Strings(input.Roots)
+
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+		if _, ok := input.Sources[u.name]; ok {
+			continue
+		}
+		content, err := os.ReadFile(u.file)
+		if err != nil {
+			return "", fmt.Errorf("read %s: %w", u.file, err)
+		}
+		sum := sha256.Sum256(content)
+		input.Sources[u.name] = hex.EncodeToString(sum[:])
+
+		for _, m := range importPattern.FindAllSubmatch(content, -1) {
+			name := resolveImport(u.name, string(m[1]), opts.Remappings)
+			file, err := locateSource(name, opts)
+			if err != nil {
+				return "", err
+			}
+			queue = append(queue, unit{name: name, file: file})
+		}
+	}
+
+	encoded, err := json.Marshal(input)
+	if err != nil {
+		return "", fmt.Errorf("marshal cache key: %w", err)
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// resolveImport returns the source unit name of an import of importPath from
+// the source unit importer, resolving relative paths and then applying the
+// remappings as solc does.
+func resolveImport(importer string, importPath string, remappings []string) string {
+	name := importPath
+	if strings.HasPrefix(importPath, "./") || strings.HasPrefix(importPath, "../") {
+		name = path.Join(path.Dir(importer), importPath)
+	}
+
+	// The remapping with the longest context, then the longest prefix, wins.
+	var bestContext, bestPrefix, bestTarget string
+	found := false
+	for _, remapping := range remappings {
+		lhs, target, _ := strings.Cut(remapping, "=")
+		context, prefix, hasContext := strings.Cut(lhs, ":")
+		if !hasContext {
+			context, prefix = "", lhs
+		}
+		if !strings.HasPrefix(importer, context) || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if !found || len(context) > len(bestContext) ||
+			(len(context) == len(bestContext) && len(prefix) > len(bestPrefix)) {
+			bestContext, bestPrefix, bestTarget = context, prefix, target
+			found = true
+		}
+	}
+	if found {
+		name = bestTarget + strings.TrimPrefix(name, bestPrefix)
+	}
+	return name
+}
+
+// locateSource returns the file solc loads the source unit name from: the
+// name itself if absolute, otherwise the first match under the base path,
+// or the working directory if there is none, and the include paths.
+func locateSource(name string, opts CompileOptions) (string, error) {
+	if filepath.IsAbs(name) {
+		return name, nil
+	}
+	for _, dir := range append([]string{opts.BasePath}, opts.IncludePaths...) {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if _, err := os.Stat(file); err == nil {
+			return file, nil
+		}
+	}
+	return "", fmt.Errorf("import %q not found in base path or include paths", name)
+}
+
+// entryPath returns the path of the cache entry with the given key.
+func (c *artifactCache) entryPath(key string) string {
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+// load returns the cached artifacts of the given key, or false if there are none.
+// Unreadable entries are treated as missing and are overwritten by the next store.
+func (c *artifactCache) load(key string) (Artifacts, bool) {
+	content, err := os.ReadFile(c.entryPath(key))
+	if err != nil {
+		return nil, false
+	}
+	var artifacts Artifacts
+	if err := json.Unmarshal(content, &artifacts); err != nil {
+		return nil, false
+	}
+	return artifacts, true
+}
+
+// store writes the artifacts of the given key. The entry is written to a
+// temporary file and renamed into place, so readers never see a partial entry.
+func (c *artifactCache) store(key string, artifacts Artifacts) error {
+	entry := c.entryPath(key)
+	if err := os.MkdirAll(filepath.Dir(entry), 0755); err != nil {
+		return fmt.Errorf("create artifact cache dir: %w", err)
+	}
+	content, err := json.Marshal(artifacts)
+	if err != nil {
+		return fmt.Errorf("marshal artifacts: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(entry), key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create artifact cache entry: %w", err)
+	}
+	_, writeErr := tmp.Write(content)
+	closeErr := tmp.Close()
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write artifact cache entry: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), entry); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("commit artifact cache entry: %w", err)
+	}
+	return nil
+}
+
+// lock acquires the compilation lock of the given key, so concurrent
+// compilations of the same sources, in this or another process, run once.
+// It blocks until the lock is acquired or an entry for the key appears, in
+// which case the cached artifacts are returned and no lock is held.
+// Returns the function releasing the lock.
+func (c *artifactCache) lock(key string) (Artifacts, func(), error) {
+	lockPath := c.entryPath(key) + ".lock"
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+		return nil, nil, fmt.Errorf("create artifact cache dir: %w", err)
+	}
+
+	for {
+		file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			_ = file.Close()
+			return nil, func() {
+				_ = os.Remove(lockPath)
+			}, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, nil, fmt.Errorf("create compilation lock: %w", err)
+		}
+
+		if artifacts, ok := c.load(key); ok {
+			return artifacts, nil, nil
+		}
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > cacheLockStaleAge {
+			_ = os.Remove(lockPath)
+			continue
+		}
+		time.Sleep(cacheLockPollInterval)
+	}
+}
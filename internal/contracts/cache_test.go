@@ -0,0 +1,119 @@
+package contracts_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/thep2p/go-eth-localnet/internal/contracts"
+	"github.com/thep2p/go-eth-localnet/internal/unittest"
+)
+
+// compilerCalls returns how many times the stub compilers of a cache directory ran.
+func compilerCalls(t *testing.T, compilerDir string) int {
+	t.Helper()
+
+	content, err := os.ReadFile(filepath.Join(compilerDir, "calls.log"))
+	if os.IsNotExist(err) {
+		return 0
+	}
+	require.NoError(t, err)
+	return strings.Count(string(content), "\n")
+}
+
+// copyTestdata copies the testdata directory to a temporary directory, so tests can modify the sources.
+func copyTestdata(t *testing.T) string {
+	t.Helper()
+
+	tmp := unittest.NewTempDir(t)
+	t.Cleanup(tmp.Remove)
+	require.NoError(t, os.CopyFS(tmp.Path(), os.DirFS("testdata")))
+	return tmp.Path()
+}
+
+// TestCompile_CachesArtifacts tests that a compilation is served from the cache
+// until the sources, including imported ones, or the settings change.
+func TestCompile_CachesArtifacts(t *testing.T) {
+	compilerDir := newCompilerCache(t, "solc-0.8.30")
+	sources := copyTestdata(t)
+	artifactDir := unittest.NewTempDir(t)
+	t.Cleanup(artifactDir.Remove)
+
+	opts := contracts.CompileOptions{
+		Remappings:       []string{"@arith/=lib/arith/"},
+		BasePath:         sources,
+		IncludePaths:     []string{filepath.Join(sources, "include")},
+		CompilerCacheDir: compilerDir,
+		ArtifactCacheDir: artifactDir.Path(),
+	}
+	calculator := filepath.Join(sources, "Calculator.sol")
+	compile := func(opts contracts.CompileOptions) contracts.Artifacts {
+		artifacts, err := contracts.Compile(opts, calculator)
+		require.NoError(t, err)
+		return artifacts
+	}
+
+	first := compile(opts)
+	require.Equal(t, 1, compilerCalls(t, compilerDir))
+	require.Equal(t, first, compile(opts), "cached artifacts should match the compiled ones")
+	require.Equal(t, 1, compilerCalls(t, compilerDir), "the second compilation should be cached")
+
+	// Changing a file imported through a remapping invalidates the entry.
+	adder := filepath.Join(sources, "lib", "arith", "Adder.sol")
+	content, err := os.ReadFile(adder)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(adder, append(content, []byte("\n// changed\n")...), 0644))
+	compile(opts)
+	require.Equal(t, 2, compilerCalls(t, compilerDir))
+
+	// So does a file imported through an include path.
+	unit := filepath.Join(sources, "include", "shapes", "Unit.sol")
+	content, err = os.ReadFile(unit)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(unit, append(content, []byte("\n// changed\n")...), 0644))
+	compile(opts)
+	require.Equal(t, 3, compilerCalls(t, compilerDir))
+
+	optimized := opts
+	optimized.Settings.Optimizer.Enabled = true
+	compile(optimized)
+	require.Equal(t, 4, compilerCalls(t, compilerDir), "different settings should not share an entry")
+	compile(optimized)
+	require.Equal(t, 4, compilerCalls(t, compilerDir))
+
+	uncached := opts
+	uncached.NoCache = true
+	compile(uncached)
+	require.Equal(t, 5, compilerCalls(t, compilerDir), "NoCache should always compile")
+}
+
+// TestCompile_CacheParallel tests that concurrent compilations of the same
+// sources run the compiler once and all return the same artifacts.
+func TestCompile_CacheParallel(t *testing.T) {
+	compilerDir := newCompilerCache(t, "solc-0.8.30")
+	artifactDir := unittest.NewTempDir(t)
+	t.Cleanup(artifactDir.Remove)
+	opts := contracts.CompileOptions{CompilerCacheDir: compilerDir, ArtifactCacheDir: artifactDir.Path()}
+
+	const compilations = 8
+	results := make([]contracts.Artifacts, compilations)
+	errs := make([]error, compilations)
+	var wg sync.WaitGroup
+	for i := 0; i < compilations; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = contracts.Compile(opts, "SimpleStorageContract.sol")
+		}()
+	}
+	wg.Wait()
+
+	for i := 0; i < compilations; i++ {
+		require.NoError(t, errs[i])
+		require.Equal(t, results[0], results[i])
+	}
+	require.Equal(t, 1, compilerCalls(t, compilerDir))
+}
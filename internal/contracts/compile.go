@@ -1,59 +1,305 @@
 package contracts
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
 )
 
+// Artifact is a contract compiled by solc.
+type Artifact struct {
+	// Name is the contract name, e.g. Storage.
+	Name string
+	// SourcePath is the source unit that defines the contract, as reported by solc.
+	SourcePath string
+	// ABI is the JSON ABI of the contract.
+	ABI string
+	// Bin is the hex-encoded creation bytecode of the contract, without 0x prefix.
+	Bin string
+	// BinRuntime is the hex-encoded runtime bytecode deployed on chain, without 0x prefix.
+	BinRuntime string
+	// SrcMap is the source mapping of Bin.
+	SrcMap string
+	// SrcMapRuntime is the source mapping of BinRuntime.
+	SrcMapRuntime string
+	// ImmutableReferences are the ranges of BinRuntime holding immutable
+	// variables, keyed by the AST id of their declaration. They are zero in
+	// BinRuntime and set by the constructor on deployment.
+	ImmutableReferences map[string][]CodeRange
+	// StorageLayout is the layout of the contract's state variables.
+	StorageLayout *StorageLayout
+	// DevDoc is the developer documentation of the contract.
+	DevDoc *DevDoc
+	// CompilerVersion is the version of the solc that compiled the contract.
+	CompilerVersion Version
+}
+
+// Key returns the key of the artifact in Artifacts, "SourcePath:Name".
+func (a *Artifact) Key() string {
+	return a.SourcePath + ":" + a.Name
+}
+
+// Artifacts are the contracts of a compilation keyed by "file:ContractName",
+// e.g. "SimpleStorageContract.sol:Storage". They include the contracts of
+// imported files.
+type Artifacts map[string]*Artifact
+
+// Contract selects a contract by name, either fully qualified as
+// "file:ContractName" or by contract name alone.
+// Returns an error if no contract matches, or if the bare name is defined in
+// several files and must be qualified.
+func (a Artifacts) Contract(name string) (*Artifact, error) {
+	if artifact, ok := a[name]; ok {
+		return artifact, nil
+	}
+
+	var matches []*Artifact
+	for _, artifact := range a {
+		if artifact.Name == name {
+			matches = append(matches, artifact)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("contract %q not found, compiled contracts: %s", name, strings.Join(a.Keys(), ", "))
+	case 1:
+		return matches[0], nil
+	default:
+		keys := make([]string, 0, len(matches))
+		for _, artifact := range matches {
+			keys = append(keys, artifact.Key())
+		}
+		sort.Strings(keys)
+		return nil, fmt.Errorf("contract name %q is ambiguous, qualify it as one of: %s", name, strings.Join(keys, ", "))
+	}
+}
+
+// Keys returns the keys of the artifacts in ascending order.
+func (a Artifacts) Keys() []string {
+	keys := make([]string, 0, len(a))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// CompileOptions configure how solc resolves imports and compiles the sources.
+type CompileOptions struct {
+	// Remappings are import remappings of the form "prefix=target", e.g.
+	// "@openzeppelin/=lib/openzeppelin-contracts/". Targets outside the base
+	// path and include paths are allowed automatically.
+	Remappings []string
+	// BasePath is the root directory imports and source files are resolved
+	// against. Empty means the working directory.
+	BasePath string
+	// IncludePaths are additional directories searched for imports, e.g.
+	// node_modules. solc requires BasePath to be set along with them.
+	IncludePaths []string
+	// Settings are the compiler settings, e.g. optimizer and EVM version.
+	Settings Settings
+	// CompilerCacheDir is the directory of solc binaries the compiler is
+	// selected from by the version pragmas of the sources (see SelectCompiler).
+	// Empty means the directory named by the SOLC_CACHE_DIR environment
+	// variable, or, if that is unset too, the solc on PATH.
+	CompilerCacheDir string
+	// ArtifactCacheDir is the directory compilation artifacts are cached in,
+	// keyed by the sources, including imports, the compiler and the settings.
+	// Empty means DefaultArtifactCacheDir.
+	ArtifactCacheDir string
+	// NoCache compiles without reading or writing the artifact cache.
+	NoCache bool
+}
+
+// Compile compiles the given Solidity files in a single solc --standard-json
+// invocation and returns the artifacts of every contract they define or import.
+//
+// Args:
+//   - opts: import resolution options and compiler settings
+//   - solPaths: paths of the Solidity files to compile
+//
+// Returns the artifacts keyed by "file:ContractName". All errors indicate that
+// no solc satisfies the version pragmas, a file does not exist, or the
+// compilation failed.
+func Compile(opts CompileOptions, solPaths ...string) (Artifacts, error) {
+	if len(solPaths) == 0 {
+		return nil, fmt.Errorf("no solidity files to compile")
+	}
+	for _, remapping := range opts.Remappings {
+		if _, target, ok := strings.Cut(remapping, "="); !ok || target == "" {
+			return nil, fmt.Errorf("invalid remapping %q, expected prefix=target", remapping)
+		}
+	}
+	if len(opts.IncludePaths) > 0 && opts.BasePath == "" {
+		return nil, fmt.Errorf("include paths require a base path")
+	}
+	compilerDir := opts.CompilerCacheDir
+	if compilerDir == "" {
+		compilerDir = os.Getenv(CompilerCacheDirEnv)
+	}
+	compiler, err := SelectCompiler(compilerDir, solPaths...)
+	if err != nil {
+		return nil, err
+	}
+
+	artifactDir := opts.ArtifactCacheDir
+	if artifactDir == "" {
+		artifactDir = DefaultArtifactCacheDir()
+	}
+	if opts.NoCache || artifactDir == "" {
+		return compile(compiler, opts, solPaths)
+	}
+
+	// The cache only saves work, so any failure to use it falls back to
+	// compiling; imports that cannot be resolved are then reported by solc.
+	cache := &artifactCache{dir: artifactDir}
+	key, err := cache.key(compiler, opts, solPaths)
+	if err != nil {
+		return compile(compiler, opts, solPaths)
+	}
+	if artifacts, ok := cache.load(key); ok {
+		return artifacts, nil
+	}
+	artifacts, unlock, err := cache.lock(key)
+	if err != nil {
+		return compile(compiler, opts, solPaths)
+	}
+	if artifacts != nil {
+		return artifacts, nil
+	}
+	defer unlock()
+	// Another compilation may have stored the entry before the lock was taken.
+	if artifacts, ok := cache.load(key); ok {
+		return artifacts, nil
+	}
+
+	artifacts, err = compile(compiler, opts, solPaths)
+	if err != nil {
+		return nil, err
+	}
+	_ = cache.store(key, artifacts)
+	return artifacts, nil
+}
+
+// compile runs compiler on solPaths and returns the artifacts, bypassing the artifact cache.
+func compile(compiler *Compiler, opts CompileOptions, solPaths []string) (Artifacts, error) {
+	allowPaths := make([]string, 0, len(opts.Remappings))
+	for _, remapping := range opts.Remappings {
+		_, target, _ := strings.Cut(remapping, "=")
+		allowPaths = append(allowPaths, target)
+	}
+
+	input := standardInput{
+		Language: "Solidity",
+		Sources:  make(map[string]standardSource, len(solPaths)),
+		Settings: newStandardSettings(opts.Remappings, opts.Settings),
+	}
+	for _, solPath := range solPaths {
+		content, err := os.ReadFile(solPath)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", solPath, err)
+		}
+		input.Sources[sourceUnitName(opts.BasePath, solPath)] = standardSource{Content: string(content)}
+	}
+	inputJSON, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("marshal solc input: %w", err)
+	}
+
+	// Imports are read by solc itself from the base path and include paths.
+	args := []string{"--standard-json"}
+	if opts.BasePath != "" {
+		args = append(args, "--base-path", opts.BasePath)
+	}
+	for _, includePath := range opts.IncludePaths {
+		args = append(args, "--include-path", includePath)
+	}
+	if len(allowPaths) > 0 {
+		args = append(args, "--allow-paths", strings.Join(allowPaths, ","))
+	}
+
+	cmd := exec.Command(compiler.Path, args...)
+	cmd.Stdin = bytes.NewReader(inputJSON)
+	output, err := cmd.Output()
+	if err != nil {
+		var stderr []byte
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			stderr = exitErr.Stderr
+		}
+		return nil, fmt.Errorf("solc failed: %w\nOutput: %s", err, string(stderr))
+	}
+	artifacts, err := parseStandardOutput(output)
+	if err != nil {
+		return nil, err
+	}
+	for _, artifact := range artifacts {
+		artifact.CompilerVersion = compiler.Version
+	}
+	return artifacts, nil
+}
+
+// sourceUnitName returns the name solc knows a source file by: its path
+// relative to basePath, as solc names files given on the command line, or
+// the cleaned path if it is not within basePath.
+func sourceUnitName(basePath string, solPath string) string {
+	if basePath != "" {
+		if rel, err := filepath.Rel(basePath, solPath); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(solPath))
+}
+
 // GenerateAbiAndBin compiles a Solidity contract and returns its ABI and binary code or an error.
+// The file must define exactly one contract; contracts of imported files are
+// ignored. Use Compile to select among several contracts.
 // Args:
 //   - solPath: Path to the Solidity file to compile.
 //
 // Returns:
 //   - contractBin: The binary code of the compiled contract.
 //   - contractABI: The ABI of the compiled contract.
-//   - err: An error if the compilation fails or if the file does not exist.
+//   - err: An error if the compilation fails, if the file does not exist, or if
+//     the file does not define exactly one contract.
 func GenerateAbiAndBin(solPath string) (
 	contractBin string,
 	contractABI string,
 	err error) {
 
-	// Step 1a: Check if solc is installed
-	if _, err := exec.LookPath("solc"); err != nil {
-		return "", "", fmt.Errorf("solc not found in PATH: %w", err)
-	}
-
-	// Step 1b: Check if the provided file exists
-	if _, err := os.Stat(solPath); err != nil {
-		return "", "", fmt.Errorf("file not found: %w", err)
-	}
-
-	// Step 2: Run solc
-	solcCmd := exec.Command("solc", "--combined-json", "abi,bin", "--metadata-hash", "none", solPath)
-	output, err := solcCmd.CombinedOutput()
+	artifacts, err := Compile(CompileOptions{}, solPath)
 	if err != nil {
-		return "", "", fmt.Errorf("solc failed: %w\nOutput: %s", err, string(output))
+		return "", "", err
 	}
 
-	// Step 3: Parse solc output
-	var combined struct {
-		Contracts map[string]struct {
-			ABI json.RawMessage `json:"abi"`
-			Bin string          `json:"bin"`
-		} `json:"contracts"`
+	// solc reports the file as a source unit name, which may differ from
+	// solPath in form but not in the file it refers to.
+	var defined []*Artifact
+	for _, artifact := range artifacts {
+		if sameFile(artifact.SourcePath, solPath) {
+			defined = append(defined, artifact)
+		}
 	}
-	if err := json.Unmarshal(output, &combined); err != nil {
-		return "", "", fmt.Errorf("unmarshal solc output: %w", err)
+	switch len(defined) {
+	case 0:
+		return "", "", fmt.Errorf("compiled contract not found")
+	case 1:
+		return defined[0].Bin, defined[0].ABI, nil
+	default:
+		return "", "", fmt.Errorf("%s defines %d contracts, use Compile to select one", solPath, len(defined))
 	}
+}
 
-	// solc uses the provided file path as part of the map key which may include
-	// an absolute path. Since only one contract is being compiled, return the
-	// first entry.
-	for _, c := range combined.Contracts {
-		return c.Bin, string(c.ABI), nil
+// sameFile reports whether the source unit name reported by solc refers to path.
+func sameFile(sourceUnit string, path string) bool {
+	if filepath.Clean(sourceUnit) == filepath.Clean(path) {
+		return true
 	}
-
-	return "", "", fmt.Errorf("compiled contract not found")
+	a, errA := filepath.Abs(sourceUnit)
+	b, errB := filepath.Abs(path)
+	return errA == nil && errB == nil && a == b
 }
@@ -1,14 +1,17 @@
 package contracts_test
 
 import (
-	"github.com/thep2p/go-eth-localnet/internal/contracts"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/thep2p/go-eth-localnet/internal/contracts"
+	"github.com/thep2p/go-eth-localnet/internal/unittest"
 )
 
 // TestCompileFromStr_Success tests the successful compilation of a Solidity contract.
 func TestCompileFromStr_Success(t *testing.T) {
+	unittest.RequireSolc(t)
+
 	bin, abi, err := contracts.GenerateAbiAndBin("SimpleTestContract.sol")
 	require.NoError(t, err)
 	require.NotEmpty(t, bin)
@@ -33,8 +36,133 @@ func TestGenerateAbiAndBin_FileNotFound(t *testing.T) {
 
 // TestGenerateAbiAndBin_EmptyContracts tests the case where the Solidity file has an empty contract.
 func TestGenerateAbiAndBin_EmptyContracts(t *testing.T) {
+	unittest.RequireSolc(t)
+
 	bin, abi, err := contracts.GenerateAbiAndBin("EmptyContract.sol")
 	require.NoError(t, err)
 	require.Equal(t, "6080604052348015600e575f5ffd5b50601580601a5f395ff3fe60806040525f5ffdfea164736f6c634300081e000a", bin)
 	require.Equal(t, "[{\"inputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"}]", abi)
 }
+
+// TestCompile_MultipleContracts tests that every contract of a multi-file
+// compilation is returned, with imports resolved through remappings and include paths.
+func TestCompile_MultipleContracts(t *testing.T) {
+	unittest.RequireSolc(t)
+
+	artifacts, err := contracts.Compile(
+		contracts.CompileOptions{
+			Remappings:   []string{"@arith/=lib/arith/"},
+			BasePath:     "testdata",
+			IncludePaths: []string{"testdata/include"},
+		},
+		"testdata/Calculator.sol",
+	)
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		[]string{"Calculator.sol:Calculator", "Calculator.sol:Counter", "lib/arith/Adder.sol:Adder", "shapes/Unit.sol:Counter", "shapes/Unit.sol:Unit"},
+		artifacts.Keys(),
+	)
+
+	calculator, err := artifacts.Contract("Calculator")
+	require.NoError(t, err)
+	require.Equal(t, "Calculator", calculator.Name)
+	require.Equal(t, "Calculator.sol", calculator.SourcePath)
+	require.NotEmpty(t, calculator.Bin)
+	require.Contains(t, calculator.ABI, "\"name\":\"add\"")
+
+	counter, err := artifacts.Contract("shapes/Unit.sol:Counter")
+	require.NoError(t, err)
+	require.Equal(t, "shapes/Unit.sol", counter.SourcePath)
+
+	_, err = artifacts.Contract("Counter")
+	require.ErrorContains(t, err, "ambiguous")
+	_, err = artifacts.Contract("Missing")
+	require.ErrorContains(t, err, "not found")
+}
+
+// TestCompile_InvalidOptions tests that malformed options are rejected before solc runs.
+func TestCompile_InvalidOptions(t *testing.T) {
+	_, err := contracts.Compile(contracts.CompileOptions{})
+	require.ErrorContains(t, err, "no solidity files")
+
+	_, err = contracts.Compile(contracts.CompileOptions{Remappings: []string{"@arith/"}}, "testdata/Calculator.sol")
+	require.ErrorContains(t, err, "invalid remapping")
+
+	_, err = contracts.Compile(contracts.CompileOptions{IncludePaths: []string{"testdata/include"}}, "testdata/Calculator.sol")
+	require.ErrorContains(t, err, "require a base path")
+}
+
+// TestGenerateAbiAndBin_MultipleContracts tests that a file defining several contracts is rejected
+// instead of returning an arbitrary one.
+func TestGenerateAbiAndBin_MultipleContracts(t *testing.T) {
+	unittest.RequireSolc(t)
+
+	_, _, err := contracts.GenerateAbiAndBin("testdata/include/shapes/Unit.sol")
+	require.ErrorContains(t, err, "defines 2 contracts")
+}
+
+// TestCompile_Outputs tests the runtime bytecode, source maps, storage layout and
+// developer documentation of a compiled contract.
+func TestCompile_Outputs(t *testing.T) {
+	unittest.RequireSolc(t)
+
+	artifacts, err := contracts.Compile(contracts.CompileOptions{}, "testdata/Vault.sol")
+	require.NoError(t, err)
+	vault, err := artifacts.Contract("Vault")
+	require.NoError(t, err)
+
+	require.NotEmpty(t, vault.BinRuntime)
+	require.Contains(t, vault.Bin, vault.BinRuntime, "creation bytecode should embed the runtime bytecode")
+	require.NotEmpty(t, vault.SrcMap)
+	require.NotEmpty(t, vault.SrcMapRuntime)
+
+	require.NotNil(t, vault.StorageLayout)
+	require.Len(t, vault.StorageLayout.Storage, 3)
+	owner, locked, balances := vault.StorageLayout.Storage[0], vault.StorageLayout.Storage[1], vault.StorageLayout.Storage[2]
+	require.Equal(t, "owner", owner.Label)
+	require.Equal(t, "testdata/Vault.sol:Vault", owner.Contract)
+	require.Equal(t, "locked", locked.Label)
+	require.Equal(t, "0", locked.Slot, "locked should be packed into the slot of owner")
+	require.Equal(t, 20, locked.Offset)
+	require.Equal(t, "balances", balances.Label)
+	require.Equal(t, "1", balances.Slot)
+	mapping := vault.StorageLayout.Types[balances.Type]
+	require.Equal(t, "mapping", mapping.Encoding)
+	require.Equal(t, "uint256", vault.StorageLayout.Types[mapping.Value].Label)
+
+	require.NotNil(t, vault.DevDoc)
+	require.Equal(t, "A vault of balances", vault.DevDoc.Title)
+	require.Equal(t, "go-eth-localnet", vault.DevDoc.Author)
+	credit := vault.DevDoc.Methods["credit(address,uint256)"]
+	require.Equal(t, "Credits amount to the balance of account.", credit.Details)
+	require.Equal(t, "The account to credit.", credit.Params["account"])
+	require.Equal(t, "The new balance of account.", credit.Returns["balance"])
+}
+
+// TestCompile_Settings tests that optimizer, EVM version and via-IR settings reach the compiler.
+func TestCompile_Settings(t *testing.T) {
+	unittest.RequireSolc(t)
+
+	compile := func(settings contracts.Settings) *contracts.Artifact {
+		artifacts, err := contracts.Compile(contracts.CompileOptions{Settings: settings}, "testdata/Vault.sol")
+		require.NoError(t, err)
+		vault, err := artifacts.Contract("Vault")
+		require.NoError(t, err)
+		return vault
+	}
+
+	unoptimized := compile(contracts.Settings{})
+	optimized := compile(contracts.Settings{Optimizer: contracts.Optimizer{Enabled: true, Runs: 1}})
+	require.Less(t, len(optimized.Bin), len(unoptimized.Bin), "the optimizer should shrink the bytecode")
+
+	viaIR := compile(contracts.Settings{Optimizer: contracts.Optimizer{Enabled: true}, ViaIR: true})
+	require.NotEqual(t, optimized.BinRuntime, viaIR.BinRuntime)
+
+	// PUSH0 (0x5f) was introduced in shanghai, so paris bytecode is generated without it.
+	paris := compile(contracts.Settings{EVMVersion: "paris"})
+	require.NotEqual(t, unoptimized.Bin, paris.Bin)
+
+	_, err := contracts.Compile(contracts.CompileOptions{Settings: contracts.Settings{EVMVersion: "future"}}, "testdata/Vault.sol")
+	require.ErrorContains(t, err, "solc failed")
+}
@@ -0,0 +1,165 @@
+package contracts
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/thep2p/go-eth-localnet/internal/model"
+)
+
+// DeterministicDeployerAddress is the address of the standard deterministic
+// deployment proxy, the same on every chain it is deployed to.
+// cf. https://github.com/Arachnid/deterministic-deployment-proxy
+var DeterministicDeployerAddress = common.HexToAddress("0x4e59b44847b379578588920cA78FbF26c0B4956C")
+
+// DeterministicDeployerCode is the runtime bytecode of the deterministic
+// deployment proxy. Called with a 32-byte salt followed by init code, it
+// creates the contract with CREATE2 and returns its 20-byte address; it
+// reverts without data if the creation fails.
+const DeterministicDeployerCode = "0x7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffe03601600081602082378035828234f58015156039578182fd5b8082525050506014600cf3"
+
+// InitCode returns the creation bytecode of a compiled contract followed by
+// the ABI-encoded constructor arguments, i.e. the input of a deployment.
+// Returns an error if the ABI is invalid, the arguments do not match the
+// constructor or the artifact has no bytecode.
+func InitCode(artifact *Artifact, args ...interface{}) ([]byte, error) {
+	parsed, err := abi.JSON(strings.NewReader(artifact.ABI))
+	if err != nil {
+		return nil, fmt.Errorf("parse abi of %s: %w", artifact.Name, err)
+	}
+	return packInitCode(parsed, artifact, args...)
+}
+
+// Create2Address returns the address CREATE2 assigns to a contract created
+// by deployer with salt and initCode, as specified by EIP-1014.
+func Create2Address(deployer common.Address, salt common.Hash, initCode []byte) common.Address {
+	return crypto.CreateAddress2(deployer, salt, crypto.Keccak256(initCode))
+}
+
+// DeterministicAddress returns the address DeployDeterministic deploys a
+// contract to. It depends only on the artifact's bytecode, the constructor
+// arguments and salt, not on the deployer account or its nonce.
+// Returns an error if the arguments do not match the constructor or the
+// artifact has no bytecode.
+func DeterministicAddress(artifact *Artifact, salt common.Hash, args ...interface{}) (common.Address, error) {
+	initCode, err := InitCode(artifact, args...)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return Create2Address(DeterministicDeployerAddress, salt, initCode), nil
+}
+
+// DeployDeterministic deploys a compiled contract through the deterministic
+// deployment proxy and waits until the deployment is mined, so it lands at
+// DeterministicAddress on every run and machine. The node must have the
+// proxy installed, e.g. by launching it with node.WithDeterministicDeployer.
+//
+// Deploying the same artifact, arguments and salt again is a no-op that
+// binds the existing contract, since CREATE2 cannot create it twice.
+//
+// Args:
+//   - ctx: bounds the deployment, including waiting for the receipt
+//   - client: RPC client of the node
+//   - key: key of the funded account that sends the deployment and signs later transactions
+//   - artifact: the compiled contract, e.g. from Artifacts.Contract
+//   - salt: distinguishes deployments of the same contract and arguments
+//   - args: constructor arguments, ABI-encoded according to the artifact's ABI
+//
+// Returns the contract bound at its deterministic address. All errors are
+// CRITICAL and indicate the proxy is not installed, the arguments do not
+// match the constructor, the transaction could not be sent, or the
+// deployment reverted.
+func DeployDeterministic(
+	ctx context.Context,
+	client *rpc.Client,
+	key *ecdsa.PrivateKey,
+	artifact *Artifact,
+	salt common.Hash,
+	args ...interface{},
+) (*BoundContract, error) {
+	return DeployDeterministicWithOptions(ctx, client, key, artifact, TransactOptions{}, salt, args...)
+}
+
+// DeployDeterministicWithOptions is DeployDeterministic with explicit
+// transaction options, e.g. to fund a payable constructor.
+func DeployDeterministicWithOptions(
+	ctx context.Context,
+	client *rpc.Client,
+	key *ecdsa.PrivateKey,
+	artifact *Artifact,
+	opts TransactOptions,
+	salt common.Hash,
+	args ...interface{},
+) (*BoundContract, error) {
+	contract, err := bindContract(ctx, client, key, common.Address{}, artifact)
+	if err != nil {
+		return nil, err
+	}
+	initCode, err := packInitCode(contract.abi, artifact, args...)
+	if err != nil {
+		return nil, err
+	}
+	address := Create2Address(DeterministicDeployerAddress, salt, initCode)
+
+	deployer, err := getCode(ctx, client, DeterministicDeployerAddress)
+	if err != nil {
+		return nil, err
+	}
+	if len(deployer) == 0 {
+		return nil, fmt.Errorf(
+			"deterministic deployment proxy not found at %s, launch the node with node.WithDeterministicDeployer",
+			DeterministicDeployerAddress.Hex(),
+		)
+	}
+	existing, err := getCode(ctx, client, address)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		contract.address = address
+		return contract, nil
+	}
+
+	txHash, err := contract.send(ctx, &DeterministicDeployerAddress, append(salt.Bytes(), initCode...), opts)
+	if err != nil {
+		return nil, fmt.Errorf("deploy %s: %w", artifact.Name, err)
+	}
+	if _, err := contract.WaitReceipt(ctx, txHash); err != nil {
+		return nil, fmt.Errorf("deploy %s: %w", artifact.Name, err)
+	}
+	contract.address = address
+	return contract, nil
+}
+
+// packInitCode returns the creation bytecode of artifact followed by the
+// constructor arguments packed with its parsed ABI.
+func packInitCode(parsed abi.ABI, artifact *Artifact, args ...interface{}) ([]byte, error) {
+	input, err := parsed.Pack("", args...)
+	if err != nil {
+		return nil, fmt.Errorf("pack constructor arguments of %s: %w", artifact.Name, err)
+	}
+	bin, err := hexutil.Decode("0x" + strings.TrimPrefix(artifact.Bin, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("decode bytecode of %s: %w", artifact.Name, err)
+	}
+	if len(bin) == 0 {
+		return nil, fmt.Errorf("%s has no bytecode, it may be abstract or an interface", artifact.Name)
+	}
+	return append(bin, input...), nil
+}
+
+// getCode returns the code deployed at address in the latest block, empty if none.
+func getCode(ctx context.Context, client *rpc.Client, address common.Address) ([]byte, error) {
+	var code hexutil.Bytes
+	if err := client.CallContext(ctx, &code, model.ReceiptGetByteCode, address.Hex(), model.EthBlockLatest); err != nil {
+		return nil, fmt.Errorf("get code at %s: %w", address.Hex(), err)
+	}
+	return code, nil
+}
@@ -0,0 +1,86 @@
+package contracts_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"github.com/thep2p/go-eth-localnet/internal/contracts"
+	"github.com/thep2p/go-eth-localnet/internal/node"
+	"github.com/thep2p/go-eth-localnet/internal/unittest"
+)
+
+// TestCreate2Address tests the CREATE2 address derivation against the examples of EIP-1014.
+func TestCreate2Address(t *testing.T) {
+	require.Equal(
+		t,
+		common.HexToAddress("0x4D1A2e2bB4F88F0250f26Ffff098B0b30B26BF38"),
+		contracts.Create2Address(common.Address{}, common.Hash{}, common.FromHex("0x00")),
+	)
+	require.Equal(
+		t,
+		common.HexToAddress("0x60f3f640a8508fC6a86d45DF051962668E1e8AC7"),
+		contracts.Create2Address(
+			common.HexToAddress("0x00000000000000000000000000000000deadbeef"),
+			common.HexToHash("0x00000000000000000000000000000000000000000000000000000000cafebabe"),
+			common.FromHex("0xdeadbeef"),
+		),
+	)
+}
+
+// TestDeterministicAddress tests that the address depends on the salt and the constructor arguments only.
+func TestDeterministicAddress(t *testing.T) {
+	artifact := simpleTestArtifacts()["SimpleTestContract.sol:SimpleTestContract"]
+
+	first, err := contracts.DeterministicAddress(artifact, common.Hash{})
+	require.NoError(t, err)
+	again, err := contracts.DeterministicAddress(artifact, common.Hash{})
+	require.NoError(t, err)
+	require.Equal(t, first, again)
+
+	salted, err := contracts.DeterministicAddress(artifact, common.HexToHash("0x01"))
+	require.NoError(t, err)
+	require.NotEqual(t, first, salted)
+
+	_, err = contracts.DeterministicAddress(artifact, common.Hash{}, big.NewInt(1))
+	require.ErrorContains(t, err, "pack constructor arguments of SimpleTestContract")
+	_, err = contracts.DeterministicAddress(&contracts.Artifact{Name: "I", ABI: "[]"}, common.Hash{})
+	require.ErrorContains(t, err, "I has no bytecode")
+}
+
+// TestDeployDeterministic tests that deployments through the proxy land at the
+// same address on separate nodes and from separate accounts.
+func TestDeployDeterministic(t *testing.T) {
+	artifact := simpleTestArtifacts()["SimpleTestContract.sol:SimpleTestContract"]
+	salt := common.HexToHash("0x2a")
+	expected, err := contracts.DeterministicAddress(artifact, salt)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		key := unittest.PrivateKeyFixture(t)
+		ctx, _, client := startNode(t, key, node.WithDeterministicDeployer())
+
+		c, err := contracts.DeployDeterministic(ctx, client, key, artifact, salt)
+		require.NoError(t, err)
+		require.Equal(t, expected, c.Address())
+		results, err := c.Call(ctx, "f")
+		require.NoError(t, err)
+		require.Equal(t, int64(42), results[0].(*big.Int).Int64())
+
+		// Deploying again binds the existing contract.
+		again, err := contracts.DeployDeterministic(ctx, client, key, artifact, salt)
+		require.NoError(t, err)
+		require.Equal(t, expected, again.Address())
+	}
+}
+
+// TestDeployDeterministic_WithoutProxy tests that deploying fails on a node without the proxy.
+func TestDeployDeterministic_WithoutProxy(t *testing.T) {
+	artifact := simpleTestArtifacts()["SimpleTestContract.sol:SimpleTestContract"]
+	key := unittest.PrivateKeyFixture(t)
+	ctx, _, client := startNode(t, key)
+
+	_, err := contracts.DeployDeterministic(ctx, client, key, artifact, common.Hash{})
+	require.ErrorContains(t, err, "deterministic deployment proxy not found")
+}
@@ -0,0 +1,209 @@
+package contracts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/thep2p/go-eth-localnet/internal/model"
+)
+
+// ErrUnknownEvent is returned when decoding a log that matches no event of the ABI.
+var ErrUnknownEvent = errors.New("unknown event")
+
+// Event is a log decoded with a contract ABI.
+type Event struct {
+	// Name is the event name, e.g. ValueChanged.
+	Name string
+	// Signature is the canonical event signature, e.g. ValueChanged(uint256).
+	Signature string
+	// Args holds the event arguments by ABI name, indexed and non-indexed.
+	// Indexed arguments of dynamic types (string, bytes, arrays) are only
+	// available as the common.Hash of their value.
+	Args map[string]interface{}
+	// Log is the raw log. Log.Removed is set for logs of blocks dropped by a
+	// reorg and streamed by a subscription.
+	Log types.Log
+
+	event abi.Event
+}
+
+// Unpack copies the event arguments into out, a pointer to a struct with a
+// field per argument named after it in CamelCase (or tagged `abi:"name"`),
+// as generated by Bind for the event.
+// Returns an error if the arguments do not fit the struct.
+func (e *Event) Unpack(out interface{}) error {
+	if nonIndexed := e.event.Inputs.NonIndexed(); len(nonIndexed) > 0 {
+		values, err := nonIndexed.Unpack(e.Log.Data)
+		if err != nil {
+			return fmt.Errorf("unpack %s data: %w", e.Name, err)
+		}
+		// Copy is called on all inputs, so that a struct receives the
+		// non-indexed values by name whenever the event has several inputs.
+		if err := e.event.Inputs.Copy(out, values); err != nil {
+			return fmt.Errorf("copy %s data: %w", e.Name, err)
+		}
+	}
+	if err := abi.ParseTopics(out, indexedInputs(e.event), e.Log.Topics[1:]); err != nil {
+		return fmt.Errorf("parse %s topics: %w", e.Name, err)
+	}
+	return nil
+}
+
+// EventDecoder decodes logs into Events using a contract ABI.
+type EventDecoder struct {
+	abi abi.ABI
+}
+
+// NewEventDecoder returns a decoder for the events of a compiled contract.
+// Returns an error if the artifact's ABI is invalid.
+func NewEventDecoder(artifact *Artifact) (*EventDecoder, error) {
+	parsed, err := abi.JSON(strings.NewReader(artifact.ABI))
+	if err != nil {
+		return nil, fmt.Errorf("parse abi of %s: %w", artifact.Name, err)
+	}
+	return &EventDecoder{abi: parsed}, nil
+}
+
+// Decode decodes a log.
+// Returns ErrUnknownEvent if the log matches no event of the ABI, or is
+// anonymous, and an error if its data or topics do not match the event.
+func (d *EventDecoder) Decode(log types.Log) (*Event, error) {
+	if len(log.Topics) == 0 {
+		return nil, fmt.Errorf("log without topics: %w", ErrUnknownEvent)
+	}
+	event, err := d.abi.EventByID(log.Topics[0])
+	if err != nil {
+		return nil, fmt.Errorf("topic %s: %w", log.Topics[0].Hex(), ErrUnknownEvent)
+	}
+
+	args := make(map[string]interface{})
+	if nonIndexed := event.Inputs.NonIndexed(); len(nonIndexed) > 0 {
+		if err := nonIndexed.UnpackIntoMap(args, log.Data); err != nil {
+			return nil, fmt.Errorf("unpack %s data: %w", event.RawName, err)
+		}
+	}
+	if err := abi.ParseTopicsIntoMap(args, indexedInputs(*event), log.Topics[1:]); err != nil {
+		return nil, fmt.Errorf("parse %s topics: %w", event.RawName, err)
+	}
+
+	return &Event{
+		Name:      event.RawName,
+		Signature: event.Sig,
+		Args:      args,
+		Log:       log,
+		event:     *event,
+	}, nil
+}
+
+// DecodeAll decodes the logs of known events, e.g. those of a receipt, in
+// order, and skips the logs of unknown events, which other contracts emitted
+// in the same transaction.
+// Returns an error if a log of a known event cannot be decoded.
+func (d *EventDecoder) DecodeAll(logs []*types.Log) ([]*Event, error) {
+	events := make([]*Event, 0, len(logs))
+	for _, log := range logs {
+		event, err := d.Decode(*log)
+		if errors.Is(err, ErrUnknownEvent) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// LogFilter selects logs for EventDecoder.FilterLogs and subscriptions.
+type LogFilter struct {
+	// Addresses restricts logs to those emitted by these contracts. Empty matches any contract.
+	Addresses []common.Address
+	// Events restricts logs to these events of the ABI, by name. Empty
+	// matches every non-anonymous event of the ABI.
+	Events []string
+	// FromBlock is the first block searched by FilterLogs. Nil means the
+	// earliest block. Subscriptions ignore it.
+	FromBlock *big.Int
+	// ToBlock is the last block searched by FilterLogs. Nil means the latest
+	// block. Subscriptions ignore it.
+	ToBlock *big.Int
+}
+
+// FilterArg returns the addresses and event topics of filter as the argument
+// of eth_getLogs or of the logs subscription of eth_subscribe. The block
+// range is included only where set.
+// Returns an error if an event is not in the ABI or is anonymous.
+func (d *EventDecoder) FilterArg(filter LogFilter) (map[string]interface{}, error) {
+	var ids []common.Hash
+	if len(filter.Events) == 0 {
+		for _, event := range d.abi.Events {
+			if !event.Anonymous {
+				ids = append(ids, event.ID)
+			}
+		}
+	}
+	for _, name := range filter.Events {
+		event, ok := d.abi.Events[name]
+		if !ok {
+			return nil, fmt.Errorf("event %q not found in abi", name)
+		}
+		if event.Anonymous {
+			return nil, fmt.Errorf("event %q is anonymous and cannot be filtered by name", name)
+		}
+		ids = append(ids, event.ID)
+	}
+
+	arg := map[string]interface{}{
+		model.LogFilterTopics: [][]common.Hash{ids},
+	}
+	if len(filter.Addresses) > 0 {
+		arg[model.LogFilterAddress] = filter.Addresses
+	}
+	if filter.FromBlock != nil {
+		arg[model.LogFilterFromBlock] = hexutil.EncodeBig(filter.FromBlock)
+	}
+	if filter.ToBlock != nil {
+		arg[model.LogFilterToBlock] = hexutil.EncodeBig(filter.ToBlock)
+	}
+	return arg, nil
+}
+
+// FilterLogs fetches the logs matching filter with eth_getLogs and decodes them.
+// Returns an error if the filter is invalid, the query fails or a log cannot be decoded.
+func (d *EventDecoder) FilterLogs(ctx context.Context, client *rpc.Client, filter LogFilter) ([]*Event, error) {
+	arg, err := d.FilterArg(filter)
+	if err != nil {
+		return nil, err
+	}
+	if filter.FromBlock == nil {
+		arg[model.LogFilterFromBlock] = model.EthBlockEarliest
+	}
+	if filter.ToBlock == nil {
+		arg[model.LogFilterToBlock] = model.EthBlockLatest
+	}
+
+	var logs []*types.Log
+	if err := client.CallContext(ctx, &logs, model.EthGetLogs, arg); err != nil {
+		return nil, fmt.Errorf("get logs: %w", err)
+	}
+	return d.DecodeAll(logs)
+}
+
+// indexedInputs returns the indexed arguments of an event, in topic order.
+func indexedInputs(event abi.Event) abi.Arguments {
+	var indexed abi.Arguments
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		}
+	}
+	return indexed
+}
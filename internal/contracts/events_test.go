@@ -0,0 +1,148 @@
+package contracts_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+	"github.com/thep2p/go-eth-localnet/internal/contracts"
+	"github.com/thep2p/go-eth-localnet/internal/contracts/bindings/storage"
+	"github.com/thep2p/go-eth-localnet/internal/unittest"
+)
+
+// tokenABI declares an ERC-20 style Transfer event with indexed and non-indexed arguments.
+const tokenABI = `[{"anonymous":false,"inputs":[{"indexed":true,"internalType":"address","name":"from","type":"address"},{"indexed":true,"internalType":"address","name":"to","type":"address"},{"indexed":false,"internalType":"uint256","name":"value","type":"uint256"}],"name":"Transfer","type":"event"}]`
+
+// transferLog returns a log of the Transfer event of tokenABI.
+func transferLog(t *testing.T, from, to common.Address, value int64) *types.Log {
+	t.Helper()
+
+	parsed, err := abi.JSON(strings.NewReader(tokenABI))
+	require.NoError(t, err)
+	event := parsed.Events["Transfer"]
+	data, err := event.Inputs.NonIndexed().Pack(big.NewInt(value))
+	require.NoError(t, err)
+	return &types.Log{
+		Topics: []common.Hash{event.ID, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+		Data:   data,
+	}
+}
+
+// TestEventDecoder tests that indexed and non-indexed arguments are decoded into Args and by Unpack.
+func TestEventDecoder(t *testing.T) {
+	decoder, err := contracts.NewEventDecoder(&contracts.Artifact{Name: "Token", ABI: tokenABI})
+	require.NoError(t, err)
+	addrs := unittest.RandomAddresses(t, 2)
+
+	event, err := decoder.Decode(*transferLog(t, addrs[0], addrs[1], 5))
+	require.NoError(t, err)
+	require.Equal(t, "Transfer", event.Name)
+	require.Equal(t, "Transfer(address,address,uint256)", event.Signature)
+	require.Equal(t, addrs[0], event.Args["from"])
+	require.Equal(t, addrs[1], event.Args["to"])
+	require.Equal(t, int64(5), event.Args["value"].(*big.Int).Int64())
+
+	var transfer struct {
+		From  common.Address
+		To    common.Address
+		Value *big.Int
+	}
+	require.NoError(t, event.Unpack(&transfer))
+	require.Equal(t, addrs[0], transfer.From)
+	require.Equal(t, addrs[1], transfer.To)
+	require.Equal(t, int64(5), transfer.Value.Int64())
+
+	_, err = decoder.Decode(types.Log{Topics: []common.Hash{common.HexToHash("0x01")}})
+	require.ErrorIs(t, err, contracts.ErrUnknownEvent)
+	_, err = decoder.Decode(types.Log{})
+	require.ErrorIs(t, err, contracts.ErrUnknownEvent)
+
+	truncated := transferLog(t, addrs[0], addrs[1], 5)
+	truncated.Data = truncated.Data[:16]
+	_, err = decoder.Decode(*truncated)
+	require.ErrorContains(t, err, "unpack Transfer data")
+}
+
+// TestEventDecoder_DecodeAll tests that logs of unknown events are skipped and the order is kept.
+func TestEventDecoder_DecodeAll(t *testing.T) {
+	decoder, err := contracts.NewEventDecoder(&contracts.Artifact{Name: "Token", ABI: tokenABI})
+	require.NoError(t, err)
+	addrs := unittest.RandomAddresses(t, 2)
+
+	events, err := decoder.DecodeAll(
+		[]*types.Log{
+			transferLog(t, addrs[0], addrs[1], 1),
+			{Topics: []common.Hash{common.HexToHash("0x01")}},
+			transferLog(t, addrs[1], addrs[0], 2),
+		},
+	)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.Equal(t, int64(1), events[0].Args["value"].(*big.Int).Int64())
+	require.Equal(t, int64(2), events[1].Args["value"].(*big.Int).Int64())
+}
+
+// TestEventDecoder_FilterArg tests that the filter selects the events of the ABI by topic.
+func TestEventDecoder_FilterArg(t *testing.T) {
+	decoder, err := contracts.NewEventDecoder(&contracts.Artifact{Name: "Storage", ABI: storage.StorageMetaData.ABI})
+	require.NoError(t, err)
+	address := unittest.RandomAddresses(t, 1)[0]
+
+	arg, err := decoder.FilterArg(contracts.LogFilter{Addresses: []common.Address{address}, FromBlock: big.NewInt(3)})
+	require.NoError(t, err)
+	parsed, err := storage.StorageMetaData.GetAbi()
+	require.NoError(t, err)
+	require.Equal(
+		t, map[string]interface{}{
+			"address":   []common.Address{address},
+			"topics":    [][]common.Hash{{parsed.Events["ValueChanged"].ID}},
+			"fromBlock": "0x3",
+		}, arg,
+	)
+
+	_, err = decoder.FilterArg(contracts.LogFilter{Events: []string{"Missing"}})
+	require.ErrorContains(t, err, `event "Missing" not found`)
+}
+
+// TestFilterEvents tests decoding receipt logs and eth_getLogs results of a deployed contract.
+func TestFilterEvents(t *testing.T) {
+	unittest.RequireSolc(t)
+
+	key := unittest.PrivateKeyFixture(t)
+	ctx, _, client := startNode(t, key)
+	s, err := contracts.Deploy(ctx, client, key, storageArtifact(t))
+	require.NoError(t, err)
+
+	var receipts []*types.Receipt
+	for _, v := range []int64{1, 2, 3} {
+		txHash, err := s.Transact(ctx, "set", big.NewInt(v))
+		require.NoError(t, err)
+		receipt, err := s.WaitReceipt(ctx, txHash)
+		require.NoError(t, err)
+		receipts = append(receipts, receipt)
+	}
+
+	events, err := s.DecodeLogs(receipts[0].Logs)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	var changed storage.StorageValueChanged
+	require.NoError(t, events[0].Unpack(&changed))
+	require.Equal(t, int64(1), changed.NewValue.Int64())
+
+	events, err = s.FilterEvents(ctx, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	for i, event := range events {
+		require.Equal(t, "ValueChanged", event.Name)
+		require.Equal(t, int64(i+1), event.Args["newValue"].(*big.Int).Int64())
+		require.Equal(t, receipts[i].TxHash, event.Log.TxHash)
+	}
+
+	events, err = s.FilterEvents(ctx, receipts[1].BlockNumber, nil, "ValueChanged")
+	require.NoError(t, err)
+	require.Len(t, events, 2, "the range should exclude the first event")
+}
@@ -0,0 +1,314 @@
+package contracts
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/thep2p/go-eth-localnet/internal/model"
+)
+
+// RevertKind classifies the data a reverted call or transaction returned.
+type RevertKind int
+
+const (
+	// RevertUnknown is a revert without data, e.g. a bare revert() or an
+	// out-of-gas failure, or with data matching no known error.
+	RevertUnknown RevertKind = iota
+	// RevertReason is an Error(string) revert, e.g. require(ok, "reason").
+	RevertReason
+	// RevertPanic is a Panic(uint256) revert, e.g. a failed assert or an arithmetic overflow.
+	RevertPanic
+	// RevertCustom is a custom error declared in the ABI, e.g. revert Unauthorized(caller).
+	RevertCustom
+)
+
+// String returns the name of the kind.
+func (k RevertKind) String() string {
+	switch k {
+	case RevertReason:
+		return "reason"
+	case RevertPanic:
+		return "panic"
+	case RevertCustom:
+		return "custom"
+	default:
+		return "unknown"
+	}
+}
+
+var (
+	// errorSelector is the selector of Error(string) revert data.
+	errorSelector = crypto.Keccak256([]byte("Error(string)"))[:4]
+	// panicSelector is the selector of Panic(uint256) revert data.
+	panicSelector = crypto.Keccak256([]byte("Panic(uint256)"))[:4]
+)
+
+// panicDescriptions describes the Panic(uint256) codes solc emits.
+// cf. https://docs.soliditylang.org/en/latest/control-structures.html#panic-via-assert-and-error-via-require
+var panicDescriptions = map[uint64]string{
+	0x00: "generic panic",
+	0x01: "assertion failed",
+	0x11: "arithmetic underflow or overflow",
+	0x12: "division or modulo by zero",
+	0x21: "invalid enum value",
+	0x22: "invalid encoded storage byte array",
+	0x31: "pop on an empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to an uninitialized function",
+}
+
+// RevertError is a decoded revert of an eth_call, an eth_estimateGas or a
+// mined transaction. Use errors.As to retrieve it from the errors of
+// BoundContract.
+type RevertError struct {
+	// Kind classifies the revert data.
+	Kind RevertKind
+	// Reason is the message of an Error(string) revert.
+	Reason string
+	// PanicCode is the code of a Panic(uint256) revert, e.g. 0x11 for an overflow.
+	PanicCode *big.Int
+	// Name is the name of a custom error, e.g. Unauthorized.
+	Name string
+	// Args holds the arguments of a custom error by ABI name.
+	Args map[string]interface{}
+	// Data is the raw revert data, empty if the node returned none.
+	Data []byte
+	// Message is the error message of the node, e.g. "out of gas" for a
+	// transaction that ran out of gas.
+	Message string
+	// TxHash is the hash of the reverted transaction, if the revert was
+	// decoded by replaying a mined transaction.
+	TxHash common.Hash
+
+	errorDef *abi.Error
+}
+
+// Error describes the revert, e.g. "execution reverted: Unauthorized(caller=0x...)".
+func (e *RevertError) Error() string {
+	prefix := "execution reverted"
+	if e.TxHash != (common.Hash{}) {
+		prefix = fmt.Sprintf("transaction %s reverted", e.TxHash.Hex())
+	}
+
+	switch e.Kind {
+	case RevertReason:
+		return fmt.Sprintf("%s: %s", prefix, e.Reason)
+	case RevertPanic:
+		return fmt.Sprintf("%s: panic %#x (%s)", prefix, e.PanicCode, PanicDescription(e.PanicCode))
+	case RevertCustom:
+		args := make([]string, 0, len(e.errorDef.Inputs))
+		for _, input := range e.errorDef.Inputs {
+			args = append(args, fmt.Sprintf("%s=%v", input.Name, e.Args[input.Name]))
+		}
+		return fmt.Sprintf("%s: %s(%s)", prefix, e.Name, strings.Join(args, ", "))
+	}
+	if len(e.Data) > 0 {
+		return fmt.Sprintf("%s: unknown error %s", prefix, hexutil.Encode(e.Data))
+	}
+	if e.Message != "" && e.TxHash != (common.Hash{}) {
+		return fmt.Sprintf("%s: %s", prefix, e.Message)
+	}
+	if e.Message != "" {
+		return e.Message
+	}
+	return prefix
+}
+
+// Unpack copies the arguments of a custom error into out, a pointer to a
+// struct with a field per argument named after it in CamelCase.
+// Returns an error if the revert is not a custom error or the arguments do not fit.
+func (e *RevertError) Unpack(out interface{}) error {
+	if e.Kind != RevertCustom {
+		return fmt.Errorf("revert is not a custom error but %s", e.Kind)
+	}
+	values, err := e.errorDef.Unpack(e.Data)
+	if err != nil {
+		return fmt.Errorf("unpack %s: %w", e.Name, err)
+	}
+	if err := e.errorDef.Inputs.Copy(out, values.([]interface{})); err != nil {
+		return fmt.Errorf("copy %s: %w", e.Name, err)
+	}
+	return nil
+}
+
+// PanicDescription describes a Panic(uint256) code, e.g. "division or modulo by zero" for 0x12.
+func PanicDescription(code *big.Int) string {
+	if code != nil && code.IsUint64() {
+		if description, ok := panicDescriptions[code.Uint64()]; ok {
+			return description
+		}
+	}
+	return "unknown panic code"
+}
+
+// RevertDecoder decodes revert data using the custom errors of contract ABIs.
+// Error(string) and Panic(uint256) are decoded without any ABI.
+type RevertDecoder struct {
+	errors map[[4]byte]*abi.Error
+}
+
+// NewRevertDecoder returns a decoder for the custom errors of the given
+// contracts. Pass every contract a call may pass through, as a revert
+// bubbles up from nested calls unchanged.
+// Returns an error if an artifact's ABI is invalid.
+func NewRevertDecoder(artifacts ...*Artifact) (*RevertDecoder, error) {
+	d := &RevertDecoder{errors: make(map[[4]byte]*abi.Error)}
+	for _, artifact := range artifacts {
+		parsed, err := abi.JSON(strings.NewReader(artifact.ABI))
+		if err != nil {
+			return nil, fmt.Errorf("parse abi of %s: %w", artifact.Name, err)
+		}
+		d.add(parsed)
+	}
+	return d, nil
+}
+
+// add registers the custom errors of an ABI.
+func (d *RevertDecoder) add(parsed abi.ABI) {
+	for _, errorDef := range parsed.Errors {
+		var selector [4]byte
+		copy(selector[:], errorDef.ID[:4])
+		d.errors[selector] = &errorDef
+	}
+}
+
+// Decode decodes revert data. Data that matches no known error, or does not
+// unpack as the error it claims to be, is returned as RevertUnknown.
+func (d *RevertDecoder) Decode(data []byte) *RevertError {
+	revert := &RevertError{Kind: RevertUnknown, Data: data}
+	if len(data) < 4 {
+		return revert
+	}
+
+	switch {
+	case bytes.Equal(data[:4], errorSelector):
+		if reason, err := abi.UnpackRevert(data); err == nil {
+			revert.Kind = RevertReason
+			revert.Reason = reason
+		}
+	case bytes.Equal(data[:4], panicSelector):
+		if values, err := (abi.Arguments{{Type: uint256Type}}).Unpack(data[4:]); err == nil {
+			revert.Kind = RevertPanic
+			revert.PanicCode = values[0].(*big.Int)
+		}
+	default:
+		var selector [4]byte
+		copy(selector[:], data[:4])
+		errorDef, ok := d.errors[selector]
+		if !ok {
+			return revert
+		}
+		args := make(map[string]interface{})
+		if err := errorDef.Inputs.UnpackIntoMap(args, data[4:]); err != nil {
+			return revert
+		}
+		revert.Kind = RevertCustom
+		revert.Name = errorDef.Name
+		revert.Args = args
+		revert.errorDef = errorDef
+	}
+	return revert
+}
+
+// FromError extracts and decodes the revert data of an error returned by
+// eth_call or eth_estimateGas. A revert without data, e.g. a bare revert(),
+// is returned as RevertUnknown.
+// Returns false if err is not a revert, e.g. an out-of-gas failure or a
+// connection failure.
+func (d *RevertDecoder) FromError(err error) (*RevertError, bool) {
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if encoded, ok := dataErr.ErrorData().(string); ok {
+			if data, decodeErr := hexutil.Decode(encoded); decodeErr == nil {
+				revert := d.Decode(data)
+				revert.Message = dataErr.Error()
+				return revert, true
+			}
+		}
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && strings.HasPrefix(rpcErr.Error(), vm.ErrExecutionReverted.Error()) {
+		return &RevertError{Kind: RevertUnknown, Message: rpcErr.Error()}, true
+	}
+	return nil, false
+}
+
+// Replay re-executes a mined transaction with eth_call on the state of its
+// parent block and decodes why it failed. The state differs from the one the
+// transaction saw if earlier transactions of the same block changed it.
+//
+// Args:
+//   - ctx: bounds the replay
+//   - client: RPC client of the node
+//   - txHash: hash of the failed transaction
+//
+// Returns the decoded revert; its Message is set instead of Data if the
+// transaction failed without revert data, e.g. out of gas. All errors are
+// CRITICAL and indicate the transaction was not found or not mined, or the
+// node could not be reached.
+func (d *RevertDecoder) Replay(ctx context.Context, client *rpc.Client, txHash common.Hash) (*RevertError, error) {
+	var tx *struct {
+		From        common.Address  `json:"from"`
+		To          *common.Address `json:"to"`
+		Input       hexutil.Bytes   `json:"input"`
+		Value       *hexutil.Big    `json:"value"`
+		Gas         hexutil.Uint64  `json:"gas"`
+		BlockNumber *hexutil.Big    `json:"blockNumber"`
+	}
+	if err := client.CallContext(ctx, &tx, model.EthGetTransactionByHash, txHash); err != nil {
+		return nil, fmt.Errorf("get transaction %s: %w", txHash.Hex(), err)
+	}
+	if tx == nil {
+		return nil, fmt.Errorf("transaction %s not found", txHash.Hex())
+	}
+	if tx.BlockNumber == nil {
+		return nil, fmt.Errorf("transaction %s is not mined", txHash.Hex())
+	}
+
+	args := map[string]string{
+		model.CallContextFrom: tx.From.Hex(),
+		model.CallContextData: hexutil.Encode(tx.Input),
+		model.CallContextGas:  hexutil.EncodeUint64(uint64(tx.Gas)),
+	}
+	if tx.To != nil {
+		args[model.CallContextTo] = tx.To.Hex()
+	}
+	if tx.Value != nil {
+		args[model.CallContextValue] = tx.Value.String()
+	}
+	parent := new(big.Int).Sub(tx.BlockNumber.ToInt(), big.NewInt(1))
+
+	var output hexutil.Bytes
+	err := client.CallContext(ctx, &output, model.CallContextEthCall, args, hexutil.EncodeBig(parent))
+	if revert, ok := d.FromError(err); ok {
+		revert.TxHash = txHash
+		return revert, nil
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		// The node executed the call, which failed without revert data.
+		return &RevertError{Kind: RevertUnknown, Message: rpcErr.Error(), TxHash: txHash}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("replay transaction %s: %w", txHash.Hex(), err)
+	}
+	return &RevertError{
+		Kind:    RevertUnknown,
+		Message: "replay succeeded, the failure depends on earlier transactions of the same block",
+		TxHash:  txHash,
+	}, nil
+}
+
+// uint256Type is the ABI type of Panic(uint256) codes.
+var uint256Type, _ = abi.NewType("uint256", "", nil)
@@ -0,0 +1,136 @@
+package contracts_test
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+	"github.com/thep2p/go-eth-localnet/internal/contracts"
+	"github.com/thep2p/go-eth-localnet/internal/unittest"
+)
+
+// vaultABI declares a custom error with named arguments.
+const vaultABI = `[{"inputs":[{"internalType":"address","name":"caller","type":"address"},{"internalType":"uint256","name":"needed","type":"uint256"}],"name":"Unauthorized","type":"error"}]`
+
+// rpcRevert mimics the error the RPC client returns for a revert with data.
+type rpcRevert struct {
+	data string
+}
+
+func (e *rpcRevert) Error() string          { return "execution reverted" }
+func (e *rpcRevert) ErrorCode() int         { return 3 }
+func (e *rpcRevert) ErrorData() interface{} { return e.data }
+
+// packRevert returns revert data of an error with the given signature and arguments.
+func packRevert(t *testing.T, signature string, args abi.Arguments, values ...interface{}) []byte {
+	t.Helper()
+
+	packed, err := args.Pack(values...)
+	require.NoError(t, err)
+	selector := abi.NewMethod("", signature[:strings.Index(signature, "(")], abi.Function, "", false, false, args, nil).ID
+	return append(selector, packed...)
+}
+
+// TestRevertDecoder_Decode tests decoding Error(string), Panic(uint256), custom errors and unknown data.
+func TestRevertDecoder_Decode(t *testing.T) {
+	decoder, err := contracts.NewRevertDecoder(&contracts.Artifact{Name: "Vault", ABI: vaultABI})
+	require.NoError(t, err)
+	stringType, err := abi.NewType("string", "", nil)
+	require.NoError(t, err)
+	uintType, err := abi.NewType("uint256", "", nil)
+	require.NoError(t, err)
+	addressType, err := abi.NewType("address", "", nil)
+	require.NoError(t, err)
+
+	revert := decoder.Decode(packRevert(t, "Error(string)", abi.Arguments{{Type: stringType}}, "value too low"))
+	require.Equal(t, contracts.RevertReason, revert.Kind)
+	require.Equal(t, "value too low", revert.Reason)
+	require.EqualError(t, revert, "execution reverted: value too low")
+
+	revert = decoder.Decode(packRevert(t, "Panic(uint256)", abi.Arguments{{Type: uintType}}, big.NewInt(0x12)))
+	require.Equal(t, contracts.RevertPanic, revert.Kind)
+	require.Equal(t, int64(0x12), revert.PanicCode.Int64())
+	require.EqualError(t, revert, "execution reverted: panic 0x12 (division or modulo by zero)")
+
+	caller := unittest.RandomAddresses(t, 1)[0]
+	data := packRevert(
+		t, "Unauthorized(address,uint256)",
+		abi.Arguments{{Name: "caller", Type: addressType}, {Name: "needed", Type: uintType}},
+		caller, big.NewInt(3),
+	)
+	revert = decoder.Decode(data)
+	require.Equal(t, contracts.RevertCustom, revert.Kind)
+	require.Equal(t, "Unauthorized", revert.Name)
+	require.Equal(t, caller, revert.Args["caller"])
+	require.Equal(t, int64(3), revert.Args["needed"].(*big.Int).Int64())
+	require.EqualError(t, revert, fmt.Sprintf("execution reverted: Unauthorized(caller=%s, needed=3)", caller.Hex()))
+
+	var unauthorized struct {
+		Caller common.Address
+		Needed *big.Int
+	}
+	require.NoError(t, revert.Unpack(&unauthorized))
+	require.Equal(t, caller, unauthorized.Caller)
+	require.Equal(t, int64(3), unauthorized.Needed.Int64())
+
+	// Without the ABI of the contract, custom errors are unknown.
+	withoutABI, err := contracts.NewRevertDecoder()
+	require.NoError(t, err)
+	revert = withoutABI.Decode(data)
+	require.Equal(t, contracts.RevertUnknown, revert.Kind)
+	require.ErrorContains(t, revert, "unknown error "+hexutil.Encode(data))
+	require.ErrorContains(t, revert.Unpack(&unauthorized), "not a custom error")
+
+	revert = decoder.Decode(nil)
+	require.Equal(t, contracts.RevertUnknown, revert.Kind)
+	require.EqualError(t, revert, "execution reverted")
+
+	// Data that claims to be an error but does not unpack as it is unknown.
+	revert = decoder.Decode(data[:20])
+	require.Equal(t, contracts.RevertUnknown, revert.Kind)
+}
+
+// TestRevertDecoder_FromError tests that revert data is extracted from RPC errors and other errors are rejected.
+func TestRevertDecoder_FromError(t *testing.T) {
+	decoder, err := contracts.NewRevertDecoder()
+	require.NoError(t, err)
+	uintType, err := abi.NewType("uint256", "", nil)
+	require.NoError(t, err)
+	data := packRevert(t, "Panic(uint256)", abi.Arguments{{Type: uintType}}, big.NewInt(0x11))
+
+	revert, ok := decoder.FromError(fmt.Errorf("call f: %w", &rpcRevert{data: hexutil.Encode(data)}))
+	require.True(t, ok)
+	require.Equal(t, contracts.RevertPanic, revert.Kind)
+	require.Equal(t, data, revert.Data)
+	require.Equal(t, "execution reverted", revert.Message)
+
+	wrapped := fmt.Errorf("call f: %w", revert)
+	var target *contracts.RevertError
+	require.True(t, errors.As(wrapped, &target))
+	require.Equal(t, int64(0x11), target.PanicCode.Int64())
+
+	_, ok = decoder.FromError(errors.New("connection refused"))
+	require.False(t, ok)
+	// A revert whose data is missing or malformed is still a revert.
+	revert, ok = decoder.FromError(&rpcRevert{data: "not hex"})
+	require.True(t, ok)
+	require.Equal(t, contracts.RevertUnknown, revert.Kind)
+	require.Empty(t, revert.Data)
+	_, ok = decoder.FromError(nil)
+	require.False(t, ok)
+}
+
+// TestPanicDescription tests that known panic codes are described and others are reported unknown.
+func TestPanicDescription(t *testing.T) {
+	require.Equal(t, "arithmetic underflow or overflow", contracts.PanicDescription(big.NewInt(0x11)))
+	require.Equal(t, "array index out of bounds", contracts.PanicDescription(big.NewInt(0x32)))
+	require.Equal(t, "unknown panic code", contracts.PanicDescription(big.NewInt(0x99)))
+	require.Equal(t, "unknown panic code", contracts.PanicDescription(nil))
+	require.Equal(t, "unknown", contracts.RevertKind(42).String())
+}
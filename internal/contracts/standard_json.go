@@ -0,0 +1,241 @@
+package contracts
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// outputSelection lists the standard-JSON outputs requested for every contract.
+var outputSelection = []string{
+	"abi",
+	"devdoc",
+	"storageLayout",
+	"evm.bytecode.object",
+	"evm.bytecode.sourceMap",
+	"evm.deployedBytecode.object",
+	"evm.deployedBytecode.sourceMap",
+	"evm.deployedBytecode.immutableReferences",
+}
+
+// Settings are the compiler settings of a compilation. The zero value uses
+// solc's defaults: optimizer disabled, the compiler's default EVM version and
+// the legacy code generator.
+type Settings struct {
+	// Optimizer configures the bytecode optimizer.
+	Optimizer Optimizer
+	// EVMVersion is the EVM version to target, e.g. "cancun" or "prague".
+	// Empty means the compiler's default.
+	EVMVersion string
+	// ViaIR compiles through the Yul intermediate representation.
+	ViaIR bool
+}
+
+// Optimizer configures the solc bytecode optimizer.
+type Optimizer struct {
+	// Enabled turns the optimizer on.
+	Enabled bool
+	// Runs is the number of times each opcode is expected to be executed over
+	// the contract's lifetime; lower values favour deployment cost, higher
+	// values execution cost. Zero means solc's default of 200.
+	Runs int
+}
+
+// StorageLayout is the layout of a contract's state variables in storage.
+type StorageLayout struct {
+	// Storage are the state variables in declaration order.
+	Storage []StorageVariable `json:"storage"`
+	// Types describe the types referenced by Storage, keyed by type identifier.
+	Types map[string]StorageType `json:"types"`
+}
+
+// StorageVariable is a state variable, or a struct member, in a StorageLayout.
+type StorageVariable struct {
+	// AstID is the id of the variable's declaration in the AST.
+	AstID int `json:"astId"`
+	// Contract is the fully qualified name of the declaring contract, "file:ContractName".
+	Contract string `json:"contract"`
+	// Label is the name of the variable.
+	Label string `json:"label"`
+	// Offset is the byte offset of the variable within its slot.
+	Offset int `json:"offset"`
+	// Slot is the storage slot of the variable, as a decimal string.
+	Slot string `json:"slot"`
+	// Type is the type identifier, a key of StorageLayout.Types.
+	Type string `json:"type"`
+}
+
+// StorageType describes a type referenced by a StorageLayout.
+type StorageType struct {
+	// Encoding is how the value is stored: inplace, mapping, dynamic_array or bytes.
+	Encoding string `json:"encoding"`
+	// Label is the canonical type name, e.g. uint256.
+	Label string `json:"label"`
+	// NumberOfBytes is the number of bytes used, as a decimal string.
+	NumberOfBytes string `json:"numberOfBytes"`
+	// Base is the element type identifier of arrays.
+	Base string `json:"base,omitempty"`
+	// Key is the key type identifier of mappings.
+	Key string `json:"key,omitempty"`
+	// Value is the value type identifier of mappings.
+	Value string `json:"value,omitempty"`
+	// Members are the members of structs.
+	Members []StorageVariable `json:"members,omitempty"`
+}
+
+// CodeRange is a byte range of a contract's bytecode.
+type CodeRange struct {
+	// Start is the offset of the first byte.
+	Start int `json:"start"`
+	// Length is the number of bytes.
+	Length int `json:"length"`
+}
+
+// DevDoc is the developer documentation of a contract, from its NatSpec comments.
+type DevDoc struct {
+	// Title is the @title of the contract.
+	Title string `json:"title,omitempty"`
+	// Author is the @author of the contract.
+	Author string `json:"author,omitempty"`
+	// Details are the @dev comments of the contract.
+	Details string `json:"details,omitempty"`
+	// Methods document functions keyed by signature, e.g. "set(uint256)".
+	Methods map[string]MethodDoc `json:"methods,omitempty"`
+	// Events document events keyed by signature.
+	Events map[string]MethodDoc `json:"events,omitempty"`
+	// Errors document custom errors keyed by signature. An error signature
+	// declared several times has one entry per declaration.
+	Errors map[string][]MethodDoc `json:"errors,omitempty"`
+	// StateVariables document public state variables keyed by name.
+	StateVariables map[string]MethodDoc `json:"stateVariables,omitempty"`
+}
+
+// MethodDoc is the developer documentation of a function, event, error or state variable.
+type MethodDoc struct {
+	// Details are the @dev comments.
+	Details string `json:"details,omitempty"`
+	// Params document parameters keyed by name.
+	Params map[string]string `json:"params,omitempty"`
+	// Returns document return values keyed by name, or by "_0", "_1", ... when unnamed.
+	Returns map[string]string `json:"returns,omitempty"`
+}
+
+// standardInput is the solc standard-JSON input.
+type standardInput struct {
+	Language string                    `json:"language"`
+	Sources  map[string]standardSource `json:"sources"`
+	Settings standardSettings          `json:"settings"`
+}
+
+// standardSource is a source unit of the standard-JSON input.
+type standardSource struct {
+	Content string `json:"content"`
+}
+
+// standardSettings are the settings of the standard-JSON input.
+type standardSettings struct {
+	Remappings []string `json:"remappings,omitempty"`
+	Optimizer  struct {
+		Enabled bool `json:"enabled"`
+		Runs    int  `json:"runs,omitempty"`
+	} `json:"optimizer"`
+	EVMVersion string `json:"evmVersion,omitempty"`
+	ViaIR      bool   `json:"viaIR,omitempty"`
+	Metadata   struct {
+		BytecodeHash string `json:"bytecodeHash"`
+	} `json:"metadata"`
+	OutputSelection map[string]map[string][]string `json:"outputSelection"`
+}
+
+// newStandardSettings returns the standard-JSON settings for the given options.
+// The metadata hash is omitted so the bytecode only depends on the sources and
+// settings, as with --metadata-hash none.
+func newStandardSettings(remappings []string, settings Settings) standardSettings {
+	s := standardSettings{
+		Remappings: remappings,
+		EVMVersion: settings.EVMVersion,
+		ViaIR:      settings.ViaIR,
+		OutputSelection: map[string]map[string][]string{
+			"*": {"*": outputSelection},
+		},
+	}
+	s.Optimizer.Enabled = settings.Optimizer.Enabled
+	s.Optimizer.Runs = settings.Optimizer.Runs
+	s.Metadata.BytecodeHash = "none"
+	return s
+}
+
+// standardOutput is the solc standard-JSON output.
+type standardOutput struct {
+	Errors    []standardError                        `json:"errors"`
+	Contracts map[string]map[string]standardContract `json:"contracts"`
+}
+
+// standardError is a diagnostic of the standard-JSON output.
+type standardError struct {
+	Severity         string `json:"severity"`
+	Message          string `json:"message"`
+	FormattedMessage string `json:"formattedMessage"`
+}
+
+// standardContract is a compiled contract of the standard-JSON output.
+type standardContract struct {
+	ABI           json.RawMessage `json:"abi"`
+	DevDoc        *DevDoc         `json:"devdoc"`
+	StorageLayout *StorageLayout  `json:"storageLayout"`
+	EVM           struct {
+		Bytecode         standardBytecode `json:"bytecode"`
+		DeployedBytecode standardBytecode `json:"deployedBytecode"`
+	} `json:"evm"`
+}
+
+// standardBytecode is the bytecode of a contract in the standard-JSON output.
+type standardBytecode struct {
+	Object              string                 `json:"object"`
+	SourceMap           string                 `json:"sourceMap"`
+	ImmutableReferences map[string][]CodeRange `json:"immutableReferences"`
+}
+
+// parseStandardOutput returns the artifacts of a standard-JSON output, or an
+// error listing the compilation errors if there are any.
+func parseStandardOutput(output []byte) (Artifacts, error) {
+	var out standardOutput
+	if err := json.Unmarshal(output, &out); err != nil {
+		return nil, fmt.Errorf("unmarshal solc output: %w", err)
+	}
+
+	var messages []string
+	for _, e := range out.Errors {
+		if e.Severity != "error" {
+			continue
+		}
+		if e.FormattedMessage != "" {
+			messages = append(messages, strings.TrimSpace(e.FormattedMessage))
+		} else {
+			messages = append(messages, e.Message)
+		}
+	}
+	if len(messages) > 0 {
+		return nil, fmt.Errorf("solc failed:\n%s", strings.Join(messages, "\n"))
+	}
+
+	artifacts := make(Artifacts)
+	for sourcePath, contracts := range out.Contracts {
+		for name, c := range contracts {
+			artifact := &Artifact{
+				Name:                name,
+				SourcePath:          sourcePath,
+				ABI:                 string(c.ABI),
+				Bin:                 c.EVM.Bytecode.Object,
+				BinRuntime:          c.EVM.DeployedBytecode.Object,
+				SrcMap:              c.EVM.Bytecode.SourceMap,
+				SrcMapRuntime:       c.EVM.DeployedBytecode.SourceMap,
+				StorageLayout:       c.StorageLayout,
+				DevDoc:              c.DevDoc,
+				ImmutableReferences: c.EVM.DeployedBytecode.ImmutableReferences,
+			}
+			artifacts[artifact.Key()] = artifact
+		}
+	}
+	return artifacts, nil
+}
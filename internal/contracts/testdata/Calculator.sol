@@ -0,0 +1,25 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+import "@arith/Adder.sol";
+import {Unit} from "shapes/Unit.sol";
+
+// This contract imports a library through a remapping and a contract through an include path.
+contract Calculator {
+    function add(uint256 a, uint256 b) public pure returns (uint256) {
+        return Adder.add(a, b);
+    }
+
+    function unit() public returns (address) {
+        return address(new Unit());
+    }
+}
+
+// This contract shares its name with a contract of an imported file.
+contract Counter {
+    uint256 public count;
+
+    function increment() public {
+        count += 1;
+    }
+}
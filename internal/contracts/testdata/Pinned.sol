@@ -0,0 +1,5 @@
+// SPDX-License-Identifier: MIT
+pragma solidity >=0.8.10 <0.8.30;
+
+// This contract pins a compiler range to test compiler selection.
+contract Pinned {}
@@ -0,0 +1,14 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+/// @title A registry stamped with its deployer
+/// @dev This contract is used to test that immutables are masked when verifying deployed code.
+contract Registry {
+    address public immutable owner;
+    uint256 public immutable createdAt;
+
+    constructor() {
+        owner = msg.sender;
+        createdAt = block.number;
+    }
+}
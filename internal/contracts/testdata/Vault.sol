@@ -0,0 +1,24 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+/// @title A vault of balances
+/// @author go-eth-localnet
+/// @dev This contract is used to test storage layout and developer documentation outputs.
+contract Vault {
+    address public owner;
+    bool public locked;
+    mapping(address => uint256) public balances;
+
+    constructor() {
+        owner = msg.sender;
+    }
+
+    /// @dev Credits amount to the balance of account.
+    /// @param account The account to credit.
+    /// @param amount The amount to credit.
+    /// @return balance The new balance of account.
+    function credit(address account, uint256 amount) public returns (uint256 balance) {
+        balances[account] += amount;
+        return balances[account];
+    }
+}
@@ -0,0 +1,13 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+// This file is resolved through an include path and defines two contracts.
+contract Unit {
+    function one() public pure returns (uint256) {
+        return 1;
+    }
+}
+
+contract Counter {
+    uint256 public count;
+}
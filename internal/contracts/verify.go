@@ -0,0 +1,174 @@
+package contracts
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// libraryPlaceholder matches the placeholder of an unlinked library address
+// in hex-encoded bytecode, e.g. __$<34 hex chars>$__.
+var libraryPlaceholder = regexp.MustCompile(`__\$[0-9a-fA-F]{34}\$__`)
+
+// Verification is the result of comparing the code deployed at an address
+// with the runtime bytecode of a compiled contract (see Verify).
+type Verification struct {
+	// Address is the verified contract address.
+	Address common.Address
+	// Match reports whether the deployed code is the compiled runtime
+	// bytecode once immutables, library addresses and metadata are ignored.
+	Match bool
+	// MetadataMatch reports whether the metadata appended by solc, which
+	// encodes the compiler version and optionally a hash of the sources and
+	// settings, is identical too.
+	MetadataMatch bool
+	// Mismatches are the ranges of the code, metadata excluded, where the
+	// deployed and compiled bytes differ. A length difference is reported as
+	// a range covering the extra bytes.
+	Mismatches []CodeRange
+	// DeployedSize is the size of the deployed code in bytes.
+	DeployedSize int
+	// CompiledSize is the size of the compiled runtime bytecode in bytes.
+	CompiledSize int
+}
+
+// Verify fetches the code deployed at address with eth_getCode and compares
+// it with the runtime bytecode of artifact, to catch deployments of the wrong
+// build. Immutable variables and linked library addresses, which are only
+// known on deployment, are masked on both sides, and the metadata solc
+// appends is compared separately.
+//
+// Args:
+//   - ctx: bounds the query
+//   - client: RPC client of the node
+//   - address: the deployed contract
+//   - artifact: the compiled contract the deployment is expected to match
+//
+// Returns the verification, whose Match is false if the code differs. All
+// errors are CRITICAL and indicate the artifact has no runtime bytecode,
+// nothing is deployed at address, or the node could not be reached.
+func Verify(ctx context.Context, client *rpc.Client, address common.Address, artifact *Artifact) (*Verification, error) {
+	compiled, masks, err := runtimeBytecode(artifact)
+	if err != nil {
+		return nil, err
+	}
+	deployed, err := getCode(ctx, client, address)
+	if err != nil {
+		return nil, err
+	}
+	if len(deployed) == 0 {
+		return nil, fmt.Errorf("no code deployed at %s", address.Hex())
+	}
+	return compareBytecode(address, deployed, compiled, masks), nil
+}
+
+// runtimeBytecode decodes the runtime bytecode of artifact, with unlinked
+// library placeholders zeroed, and returns the ranges to mask when comparing:
+// immutables, library addresses and, for a library, its own address.
+func runtimeBytecode(artifact *Artifact) ([]byte, []CodeRange, error) {
+	bin := strings.TrimPrefix(artifact.BinRuntime, "0x")
+	if bin == "" {
+		return nil, nil, fmt.Errorf("%s has no runtime bytecode, it may be abstract or an interface", artifact.Name)
+	}
+
+	var masks []CodeRange
+	for _, loc := range libraryPlaceholder.FindAllStringIndex(bin, -1) {
+		masks = append(masks, CodeRange{Start: loc[0] / 2, Length: (loc[1] - loc[0]) / 2})
+	}
+	bin = libraryPlaceholder.ReplaceAllStringFunc(bin, func(placeholder string) string {
+		return strings.Repeat("0", len(placeholder))
+	})
+	for _, refs := range artifact.ImmutableReferences {
+		masks = append(masks, refs...)
+	}
+
+	compiled, err := hexutil.Decode("0x" + bin)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode runtime bytecode of %s: %w", artifact.Name, err)
+	}
+	// A library's runtime bytecode starts with PUSH20 of its own address, its
+	// call protection, which solc leaves zero and the deployment fills in.
+	// cf. https://docs.soliditylang.org/en/latest/contracts.html#call-protection-for-libraries
+	if len(compiled) > common.AddressLength && compiled[0] == byte(vm.PUSH20) {
+		masks = append(masks, CodeRange{Start: 1, Length: common.AddressLength})
+	}
+	return compiled, masks, nil
+}
+
+// compareBytecode compares deployed code with compiled runtime bytecode,
+// ignoring the masked ranges and comparing the metadata separately.
+func compareBytecode(address common.Address, deployed, compiled []byte, masks []CodeRange) *Verification {
+	deployedCode, deployedMetadata := splitMetadata(deployed)
+	compiledCode, compiledMetadata := splitMetadata(compiled)
+
+	// Copies are masked, so the caller's slices are left unchanged.
+	deployedCode = maskRanges(deployedCode, masks)
+	compiledCode = maskRanges(compiledCode, masks)
+
+	v := &Verification{
+		Address:       address,
+		MetadataMatch: bytes.Equal(deployedMetadata, compiledMetadata),
+		Mismatches:    diffRanges(deployedCode, compiledCode),
+		DeployedSize:  len(deployed),
+		CompiledSize:  len(compiled),
+	}
+	v.Match = len(v.Mismatches) == 0
+	return v
+}
+
+// splitMetadata splits bytecode into the code and the CBOR-encoded metadata
+// solc appends, which ends with its length as two big-endian bytes.
+// Bytecode without recognizable metadata is returned whole.
+// cf. https://docs.soliditylang.org/en/latest/metadata.html#encoding-of-the-metadata-hash-in-the-bytecode
+func splitMetadata(code []byte) ([]byte, []byte) {
+	if len(code) < 2 {
+		return code, nil
+	}
+	length := int(code[len(code)-2])<<8 | int(code[len(code)-1])
+	start := len(code) - 2 - length
+	// The metadata is a CBOR map, whose major type 5 sets the top bits to 101.
+	if length == 0 || start < 0 || code[start]&0xe0 != 0xa0 {
+		return code, nil
+	}
+	return code[:start], code[start:]
+}
+
+// maskRanges returns a copy of code with the bytes of ranges zeroed. Ranges
+// are clipped to the code.
+func maskRanges(code []byte, ranges []CodeRange) []byte {
+	masked := bytes.Clone(code)
+	for _, r := range ranges {
+		for i := r.Start; i < r.Start+r.Length && i < len(masked); i++ {
+			masked[i] = 0
+		}
+	}
+	return masked
+}
+
+// diffRanges returns the ranges where a and b differ, merging adjacent
+// differing bytes, followed by the bytes only the longer of them has.
+func diffRanges(a, b []byte) []CodeRange {
+	var ranges []CodeRange
+	shorter := min(len(a), len(b))
+	for i := 0; i < shorter; i++ {
+		if a[i] == b[i] {
+			continue
+		}
+		if n := len(ranges); n > 0 && ranges[n-1].Start+ranges[n-1].Length == i {
+			ranges[n-1].Length++
+		} else {
+			ranges = append(ranges, CodeRange{Start: i, Length: 1})
+		}
+	}
+	if longer := max(len(a), len(b)); longer > shorter {
+		ranges = append(ranges, CodeRange{Start: shorter, Length: longer - shorter})
+	}
+	return ranges
+}
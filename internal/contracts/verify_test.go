@@ -0,0 +1,146 @@
+package contracts_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+	"github.com/thep2p/go-eth-localnet/internal/contracts"
+	"github.com/thep2p/go-eth-localnet/internal/model"
+	"github.com/thep2p/go-eth-localnet/internal/unittest"
+)
+
+// simpleTestRuntime returns SimpleTestContract with its runtime bytecode, which
+// its constructor copies from offset 0x1a of the creation bytecode.
+func simpleTestRuntime() *contracts.Artifact {
+	artifact := *simpleTestArtifacts()["SimpleTestContract.sol:SimpleTestContract"]
+	artifact.BinRuntime = artifact.Bin[2*0x1a:]
+	return &artifact
+}
+
+// TestVerify tests that deployed code matches its artifact, and that code and
+// metadata differences are reported unless the code differs in masked ranges.
+func TestVerify(t *testing.T) {
+	key := unittest.PrivateKeyFixture(t)
+	ctx, _, client := startNode(t, key)
+	artifact := simpleTestRuntime()
+	c, err := contracts.Deploy(ctx, client, key, artifact)
+	require.NoError(t, err)
+
+	v, err := contracts.Verify(ctx, client, c.Address(), artifact)
+	require.NoError(t, err)
+	require.True(t, v.Match)
+	require.True(t, v.MetadataMatch)
+	require.Empty(t, v.Mismatches)
+	require.Equal(t, len(artifact.BinRuntime)/2, v.DeployedSize)
+	require.Equal(t, v.DeployedSize, v.CompiledSize)
+
+	// A different compiler version only changes the metadata, solc 0.8.30 -> 0.8.29.
+	otherCompiler := *artifact
+	otherCompiler.BinRuntime = artifact.BinRuntime[:len(artifact.BinRuntime)-6] + "1d000a"
+	v, err = contracts.Verify(ctx, client, c.Address(), &otherCompiler)
+	require.NoError(t, err)
+	require.True(t, v.Match)
+	require.False(t, v.MetadataMatch)
+
+	// A different constant returned by f, 0x2a -> 0x2b, the operand of PUSH1 0x2a is byte 0x47.
+	offset := 2 * 0x47
+	require.Equal(t, "2a", artifact.BinRuntime[offset:offset+2])
+	otherCode := *artifact
+	otherCode.BinRuntime = artifact.BinRuntime[:offset] + "2b" + artifact.BinRuntime[offset+2:]
+	v, err = contracts.Verify(ctx, client, c.Address(), &otherCode)
+	require.NoError(t, err)
+	require.False(t, v.Match)
+	require.True(t, v.MetadataMatch)
+	require.Equal(t, []contracts.CodeRange{{Start: 0x47, Length: 1}}, v.Mismatches)
+
+	// The same byte held by an immutable is masked.
+	otherCode.ImmutableReferences = map[string][]contracts.CodeRange{"3": {{Start: 0x47, Length: 1}}}
+	v, err = contracts.Verify(ctx, client, c.Address(), &otherCode)
+	require.NoError(t, err)
+	require.True(t, v.Match)
+
+	// Extra code is reported as a range past the deployed code, whose 12 trailing bytes are metadata.
+	longer := *artifact
+	longer.BinRuntime = "00" + artifact.BinRuntime
+	v, err = contracts.Verify(ctx, client, c.Address(), &longer)
+	require.NoError(t, err)
+	require.False(t, v.Match)
+	require.Equal(t, contracts.CodeRange{Start: v.DeployedSize - 12, Length: 1}, v.Mismatches[len(v.Mismatches)-1])
+}
+
+// TestVerify_Errors tests that an artifact without runtime bytecode or an address without code are rejected.
+func TestVerify_Errors(t *testing.T) {
+	key := unittest.PrivateKeyFixture(t)
+	ctx, _, client := startNode(t, key)
+	address := unittest.RandomAddresses(t, 1)[0]
+
+	_, err := contracts.Verify(ctx, client, address, simpleTestRuntime())
+	require.ErrorContains(t, err, "no code deployed at "+address.Hex())
+	_, err = contracts.Verify(ctx, client, address, &contracts.Artifact{Name: "I"})
+	require.ErrorContains(t, err, "I has no runtime bytecode")
+}
+
+// TestVerify_Immutables tests that a contract with immutables, set on
+// deployment, matches its compiled runtime bytecode and not another contract's.
+func TestVerify_Immutables(t *testing.T) {
+	unittest.RequireSolc(t)
+
+	artifacts, err := contracts.Compile(contracts.CompileOptions{}, "testdata/Registry.sol")
+	require.NoError(t, err)
+	registry, err := artifacts.Contract("Registry")
+	require.NoError(t, err)
+	require.Len(t, registry.ImmutableReferences, 2)
+
+	key := unittest.PrivateKeyFixture(t)
+	ctx, _, client := startNode(t, key)
+	c, err := contracts.Deploy(ctx, client, key, registry)
+	require.NoError(t, err)
+
+	v, err := contracts.Verify(ctx, client, c.Address(), registry)
+	require.NoError(t, err)
+	require.True(t, v.Match)
+	require.True(t, v.MetadataMatch)
+
+	v, err = contracts.Verify(ctx, client, c.Address(), storageArtifact(t))
+	require.NoError(t, err)
+	require.False(t, v.Match)
+	require.NotEmpty(t, v.Mismatches)
+}
+
+// TestVerify_Library tests that the call protection of a library, the PUSH20
+// of its own address its deployment fills in, is masked.
+func TestVerify_Library(t *testing.T) {
+	// The runtime bytecode is PUSH20 <address> STOP; the constructor stores
+	// ADDRESS after the PUSH20 opcode and returns the 22 bytes.
+	compiledRuntime := "73" + strings.Repeat("00", 20) + "00"
+	library := &contracts.Artifact{
+		Name:       "Library",
+		ABI:        "[]",
+		Bin:        "3060601b600152607360005360166000f3",
+		BinRuntime: compiledRuntime,
+	}
+
+	key := unittest.PrivateKeyFixture(t)
+	ctx, _, client := startNode(t, key)
+	c, err := contracts.Deploy(ctx, client, key, library)
+	require.NoError(t, err)
+
+	var deployed hexutil.Bytes
+	require.NoError(t, client.CallContext(ctx, &deployed, model.ReceiptGetByteCode, c.Address(), model.EthBlockLatest))
+	require.Equal(t, c.Address().Bytes(), []byte(deployed[1:21]))
+
+	v, err := contracts.Verify(ctx, client, c.Address(), library)
+	require.NoError(t, err)
+	require.True(t, v.Match)
+	require.Equal(t, 22, v.DeployedSize)
+
+	// Bytes past the address are still compared.
+	other := *library
+	other.BinRuntime = compiledRuntime[:len(compiledRuntime)-2] + "fe"
+	v, err = contracts.Verify(ctx, client, c.Address(), &other)
+	require.NoError(t, err)
+	require.False(t, v.Match)
+	require.Equal(t, []contracts.CodeRange{{Start: 21, Length: 1}}, v.Mismatches)
+}
@@ -0,0 +1,380 @@
+package contracts
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CompilerCacheDirEnv is the environment variable naming the default directory
+// of solc binaries, used when CompileOptions.CompilerCacheDir is empty.
+const CompilerCacheDirEnv = "SOLC_CACHE_DIR"
+
+var (
+	// pragmaPattern matches a Solidity version pragma and captures its constraint.
+	pragmaPattern = regexp.MustCompile(`(?m)^\s*pragma\s+solidity\s+([^;]+);`)
+	// versionPattern matches a solc version, e.g. in "Version: 0.8.30+commit.73712a01.Linux.g++".
+	versionPattern = regexp.MustCompile(`(\d+)\.(\d+)\.(\d+)`)
+	// binaryPattern matches the file name of a cached solc binary, e.g. solc-0.8.30 or solc-v0.8.30+commit.73712a01.
+	binaryPattern = regexp.MustCompile(`^solc-v?(\d+\.\d+\.\d+)([+.-].*)?$`)
+	// comparatorPattern matches a single comparator of a version constraint, e.g. >=0.8.0 or ^0.8.
+	comparatorPattern = regexp.MustCompile(`^(\^|~|>=|<=|>|<|=)?\s*v?(\d+)(?:\.(\d+|x|\*))?(?:\.(\d+|x|\*))?$`)
+)
+
+// Version is a solc release version.
+type Version struct {
+	Major, Minor, Patch int
+}
+
+// ParseVersion parses a version of the form major.minor.patch, ignoring a
+// leading "v" and any build suffix such as "+commit.73712a01".
+func ParseVersion(s string) (Version, error) {
+	m := versionPattern.FindStringSubmatch(s)
+	if m == nil {
+		return Version{}, fmt.Errorf("invalid solc version %q", s)
+	}
+	major, _ := strconv.Atoi(m[1])
+	minor, _ := strconv.Atoi(m[2])
+	patch, _ := strconv.Atoi(m[3])
+	return Version{Major: major, Minor: minor, Patch: patch}, nil
+}
+
+// String returns the version as major.minor.patch.
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Compare returns -1, 0 or +1 if v is lower than, equal to or greater than o.
+func (v Version) Compare(o Version) int {
+	for _, d := range [3]int{v.Major - o.Major, v.Minor - o.Minor, v.Patch - o.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+// comparator is a single bound of a version constraint.
+type comparator struct {
+	op      string
+	version Version
+}
+
+// allows reports whether v satisfies the bound.
+func (c comparator) allows(v Version) bool {
+	cmp := v.Compare(c.version)
+	switch c.op {
+	case ">=":
+		return cmp >= 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case "<":
+		return cmp < 0
+	default:
+		return cmp == 0
+	}
+}
+
+// Constraint is a Solidity version constraint, as in a version pragma, e.g.
+// "^0.8.0" or ">=0.8.10 <0.9.0 || 0.7.6".
+type Constraint struct {
+	expr string
+	// anyOf are alternatives separated by "||"; each is a set of bounds that must all hold.
+	anyOf [][]comparator
+}
+
+// ParseConstraint parses a version constraint with the npm-style syntax
+// Solidity pragmas use: comparators (^, ~, >=, <=, >, <, =) joined by spaces,
+// alternatives joined by "||", hyphen ranges "a - b", and partial versions such
+// as 0.8 or 0.8.x.
+func ParseConstraint(expr string) (*Constraint, error) {
+	c := &Constraint{expr: strings.TrimSpace(expr)}
+	for _, alternative := range strings.Split(expr, "||") {
+		fields := strings.Fields(alternative)
+		// Rejoin operators separated from their version, e.g. ">= 0.8.0".
+		for i := 0; i < len(fields)-1; i++ {
+			if strings.Trim(fields[i], "^~<>=") == "" && fields[i] != "-" {
+				fields[i] += fields[i+1]
+				fields = append(fields[:i+1], fields[i+2:]...)
+			}
+		}
+
+		var bounds []comparator
+		for i := 0; i < len(fields); i++ {
+			if i+2 < len(fields) && fields[i+1] == "-" {
+				lower, err := parseComparator(">=" + fields[i])
+				if err != nil {
+					return nil, fmt.Errorf("invalid version constraint %q: %w", expr, err)
+				}
+				upper, err := parseComparator("<=" + fields[i+2])
+				if err != nil {
+					return nil, fmt.Errorf("invalid version constraint %q: %w", expr, err)
+				}
+				bounds = append(bounds, lower...)
+				bounds = append(bounds, upper...)
+				i += 2
+				continue
+			}
+			parsed, err := parseComparator(fields[i])
+			if err != nil {
+				return nil, fmt.Errorf("invalid version constraint %q: %w", expr, err)
+			}
+			bounds = append(bounds, parsed...)
+		}
+		if len(bounds) == 0 {
+			return nil, fmt.Errorf("invalid version constraint %q: empty alternative", expr)
+		}
+		c.anyOf = append(c.anyOf, bounds)
+	}
+	return c, nil
+}
+
+// parseComparator parses a single comparator into the bounds it stands for.
+// Partial versions and the ^ and ~ operators expand to a lower and an upper bound.
+func parseComparator(s string) ([]comparator, error) {
+	m := comparatorPattern.FindStringSubmatch(s)
+	if m == nil {
+		return nil, fmt.Errorf("invalid comparator %q", s)
+	}
+	op := m[1]
+	major, _ := strconv.Atoi(m[2])
+	wildcard := func(part string) bool {
+		return part == "" || part == "x" || part == "*"
+	}
+	// precision is the number of version parts given, wildcards excluded.
+	precision := 1
+	minor, patch := 0, 0
+	if !wildcard(m[3]) {
+		minor, _ = strconv.Atoi(m[3])
+		precision = 2
+		if !wildcard(m[4]) {
+			patch, _ = strconv.Atoi(m[4])
+			precision = 3
+		}
+	}
+	v := Version{Major: major, Minor: minor, Patch: patch}
+
+	// next returns the first version past the range a partial version covers.
+	next := func(parts int) Version {
+		switch parts {
+		case 1:
+			return Version{Major: v.Major + 1}
+		case 2:
+			return Version{Major: v.Major, Minor: v.Minor + 1}
+		default:
+			return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+		}
+	}
+
+	switch op {
+	case "^":
+		// ^ allows changes that do not modify the left-most non-zero part.
+		parts := 1
+		if v.Major == 0 {
+			parts = 2
+			if v.Minor == 0 && precision == 3 {
+				parts = 3
+			}
+		}
+		return []comparator{{">=", v}, {"<", next(min(parts, precision))}}, nil
+	case "~":
+		return []comparator{{">=", v}, {"<", next(min(2, precision))}}, nil
+	case ">", "<=":
+		if precision < 3 {
+			// >0.8 means >=0.9.0, and <=0.8 means <0.9.0.
+			if op == ">" {
+				return []comparator{{">=", next(precision)}}, nil
+			}
+			return []comparator{{"<", next(precision)}}, nil
+		}
+		return []comparator{{op, v}}, nil
+	case ">=", "<":
+		return []comparator{{op, v}}, nil
+	default:
+		if precision < 3 {
+			return []comparator{{">=", v}, {"<", next(precision)}}, nil
+		}
+		return []comparator{{"=", v}}, nil
+	}
+}
+
+// Allows reports whether v satisfies the constraint.
+func (c *Constraint) Allows(v Version) bool {
+	for _, bounds := range c.anyOf {
+		allowed := true
+		for _, b := range bounds {
+			if !b.allows(v) {
+				allowed = false
+				break
+			}
+		}
+		if allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the constraint as written.
+func (c *Constraint) String() string {
+	return c.expr
+}
+
+// ParsePragmas returns the version constraints of the Solidity version
+// pragmas in source. A file without a pragma yields none.
+func ParsePragmas(source []byte) ([]*Constraint, error) {
+	var constraints []*Constraint
+	for _, m := range pragmaPattern.FindAllSubmatch(source, -1) {
+		c, err := ParseConstraint(string(m[1]))
+		if err != nil {
+			return nil, err
+		}
+		constraints = append(constraints, c)
+	}
+	return constraints, nil
+}
+
+// Compiler is a solc binary of a known version.
+type Compiler struct {
+	// Path is the path of the binary.
+	Path string
+	// Version is the release version of the binary.
+	Version Version
+}
+
+// LocalCompilers lists the solc binaries in a cache directory, highest version
+// first. Binaries are named solc-<version>, optionally with a "v" prefix or a
+// build suffix, e.g. solc-0.8.30 or solc-v0.8.30+commit.73712a01, and are
+// either placed in the directory itself or in a subdirectory of the same name,
+// as solc-select lays them out. Populate the directory ahead of time, e.g. from
+// https://binaries.soliditylang.org; nothing is downloaded.
+// Returns an error if the directory cannot be read.
+func LocalCompilers(dir string) ([]Compiler, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read solc cache dir: %w", err)
+	}
+
+	var compilers []Compiler
+	for _, entry := range entries {
+		m := binaryPattern.FindStringSubmatch(entry.Name())
+		if m == nil {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if entry.IsDir() {
+			path = filepath.Join(path, entry.Name())
+		}
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() || info.Mode()&0111 == 0 {
+			continue
+		}
+		version, err := ParseVersion(m[1])
+		if err != nil {
+			continue
+		}
+		compilers = append(compilers, Compiler{Path: path, Version: version})
+	}
+	sort.SliceStable(compilers, func(i, j int) bool {
+		return compilers[i].Version.Compare(compilers[j].Version) > 0
+	})
+	return compilers, nil
+}
+
+// SelectCompiler returns the highest solc version that satisfies the version
+// pragmas of every given file. Candidates are the binaries of cacheDir (see
+// LocalCompilers), or, if cacheDir is empty, the solc on PATH.
+//
+// Only the pragmas of the given files are checked; solc itself rejects
+// imported files whose pragma the selected version does not satisfy.
+//
+// Returns an error naming the pragmas and the available versions if no
+// candidate matches, or if a file or the cache directory cannot be read.
+func SelectCompiler(cacheDir string, solPaths ...string) (*Compiler, error) {
+	var constraints []*Constraint
+	for _, solPath := range solPaths {
+		source, err := os.ReadFile(solPath)
+		if err != nil {
+			return nil, fmt.Errorf("file not found: %w", err)
+		}
+		pragmas, err := ParsePragmas(source)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", solPath, err)
+		}
+		constraints = append(constraints, pragmas...)
+	}
+
+	var candidates []Compiler
+	source := cacheDir
+	if cacheDir != "" {
+		compilers, err := LocalCompilers(cacheDir)
+		if err != nil {
+			return nil, err
+		}
+		candidates = compilers
+	} else {
+		compiler, err := pathCompiler()
+		if err != nil {
+			return nil, err
+		}
+		candidates = []Compiler{*compiler}
+		source = "PATH"
+	}
+
+	available := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		if allowsAll(constraints, candidate.Version) {
+			return &candidate, nil
+		}
+		available = append(available, candidate.Version.String())
+	}
+
+	pragmas := make([]string, 0, len(constraints))
+	for _, c := range constraints {
+		pragmas = append(pragmas, c.String())
+	}
+	if len(available) == 0 {
+		return nil, fmt.Errorf("no solc binaries in %s, add one satisfying %s", source, strings.Join(pragmas, ", "))
+	}
+	return nil, fmt.Errorf(
+		"no solc in %s satisfies %s, available versions: %s",
+		source, strings.Join(pragmas, ", "), strings.Join(available, ", "),
+	)
+}
+
+// allowsAll reports whether v satisfies every constraint.
+func allowsAll(constraints []*Constraint, v Version) bool {
+	for _, c := range constraints {
+		if !c.Allows(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// pathCompiler returns the solc on PATH and its version.
+func pathCompiler() (*Compiler, error) {
+	path, err := exec.LookPath("solc")
+	if err != nil {
+		return nil, fmt.Errorf("solc not found in PATH: %w", err)
+	}
+	output, err := exec.Command(path, "--version").Output()
+	if err != nil {
+		return nil, fmt.Errorf("solc --version: %w", err)
+	}
+	version, err := ParseVersion(string(output))
+	if err != nil {
+		return nil, err
+	}
+	return &Compiler{Path: path, Version: version}, nil
+}
@@ -0,0 +1,161 @@
+package contracts_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/thep2p/go-eth-localnet/internal/contracts"
+	"github.com/thep2p/go-eth-localnet/internal/unittest"
+)
+
+// newCompilerCache creates a solc cache directory with a stub binary for each
+// version. Each stub answers any standard-JSON input with a single contract,
+// so tests can tell which binary was selected without a real compiler, and
+// appends a line to calls.log in the directory (see compilerCalls).
+func newCompilerCache(t *testing.T, names ...string) string {
+	t.Helper()
+
+	tmp := unittest.NewTempDir(t)
+	t.Cleanup(tmp.Remove)
+	for _, name := range names {
+		path := filepath.Join(tmp.Path(), name)
+		if filepath.Base(name) != name {
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		}
+		stub := fmt.Sprintf(
+			"#!/bin/sh\ncat >/dev/null\nsleep 0.2\necho x >> \"$(dirname \"$0\")/calls.log\"\necho '{\"contracts\":{\"stub.sol\":{\"Stub\":{\"abi\":[],\"evm\":{\"bytecode\":{\"object\":\"%s\"}}}}}}'\n",
+			filepath.Base(name),
+		)
+		require.NoError(t, os.WriteFile(path, []byte(stub), 0755))
+	}
+	return tmp.Path()
+}
+
+// TestParseConstraint tests the pragma constraint syntax against versions in and out of range.
+func TestParseConstraint(t *testing.T) {
+	cases := []struct {
+		expr    string
+		allowed []string
+		denied  []string
+	}{
+		{"^0.8.0", []string{"0.8.0", "0.8.30"}, []string{"0.7.6", "0.9.0"}},
+		{"^0.8.30", []string{"0.8.30", "0.8.31"}, []string{"0.8.29", "0.9.0"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4"}},
+		{"~0.8.1", []string{"0.8.1", "0.8.9"}, []string{"0.8.0", "0.9.0"}},
+		{"0.8.30", []string{"0.8.30"}, []string{"0.8.29", "0.8.31"}},
+		{"=0.8.30", []string{"0.8.30"}, []string{"0.8.31"}},
+		{"0.8", []string{"0.8.0", "0.8.99"}, []string{"0.9.0"}},
+		{"0.8.x", []string{"0.8.7"}, []string{"0.7.0"}},
+		{">=0.8.10 <0.9.0", []string{"0.8.10", "0.8.30"}, []string{"0.8.9", "0.9.0"}},
+		{">= 0.8.10 < 0.8.20", []string{"0.8.19"}, []string{"0.8.20"}},
+		{">0.8.10 <=0.8.12", []string{"0.8.11", "0.8.12"}, []string{"0.8.10", "0.8.13"}},
+		{">0.7", []string{"0.8.0"}, []string{"0.7.6"}},
+		{"<=0.7", []string{"0.7.6"}, []string{"0.8.0"}},
+		{"0.8.0 - 0.8.5", []string{"0.8.0", "0.8.5"}, []string{"0.8.6"}},
+		{"^0.7.6 || ^0.8.20", []string{"0.7.6", "0.8.20"}, []string{"0.8.19", "0.6.12"}},
+	}
+	for _, tc := range cases {
+		c, err := contracts.ParseConstraint(tc.expr)
+		require.NoError(t, err, tc.expr)
+		for _, v := range tc.allowed {
+			version, err := contracts.ParseVersion(v)
+			require.NoError(t, err)
+			require.True(t, c.Allows(version), "%s should allow %s", tc.expr, v)
+		}
+		for _, v := range tc.denied {
+			version, err := contracts.ParseVersion(v)
+			require.NoError(t, err)
+			require.False(t, c.Allows(version), "%s should not allow %s", tc.expr, v)
+		}
+	}
+
+	for _, expr := range []string{"", "latest", "^0.8.0 ||", ">=a.b.c"} {
+		_, err := contracts.ParseConstraint(expr)
+		require.Error(t, err, expr)
+	}
+}
+
+// TestParsePragmas tests that the version pragmas of the repository's contracts are found.
+func TestParsePragmas(t *testing.T) {
+	for file, expected := range map[string]string{
+		"SimpleStorageContract.sol": "^0.8.30",
+		"EmptyContract.sol":         "^0.8.10",
+		"SimpleTestContract.sol":    "^0.8.0",
+	} {
+		source, err := os.ReadFile(file)
+		require.NoError(t, err)
+		pragmas, err := contracts.ParsePragmas(source)
+		require.NoError(t, err)
+		require.Len(t, pragmas, 1)
+		require.Equal(t, expected, pragmas[0].String())
+	}
+
+	pragmas, err := contracts.ParsePragmas([]byte("contract A {}"))
+	require.NoError(t, err)
+	require.Empty(t, pragmas)
+}
+
+// TestSelectCompiler tests that the highest cached version satisfying the pragma is selected.
+func TestSelectCompiler(t *testing.T) {
+	cacheDir := newCompilerCache(
+		t,
+		"solc-0.8.10",
+		"solc-0.8.29",
+		"solc-v0.8.30+commit.73712a01",
+		"solc-0.8.31/solc-0.8.31",
+		"not-solc",
+	)
+
+	compilers, err := contracts.LocalCompilers(cacheDir)
+	require.NoError(t, err)
+	versions := make([]string, 0, len(compilers))
+	for _, c := range compilers {
+		versions = append(versions, c.Version.String())
+	}
+	require.Equal(t, []string{"0.8.31", "0.8.30", "0.8.29", "0.8.10"}, versions)
+
+	compiler, err := contracts.SelectCompiler(cacheDir, "SimpleStorageContract.sol")
+	require.NoError(t, err)
+	require.Equal(t, "0.8.31", compiler.Version.String())
+	require.Equal(t, filepath.Join(cacheDir, "solc-0.8.31", "solc-0.8.31"), compiler.Path)
+
+	// Every file's pragma must be satisfied.
+	compiler, err = contracts.SelectCompiler(cacheDir, "testdata/Pinned.sol", "SimpleTestContract.sol")
+	require.NoError(t, err)
+	require.Equal(t, "0.8.29", compiler.Version.String())
+}
+
+// TestSelectCompiler_NoMatch tests that a missing compiler version is reported with the available ones.
+func TestSelectCompiler_NoMatch(t *testing.T) {
+	cacheDir := newCompilerCache(t, "solc-0.8.10", "solc-0.7.6")
+
+	_, err := contracts.SelectCompiler(cacheDir, "SimpleStorageContract.sol")
+	require.ErrorContains(t, err, "satisfies ^0.8.30, available versions: 0.8.10, 0.7.6")
+
+	_, err = contracts.SelectCompiler(newCompilerCache(t), "SimpleStorageContract.sol")
+	require.ErrorContains(t, err, "no solc binaries in")
+
+	_, err = contracts.SelectCompiler(filepath.Join(cacheDir, "missing"), "SimpleStorageContract.sol")
+	require.ErrorContains(t, err, "read solc cache dir")
+}
+
+// TestCompile_SelectsCachedCompiler tests that Compile runs the compiler selected from the cache directory.
+func TestCompile_SelectsCachedCompiler(t *testing.T) {
+	cacheDir := newCompilerCache(t, "solc-0.8.10", "solc-0.8.30")
+
+	artifacts, err := contracts.Compile(
+		contracts.CompileOptions{CompilerCacheDir: cacheDir, NoCache: true}, "SimpleStorageContract.sol",
+	)
+	require.NoError(t, err)
+	stub, err := artifacts.Contract("Stub")
+	require.NoError(t, err)
+	require.Equal(t, "solc-0.8.30", stub.Bin, "the stub of the selected version should have run")
+	require.Equal(t, "0.8.30", stub.CompilerVersion.String())
+
+	t.Setenv(contracts.CompilerCacheDirEnv, newCompilerCache(t, "solc-0.8.20"))
+	_, err = contracts.Compile(contracts.CompileOptions{}, "SimpleStorageContract.sol")
+	require.ErrorContains(t, err, "available versions: 0.8.20")
+}
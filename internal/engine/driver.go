@@ -0,0 +1,363 @@
+// Package engine drives execution layer nodes through the Engine API, the
+// interface a consensus client uses to build and import blocks.
+package engine
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	gethengine "github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/rs/zerolog"
+	"github.com/thep2p/go-eth-localnet/internal/model"
+)
+
+// Engine API methods used by the Driver. The versions match a chain with
+// Prague active from genesis, as created by node.NewGenesis.
+const (
+	// MethodForkchoiceUpdated updates the forkchoice and optionally starts building a payload.
+	MethodForkchoiceUpdated = "engine_forkchoiceUpdatedV3"
+	// MethodGetPayload retrieves a payload started by MethodForkchoiceUpdated.
+	MethodGetPayload = "engine_getPayloadV4"
+	// MethodNewPayload submits a payload for validation and import.
+	MethodNewPayload = "engine_newPayloadV4"
+)
+
+// JWTSecretLength is the length in bytes of an Engine API JWT secret.
+const JWTSecretLength = 32
+
+var (
+	// ErrPayloadInvalid indicates the node did not accept a payload or forkchoice update as VALID.
+	ErrPayloadInvalid = errors.New("payload not valid")
+	// ErrNoPayloadID indicates the node did not start building a payload.
+	ErrNoPayloadID = errors.New("no payload id returned")
+)
+
+// BuildOptions are the payload attributes of a block built by the Driver.
+type BuildOptions struct {
+	// Timestamp of the block. Zero means the later of the parent timestamp + 1
+	// and the current time.
+	Timestamp uint64
+	// FeeRecipient receives the priority fees of the block.
+	FeeRecipient common.Address
+	// PrevRandao is the randomness mix of the block.
+	PrevRandao common.Hash
+	// Withdrawals are credited by the block. Nil means no withdrawals.
+	Withdrawals []*types.Withdrawal
+	// BeaconRoot is the parent beacon block root (EIP-4788). Zero by default,
+	// since there is no beacon chain.
+	BeaconRoot common.Hash
+}
+
+// Payload is a block built by the node together with the data needed to import it.
+type Payload struct {
+	// ExecutionPayload is the built block.
+	ExecutionPayload *gethengine.ExecutableData
+	// BlockValue is the fee revenue of the block in wei.
+	BlockValue *hexutil.Big
+	// BlobsBundle holds the blobs, commitments and proofs of the block's blob transactions.
+	BlobsBundle *gethengine.BlobsBundleV1
+	// ExecutionRequests are the EIP-7685 requests (deposits, withdrawals, consolidations) of the block.
+	ExecutionRequests []hexutil.Bytes
+	// BeaconRoot is the parent beacon block root the payload was built with.
+	BeaconRoot common.Hash
+}
+
+// VersionedHashes returns the EIP-4844 versioned hashes of the payload's blob commitments,
+// in the order newPayload expects them.
+func (p *Payload) VersionedHashes() []common.Hash {
+	hashes := make([]common.Hash, 0)
+	if p.BlobsBundle == nil {
+		return hashes
+	}
+	hasher := sha256.New()
+	for _, c := range p.BlobsBundle.Commitments {
+		var commitment kzg4844.Commitment
+		copy(commitment[:], c)
+		hashes = append(hashes, kzg4844.CalcBlobHashV1(hasher, &commitment))
+	}
+	return hashes
+}
+
+// Driver plays the role of a consensus client for a single EL node: it builds
+// blocks with forkchoiceUpdated → getPayload → newPayload → forkchoiceUpdated
+// over the node's authenticated Engine API.
+//
+// The node must not be driven by anything else at the same time, e.g. start it
+// with Manager.EnableExternalConsensus so no simulated beacon is attached.
+type Driver struct {
+	logger zerolog.Logger
+	client *rpc.Client
+
+	mu         sync.Mutex
+	forkchoice gethengine.ForkchoiceStateV1
+	headTime   uint64
+}
+
+// NewDriver connects to a node's Engine API and starts from its current head.
+//
+// Args:
+//   - ctx: context for connecting and fetching the head
+//   - logger: logger for block production events
+//   - endpoint: Engine API URL, e.g. http://127.0.0.1:8551
+//   - jwtSecret: hex-encoded 32-byte JWT secret of the node (see Manager.GetJWTSecret)
+//
+// Returns a Driver whose head, safe and finalized blocks are the node's latest
+// block. All errors are CRITICAL and indicate the node cannot be driven.
+func NewDriver(ctx context.Context, logger zerolog.Logger, endpoint string, jwtSecret []byte) (*Driver, error) {
+	client, err := Dial(ctx, endpoint, jwtSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	var head map[string]interface{}
+	if err := client.CallContext(ctx, &head, model.EthGetBlockByNumber, model.EthBlockLatest, false); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("get head block: %w", err)
+	}
+	headHash, headTime, err := hashAndTimestamp(head)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("decode head block: %w", err)
+	}
+
+	return &Driver{
+		logger: logger.With().Str("component", "engine-driver").Str("endpoint", endpoint).Logger(),
+		client: client,
+		forkchoice: gethengine.ForkchoiceStateV1{
+			HeadBlockHash:      headHash,
+			SafeBlockHash:      headHash,
+			FinalizedBlockHash: headHash,
+		},
+		headTime: headTime,
+	}, nil
+}
+
+// Dial creates an RPC client for a node's authenticated Engine API.
+//
+// Args:
+//   - ctx: context for connecting
+//   - endpoint: Engine API URL
+//   - jwtSecret: hex-encoded 32-byte JWT secret, optionally 0x-prefixed
+func Dial(ctx context.Context, endpoint string, jwtSecret []byte) (*rpc.Client, error) {
+	secret, err := DecodeJWTSecret(jwtSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := rpc.DialOptions(ctx, endpoint, rpc.WithHTTPAuth(node.NewJWTAuth(secret)))
+	if err != nil {
+		return nil, fmt.Errorf("dial engine api %s: %w", endpoint, err)
+	}
+	return client, nil
+}
+
+// DecodeJWTSecret decodes a hex-encoded 32-byte JWT secret, optionally
+// 0x-prefixed and surrounded by whitespace, as geth writes it to jwt.hex.
+// Returns an error if the secret is not hex or not 32 bytes long.
+func DecodeJWTSecret(jwtSecret []byte) ([JWTSecretLength]byte, error) {
+	var secret [JWTSecretLength]byte
+	secretBytes, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(jwtSecret)), "0x"))
+	if err != nil {
+		return secret, fmt.Errorf("decode jwt secret: %w", err)
+	}
+	if len(secretBytes) != JWTSecretLength {
+		return secret, fmt.Errorf("jwt secret must be %d bytes, got %d", JWTSecretLength, len(secretBytes))
+	}
+	copy(secret[:], secretBytes)
+	return secret, nil
+}
+
+// Close closes the Engine API connection.
+func (d *Driver) Close() {
+	d.client.Close()
+}
+
+// Forkchoice returns the forkchoice state last sent to the node.
+func (d *Driver) Forkchoice() gethengine.ForkchoiceStateV1 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.forkchoice
+}
+
+// ProduceBlock builds a block on the current head and makes it the new head.
+//
+// Runs the full block production loop: forkchoiceUpdated with payload attributes,
+// getPayload, newPayload, and forkchoiceUpdated to the new block. The new block
+// also becomes the safe block; the finalized block is unchanged (see UpdateForkchoice).
+//
+// Returns the imported payload. All errors are CRITICAL; a rejected payload or
+// forkchoice update wraps ErrPayloadInvalid.
+func (d *Driver) ProduceBlock(ctx context.Context, opts BuildOptions) (*Payload, error) {
+	payload, err := d.BuildPayload(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.ImportPayload(ctx, payload); err != nil {
+		return nil, err
+	}
+
+	state := d.Forkchoice()
+	state.HeadBlockHash = payload.ExecutionPayload.BlockHash
+	state.SafeBlockHash = payload.ExecutionPayload.BlockHash
+	if err := d.UpdateForkchoice(ctx, state); err != nil {
+		return nil, err
+	}
+
+	d.logger.Info().
+		Uint64("number", payload.ExecutionPayload.Number).
+		Str("hash", payload.ExecutionPayload.BlockHash.Hex()).
+		Int("txs", len(payload.ExecutionPayload.Transactions)).
+		Msg("block produced")
+	return payload, nil
+}
+
+// BuildPayload asks the node to build a block on the current head and returns it
+// without importing it.
+//
+// Returns the built payload. All errors are CRITICAL.
+func (d *Driver) BuildPayload(ctx context.Context, opts BuildOptions) (*Payload, error) {
+	d.mu.Lock()
+	state := d.forkchoice
+	timestamp := opts.Timestamp
+	if timestamp == 0 {
+		timestamp = max(d.headTime+1, uint64(time.Now().Unix()))
+	}
+	d.mu.Unlock()
+
+	withdrawals := opts.Withdrawals
+	if withdrawals == nil {
+		withdrawals = make([]*types.Withdrawal, 0)
+	}
+	beaconRoot := opts.BeaconRoot
+	attrs := &gethengine.PayloadAttributes{
+		Timestamp:             timestamp,
+		Random:                opts.PrevRandao,
+		SuggestedFeeRecipient: opts.FeeRecipient,
+		Withdrawals:           withdrawals,
+		BeaconRoot:            &beaconRoot,
+	}
+
+	var resp gethengine.ForkChoiceResponse
+	if err := d.client.CallContext(ctx, &resp, MethodForkchoiceUpdated, state, attrs); err != nil {
+		return nil, fmt.Errorf("%s: %w", MethodForkchoiceUpdated, err)
+	}
+	if err := requireValid(resp.PayloadStatus); err != nil {
+		return nil, fmt.Errorf("%s: %w", MethodForkchoiceUpdated, err)
+	}
+	if resp.PayloadID == nil {
+		return nil, fmt.Errorf("%s: %w", MethodForkchoiceUpdated, ErrNoPayloadID)
+	}
+
+	var envelope gethengine.ExecutionPayloadEnvelope
+	if err := d.client.CallContext(ctx, &envelope, MethodGetPayload, resp.PayloadID); err != nil {
+		return nil, fmt.Errorf("%s: %w", MethodGetPayload, err)
+	}
+
+	requests := make([]hexutil.Bytes, len(envelope.Requests))
+	for i, r := range envelope.Requests {
+		requests[i] = r
+	}
+	return &Payload{
+		ExecutionPayload:  envelope.ExecutionPayload,
+		BlockValue:        (*hexutil.Big)(envelope.BlockValue),
+		BlobsBundle:       envelope.BlobsBundle,
+		ExecutionRequests: requests,
+		BeaconRoot:        beaconRoot,
+	}, nil
+}
+
+// ImportPayload submits a payload with newPayload and requires the node to accept it as VALID.
+// It does not change the forkchoice. All errors are CRITICAL.
+func (d *Driver) ImportPayload(ctx context.Context, payload *Payload) error {
+	beaconRoot := payload.BeaconRoot
+	requests := payload.ExecutionRequests
+	if requests == nil {
+		requests = make([]hexutil.Bytes, 0)
+	}
+
+	var status gethengine.PayloadStatusV1
+	if err := d.client.CallContext(
+		ctx, &status, MethodNewPayload,
+		payload.ExecutionPayload, payload.VersionedHashes(), &beaconRoot, requests,
+	); err != nil {
+		return fmt.Errorf("%s: %w", MethodNewPayload, err)
+	}
+	if err := requireValid(status); err != nil {
+		return fmt.Errorf("%s: %w", MethodNewPayload, err)
+	}
+	return nil
+}
+
+// UpdateForkchoice sets the node's head, safe and finalized blocks.
+// Use it to finalize blocks or to switch the head to another known block. All errors are CRITICAL.
+func (d *Driver) UpdateForkchoice(ctx context.Context, state gethengine.ForkchoiceStateV1) error {
+	var resp gethengine.ForkChoiceResponse
+	if err := d.client.CallContext(ctx, &resp, MethodForkchoiceUpdated, state, nil); err != nil {
+		return fmt.Errorf("%s: %w", MethodForkchoiceUpdated, err)
+	}
+	if err := requireValid(resp.PayloadStatus); err != nil {
+		return fmt.Errorf("%s: %w", MethodForkchoiceUpdated, err)
+	}
+
+	var head map[string]interface{}
+	if err := d.client.CallContext(ctx, &head, model.EthGetBlockByNumber, model.EthBlockLatest, false); err != nil {
+		return fmt.Errorf("get head block: %w", err)
+	}
+	headHash, headTime, err := hashAndTimestamp(head)
+	if err != nil {
+		return fmt.Errorf("decode head block: %w", err)
+	}
+
+	if headHash != state.HeadBlockHash {
+		return fmt.Errorf("node head %s, expected %s", headHash.Hex(), state.HeadBlockHash.Hex())
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.forkchoice = state
+	d.headTime = headTime
+	return nil
+}
+
+// requireValid returns nil if status is VALID, otherwise an error wrapping ErrPayloadInvalid.
+func requireValid(status gethengine.PayloadStatusV1) error {
+	if status.Status == gethengine.VALID {
+		return nil
+	}
+	if status.ValidationError != nil {
+		return fmt.Errorf("%w: status %s: %s", ErrPayloadInvalid, status.Status, *status.ValidationError)
+	}
+	return fmt.Errorf("%w: status %s", ErrPayloadInvalid, status.Status)
+}
+
+// hashAndTimestamp extracts the hash and timestamp of a block returned by eth_getBlockByNumber.
+func hashAndTimestamp(block map[string]interface{}) (common.Hash, uint64, error) {
+	if block == nil {
+		return common.Hash{}, 0, fmt.Errorf("block not found")
+	}
+	hashHex, ok := block[model.BlockHash].(string)
+	if !ok {
+		return common.Hash{}, 0, fmt.Errorf("block has no hash")
+	}
+	timestampHex, ok := block[model.BlockTimestamp].(string)
+	if !ok {
+		return common.Hash{}, 0, fmt.Errorf("block has no timestamp")
+	}
+	timestamp, err := hexutil.DecodeUint64(timestampHex)
+	if err != nil {
+		return common.Hash{}, 0, fmt.Errorf("decode timestamp: %w", err)
+	}
+	return common.HexToHash(hashHex), timestamp, nil
+}
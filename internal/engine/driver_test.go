@@ -0,0 +1,167 @@
+package engine_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+	"github.com/thep2p/go-eth-localnet/internal/engine"
+	"github.com/thep2p/go-eth-localnet/internal/model"
+	"github.com/thep2p/go-eth-localnet/internal/node"
+	"github.com/thep2p/go-eth-localnet/internal/unittest"
+)
+
+// startExternalNode starts a single node without a simulated beacon and returns
+// an engine driver for it and an RPC client to its public endpoint.
+func startExternalNode(t *testing.T, opts ...node.LaunchOption) (context.Context, *engine.Driver, *rpc.Client) {
+	t.Helper()
+
+	ctx, manager := unittest.StartExternalNodes(t, 1, opts...)
+	driver, err := manager.NewEngineDriver(ctx, 0)
+	require.NoError(t, err)
+	t.Cleanup(driver.Close)
+
+	return ctx, driver, unittest.DialNode(t, ctx, manager, 0)
+}
+
+// getBlock returns the block at the given number or label.
+func getBlock(t *testing.T, ctx context.Context, client *rpc.Client, number string) map[string]interface{} {
+	t.Helper()
+
+	var block map[string]interface{}
+	require.NoError(t, client.CallContext(ctx, &block, model.EthGetBlockByNumber, number, false))
+	require.NotNil(t, block)
+	return block
+}
+
+// TestDriverProduceBlocks verifies the driver alone advances a node without a
+// simulated beacon, with the timestamps and fee recipient it chooses.
+func TestDriverProduceBlocks(t *testing.T) {
+	ctx, driver, client := startExternalNode(t)
+
+	// Nothing else produces blocks.
+	time.Sleep(1500 * time.Millisecond)
+	require.Equal(t, "0x0", getBlock(t, ctx, client, model.EthBlockLatest)[model.BlockNumber])
+
+	genesisTime := hexutil.MustDecodeUint64(getBlock(t, ctx, client, "0x0")[model.BlockTimestamp].(string))
+	feeRecipient := unittest.RandomAddress(t)
+	for i := uint64(1); i <= 3; i++ {
+		payload, err := driver.ProduceBlock(ctx, engine.BuildOptions{
+			Timestamp:    genesisTime + 12*i,
+			FeeRecipient: feeRecipient,
+		})
+		require.NoError(t, err)
+		require.Equal(t, i, payload.ExecutionPayload.Number)
+		require.Equal(t, payload.ExecutionPayload.BlockHash, driver.Forkchoice().HeadBlockHash)
+	}
+
+	head := getBlock(t, ctx, client, model.EthBlockLatest)
+	require.Equal(t, "0x3", head[model.BlockNumber])
+	require.Equal(t, hexutil.EncodeUint64(genesisTime+36), head[model.BlockTimestamp])
+	require.Equal(t, feeRecipient, common.HexToAddress(head["miner"].(string)))
+	require.Equal(t, "0x3", getBlock(t, ctx, client, model.EthBlockSafe)[model.BlockNumber])
+}
+
+// TestDriverWithdrawals verifies withdrawals in the payload attributes are credited.
+func TestDriverWithdrawals(t *testing.T) {
+	ctx, driver, client := startExternalNode(t)
+
+	recipient := unittest.RandomAddress(t)
+	_, err := driver.ProduceBlock(ctx, engine.BuildOptions{
+		Withdrawals: []*types.Withdrawal{
+			{Index: 0, Validator: 7, Address: recipient, Amount: params.Ether / params.GWei},
+		},
+	})
+	require.NoError(t, err)
+
+	balance := unittest.GetBalance(t, ctx, client, recipient)
+	require.Equal(t, big.NewInt(params.Ether), balance)
+}
+
+// TestDriverBlobTransaction verifies a blob transaction is included and its blob
+// bundle returned with matching versioned hashes.
+func TestDriverBlobTransaction(t *testing.T) {
+	senderKey := unittest.PrivateKeyFixture(t)
+	sender := crypto.PubkeyToAddress(senderKey.PublicKey)
+	ctx, driver, client := startExternalNode(
+		t, node.WithPreFundGenesisAccount(sender, new(big.Int).Mul(big.NewInt(100), big.NewInt(params.Ether))),
+	)
+
+	var blob kzg4844.Blob
+	commitment, err := kzg4844.BlobToCommitment(&blob)
+	require.NoError(t, err)
+	proof, err := kzg4844.ComputeBlobProof(&blob, commitment)
+	require.NoError(t, err)
+	sidecar := &types.BlobTxSidecar{
+		Blobs:       []kzg4844.Blob{blob},
+		Commitments: []kzg4844.Commitment{commitment},
+		Proofs:      []kzg4844.Proof{proof},
+	}
+
+	chainID := uint256.NewInt(1337)
+	tx := types.MustSignNewTx(senderKey, types.LatestSignerForChainID(chainID.ToBig()), &types.BlobTx{
+		ChainID:    chainID,
+		Nonce:      0,
+		GasTipCap:  uint256.NewInt(params.GWei),
+		GasFeeCap:  uint256.NewInt(10 * params.GWei),
+		Gas:        21_000,
+		To:         unittest.RandomAddress(t),
+		BlobFeeCap: uint256.NewInt(params.GWei),
+		BlobHashes: sidecar.BlobHashes(),
+		Sidecar:    sidecar,
+	})
+	txBytes, err := tx.MarshalBinary()
+	require.NoError(t, err)
+	var txHash common.Hash
+	require.NoError(t, client.CallContext(ctx, &txHash, model.EthSendRawTransaction, hexutil.Encode(txBytes)))
+
+	feeRecipient := unittest.RandomAddress(t)
+	payload, err := driver.ProduceBlock(ctx, engine.BuildOptions{FeeRecipient: feeRecipient})
+	require.NoError(t, err)
+
+	require.Len(t, payload.ExecutionPayload.Transactions, 1)
+	require.NotNil(t, payload.BlobsBundle)
+	require.Len(t, payload.BlobsBundle.Blobs, 1)
+	require.Equal(t, sidecar.BlobHashes(), payload.VersionedHashes())
+	require.Positive(t, payload.BlockValue.ToInt().Sign())
+
+	var receipt map[string]interface{}
+	require.NoError(t, client.CallContext(ctx, &receipt, model.EthGetTransactionReceipt, txHash))
+	require.NotNil(t, receipt)
+	require.Equal(t, model.ReceiptTxStatusSuccess, receipt[model.ReceiptStatus])
+	require.Positive(t, unittest.GetBalance(t, ctx, client, feeRecipient).Sign())
+}
+
+// TestDriverRejectsTamperedPayload verifies payloads the node does not accept
+// are reported as invalid and do not move the head.
+func TestDriverRejectsTamperedPayload(t *testing.T) {
+	ctx, driver, client := startExternalNode(t)
+
+	payload, err := driver.BuildPayload(ctx, engine.BuildOptions{})
+	require.NoError(t, err)
+	payload.ExecutionPayload.StateRoot = common.HexToHash("0x01")
+
+	require.ErrorIs(t, driver.ImportPayload(ctx, payload), engine.ErrPayloadInvalid)
+	require.Equal(t, "0x0", getBlock(t, ctx, client, model.EthBlockLatest)[model.BlockNumber])
+}
+
+// TestDialRequiresValidSecret verifies a malformed JWT secret is rejected before dialing.
+func TestDialRequiresValidSecret(t *testing.T) {
+	t.Parallel()
+
+	_, err := engine.Dial(context.Background(), "http://127.0.0.1:1", []byte("abcd"))
+	require.ErrorContains(t, err, "32 bytes")
+
+	_, err = engine.Dial(context.Background(), "http://127.0.0.1:1", []byte("not hex"))
+	require.ErrorContains(t, err, "decode jwt secret")
+}
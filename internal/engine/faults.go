@@ -0,0 +1,415 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	gethengine "github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/rs/zerolog"
+)
+
+// jwtExpiryTimeout is the maximum clock drift of a token's iat claim, matching Geth.
+const jwtExpiryTimeout = 60 * time.Second
+
+// DefaultReorderHold is how long a reordered call waits for a later call
+// when Rule.Delay is not set.
+const DefaultReorderHold = 5 * time.Second
+
+// Action is what a FaultProxy does with a call matched by a Rule.
+type Action string
+
+const (
+	// ActionDelay forwards the call after Rule.Delay.
+	ActionDelay Action = "delay"
+	// ActionDrop closes the connection without forwarding the call or answering it.
+	ActionDrop Action = "drop"
+	// ActionReorder holds the call until a later call has been answered, or
+	// Rule.Delay (DefaultReorderHold if zero) elapses, then forwards it.
+	ActionReorder Action = "reorder"
+	// ActionSyncing answers newPayload and forkchoiceUpdated calls with status
+	// SYNCING without forwarding them. Other calls are forwarded unchanged.
+	ActionSyncing Action = "syncing"
+	// ActionInvalid answers newPayload and forkchoiceUpdated calls with status
+	// INVALID without forwarding them. Other calls are forwarded unchanged.
+	ActionInvalid Action = "invalid"
+)
+
+// Rule selects Engine API calls and the fault applied to them.
+type Rule struct {
+	// Method is the exact method matched, e.g. engine_newPayloadV4. A trailing
+	// "*" matches by prefix, e.g. engine_forkchoiceUpdated*. Empty matches
+	// every engine_* method.
+	Method string
+	// Match optionally narrows the calls matched by Method using their raw params.
+	Match func(method string, params json.RawMessage) bool
+	// Skip is the number of matching calls let through before the rule applies.
+	Skip int
+	// Times is the number of calls the rule applies to; zero means every call.
+	Times int
+	// Action is the fault applied to matching calls.
+	Action Action
+	// Delay is the delay of ActionDelay and the maximum hold of ActionReorder.
+	Delay time.Duration
+	// LatestValidHash is reported by ActionInvalid.
+	LatestValidHash common.Hash
+	// ValidationError is reported by ActionInvalid.
+	ValidationError string
+}
+
+// matches reports whether the rule selects the method.
+func (r *Rule) matches(method string, params json.RawMessage) bool {
+	switch {
+	case r.Method == "":
+		if !strings.HasPrefix(method, "engine_") {
+			return false
+		}
+	case strings.HasSuffix(r.Method, "*"):
+		if !strings.HasPrefix(method, strings.TrimSuffix(r.Method, "*")) {
+			return false
+		}
+	case r.Method != method:
+		return false
+	}
+	return r.Match == nil || r.Match(method, params)
+}
+
+// ruleState tracks how often a rule matched.
+type ruleState struct {
+	rule    Rule
+	matched int
+}
+
+// FaultProxy is an HTTP proxy in front of a node's authenticated Engine API
+// that delays, drops, reorders or answers selected calls according to rules.
+//
+// The proxy terminates JWT authentication: it verifies the consensus client's
+// token against the node's secret like Geth does and signs every forwarded
+// request with a fresh token, so delayed calls never arrive with a stale token.
+// Calls matching no rule, and batch requests, are forwarded without faults.
+type FaultProxy struct {
+	logger   zerolog.Logger
+	upstream string
+	client   *http.Client
+	listener net.Listener
+	server   *http.Server
+
+	mu     sync.Mutex
+	secret [32]byte
+	rules  []*ruleState
+	held   []chan struct{}
+}
+
+// NewFaultProxy starts a FaultProxy listening on listenAddr and forwarding to upstream.
+//
+// Args:
+//   - logger: logger for injected faults
+//   - listenAddr: address the consensus client connects to, e.g. 127.0.0.1:8552
+//   - upstream: Engine API URL of the node, e.g. http://127.0.0.1:8551
+//   - jwtSecret: hex-encoded 32-byte JWT secret of the node, optionally 0x-prefixed
+//
+// Returns the running proxy without rules. The caller closes it.
+// All errors are CRITICAL and indicate the proxy could not be started.
+func NewFaultProxy(logger zerolog.Logger, listenAddr string, upstream string, jwtSecret []byte) (*FaultProxy, error) {
+	secret, err := DecodeJWTSecret(jwtSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		return nil, fmt.Errorf("listen on %s: %w", listenAddr, err)
+	}
+
+	p := &FaultProxy{
+		logger:   logger.With().Str("component", "engine-fault-proxy").Str("upstream", upstream).Logger(),
+		upstream: upstream,
+		secret:   secret,
+		client:   &http.Client{},
+		listener: listener,
+	}
+	p.server = &http.Server{Handler: http.HandlerFunc(p.serveHTTP), ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := p.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			p.logger.Error().Err(err).Msg("engine fault proxy stopped")
+		}
+	}()
+	return p, nil
+}
+
+// Endpoint returns the URL consensus clients connect to instead of the node's Engine API.
+func (p *FaultProxy) Endpoint() string {
+	return "http://" + p.listener.Addr().String()
+}
+
+// SetJWTSecret replaces the node secret used to verify and sign tokens, e.g.
+// after the node's secret was rotated.
+func (p *FaultProxy) SetJWTSecret(jwtSecret []byte) error {
+	secret, err := DecodeJWTSecret(jwtSecret)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.secret = secret
+	return nil
+}
+
+// AddRule appends a rule. Rules are evaluated in the order they were added and
+// the first applicable rule wins. Returns an error if the rule is malformed.
+func (p *FaultProxy) AddRule(rule Rule) error {
+	switch rule.Action {
+	case ActionDelay:
+		if rule.Delay <= 0 {
+			return fmt.Errorf("delay rule requires a positive delay")
+		}
+	case ActionDrop, ActionReorder, ActionSyncing, ActionInvalid:
+	default:
+		return fmt.Errorf("unknown fault action %q", rule.Action)
+	}
+	if rule.Skip < 0 || rule.Times < 0 {
+		return fmt.Errorf("skip and times must not be negative")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rules = append(p.rules, &ruleState{rule: rule})
+	return nil
+}
+
+// ClearRules removes all rules and releases held calls; subsequent calls are forwarded unchanged.
+func (p *FaultProxy) ClearRules() {
+	p.mu.Lock()
+	p.rules = nil
+	p.mu.Unlock()
+	p.releaseHeld()
+}
+
+// Close stops the proxy and releases held calls.
+func (p *FaultProxy) Close() error {
+	p.releaseHeld()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := p.server.Shutdown(ctx); err != nil {
+		return fmt.Errorf("shutdown engine fault proxy: %w", err)
+	}
+	return nil
+}
+
+// serveHTTP authenticates a request, applies the first applicable rule and forwards it.
+func (p *FaultProxy) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	if status, err := p.authenticate(req); err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, "read request body", http.StatusBadRequest)
+		return
+	}
+
+	var (
+		call jsonrpcMessage
+		rule *Rule
+	)
+	if err := json.Unmarshal(body, &call); err == nil && call.Method != "" {
+		rule = p.nextRule(call.Method, call.Params)
+	}
+
+	if rule != nil {
+		p.logger.Info().Str("method", call.Method).Str("action", string(rule.Action)).Msg("injecting engine api fault")
+		switch rule.Action {
+		case ActionDelay:
+			select {
+			case <-req.Context().Done():
+				return
+			case <-time.After(rule.Delay):
+			}
+		case ActionDrop:
+			dropConnection(w)
+			return
+		case ActionReorder:
+			p.hold(req.Context(), rule.Delay)
+		case ActionSyncing, ActionInvalid:
+			if result, ok := syntheticResult(call.Method, rule); ok {
+				writeResult(w, call.ID, result)
+				return
+			}
+		}
+	}
+
+	header := req.Header.Clone()
+	header.Set("Authorization", "Bearer "+p.newToken())
+	status, respHeader, respBody, err := forward(req.Context(), p.client, p.upstream, req.Method, header, body)
+	if err != nil {
+		p.logger.Error().Err(err).Msg("forward engine api request")
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	writeResponse(w, status, respHeader, respBody)
+
+	if rule == nil || rule.Action != ActionReorder {
+		p.releaseHeld()
+	}
+}
+
+// nextRule returns a copy of the first rule that applies to the call and counts the match.
+func (p *FaultProxy) nextRule(method string, params json.RawMessage) *Rule {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, state := range p.rules {
+		if !state.rule.matches(method, params) {
+			continue
+		}
+		state.matched++
+		if state.matched <= state.rule.Skip {
+			continue
+		}
+		if state.rule.Times > 0 && state.matched > state.rule.Skip+state.rule.Times {
+			continue
+		}
+		rule := state.rule
+		return &rule
+	}
+	return nil
+}
+
+// hold blocks until a later call has been answered, maxHold elapses or ctx is done.
+func (p *FaultProxy) hold(ctx context.Context, maxHold time.Duration) {
+	if maxHold <= 0 {
+		maxHold = DefaultReorderHold
+	}
+	release := make(chan struct{})
+	p.mu.Lock()
+	p.held = append(p.held, release)
+	p.mu.Unlock()
+
+	select {
+	case <-release:
+	case <-ctx.Done():
+	case <-time.After(maxHold):
+	}
+}
+
+// releaseHeld releases all calls held by ActionReorder.
+func (p *FaultProxy) releaseHeld() {
+	p.mu.Lock()
+	held := p.held
+	p.held = nil
+	p.mu.Unlock()
+	for _, release := range held {
+		close(release)
+	}
+}
+
+// authenticate verifies the request's JWT like Geth's authenticated RPC server.
+// It returns the HTTP status to answer with if the token is rejected.
+func (p *FaultProxy) authenticate(req *http.Request) (int, error) {
+	auth := req.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return http.StatusUnauthorized, fmt.Errorf("missing token")
+	}
+
+	p.mu.Lock()
+	secret := p.secret
+	p.mu.Unlock()
+
+	var claims jwt.RegisteredClaims
+	token, err := jwt.ParseWithClaims(
+		strings.TrimPrefix(auth, "Bearer "), &claims,
+		func(*jwt.Token) (interface{}, error) { return secret[:], nil },
+		jwt.WithValidMethods([]string{"HS256"}),
+		jwt.WithoutClaimsValidation(),
+	)
+	switch {
+	case err != nil:
+		return http.StatusUnauthorized, err
+	case !token.Valid:
+		return http.StatusUnauthorized, fmt.Errorf("invalid token")
+	case !claims.VerifyExpiresAt(time.Now(), false):
+		return http.StatusUnauthorized, fmt.Errorf("token is expired")
+	case claims.IssuedAt == nil:
+		return http.StatusUnauthorized, fmt.Errorf("missing issued-at")
+	case time.Since(claims.IssuedAt.Time) > jwtExpiryTimeout:
+		return http.StatusUnauthorized, fmt.Errorf("stale token")
+	case time.Until(claims.IssuedAt.Time) > jwtExpiryTimeout:
+		return http.StatusUnauthorized, fmt.Errorf("future token")
+	}
+	return http.StatusOK, nil
+}
+
+// newToken signs a token for the node with the current time as iat.
+func (p *FaultProxy) newToken() string {
+	p.mu.Lock()
+	secret := p.secret
+	p.mu.Unlock()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"iat": &jwt.NumericDate{Time: time.Now()},
+	})
+	// Signing with HS256 and a fixed-size key cannot fail.
+	signed, _ := token.SignedString(secret[:])
+	return signed
+}
+
+// syntheticResult returns the SYNCING or INVALID result of a newPayload or
+// forkchoiceUpdated call. It returns false for other methods.
+func syntheticResult(method string, rule *Rule) (interface{}, bool) {
+	status := gethengine.PayloadStatusV1{Status: gethengine.SYNCING}
+	if rule.Action == ActionInvalid {
+		status.Status = gethengine.INVALID
+		latestValid := rule.LatestValidHash
+		status.LatestValidHash = &latestValid
+		if rule.ValidationError != "" {
+			validationError := rule.ValidationError
+			status.ValidationError = &validationError
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(method, "engine_newPayload"):
+		return status, true
+	case strings.HasPrefix(method, "engine_forkchoiceUpdated"):
+		return gethengine.ForkChoiceResponse{PayloadStatus: status}, true
+	default:
+		return nil, false
+	}
+}
+
+// writeResult answers a call with a JSON-RPC result.
+func writeResult(w http.ResponseWriter, id json.RawMessage, result interface{}) {
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(struct {
+		JSONRPC string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id"`
+		Result  json.RawMessage `json:"result"`
+	}{JSONRPC: "2.0", ID: id, Result: resultJSON})
+}
+
+// dropConnection closes the client connection without an HTTP response.
+func dropConnection(w http.ResponseWriter) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		panic(http.ErrAbortHandler)
+	}
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+	_ = conn.Close()
+}
@@ -0,0 +1,153 @@
+package engine_test
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"testing"
+	"time"
+
+	gethengine "github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/stretchr/testify/require"
+	"github.com/thep2p/go-eth-localnet/internal/engine"
+	"github.com/thep2p/go-eth-localnet/internal/model"
+	"github.com/thep2p/go-eth-localnet/internal/unittest"
+)
+
+// TestFaultProxySyncingAndInvalid verifies newPayload and forkchoiceUpdated calls
+// are answered with the configured status and not forwarded, and that rules
+// stop applying once exhausted.
+func TestFaultProxySyncingAndInvalid(t *testing.T) {
+	ctx, manager := unittest.StartExternalNodes(t, 1)
+	proxy, err := manager.StartEngineFaultProxy(0)
+	require.NoError(t, err)
+	require.Equal(t, proxy.Endpoint(), manager.GetEngineEndpoint(0))
+
+	require.NoError(t, proxy.AddRule(engine.Rule{Method: engine.MethodNewPayload, Action: engine.ActionSyncing, Times: 1}))
+	require.NoError(t, proxy.AddRule(engine.Rule{
+		Method:          "engine_forkchoiceUpdated*",
+		Skip:            1,
+		Times:           1,
+		Action:          engine.ActionInvalid,
+		ValidationError: "injected",
+	}))
+
+	driver, err := manager.NewEngineDriver(ctx, 0)
+	require.NoError(t, err)
+	defer driver.Close()
+
+	// First block: newPayload reports SYNCING.
+	_, err = driver.ProduceBlock(ctx, engine.BuildOptions{})
+	require.ErrorIs(t, err, engine.ErrPayloadInvalid)
+	require.ErrorContains(t, err, gethengine.SYNCING)
+
+	// Second block: the second forkchoiceUpdated (after the skipped first one) reports INVALID.
+	_, err = driver.ProduceBlock(ctx, engine.BuildOptions{})
+	require.ErrorIs(t, err, engine.ErrPayloadInvalid)
+	require.ErrorContains(t, err, "injected")
+
+	// Both rules are exhausted.
+	payload, err := driver.ProduceBlock(ctx, engine.BuildOptions{})
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), payload.ExecutionPayload.Number)
+}
+
+// TestFaultProxyDelayAndDrop verifies calls are delayed and dropped connections fail the call.
+func TestFaultProxyDelayAndDrop(t *testing.T) {
+	ctx, manager := unittest.StartExternalNodes(t, 1)
+	proxy, err := manager.StartEngineFaultProxy(0)
+	require.NoError(t, err)
+
+	driver, err := manager.NewEngineDriver(ctx, 0)
+	require.NoError(t, err)
+	defer driver.Close()
+
+	delay := 500 * time.Millisecond
+	require.NoError(t, proxy.AddRule(engine.Rule{Method: engine.MethodGetPayload, Action: engine.ActionDelay, Delay: delay}))
+	start := time.Now()
+	_, err = driver.ProduceBlock(ctx, engine.BuildOptions{})
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, time.Since(start), delay)
+
+	proxy.ClearRules()
+	require.NoError(t, proxy.AddRule(engine.Rule{Method: engine.MethodNewPayload, Action: engine.ActionDrop, Times: 1}))
+	_, err = driver.ProduceBlock(ctx, engine.BuildOptions{})
+	require.Error(t, err)
+	require.NotErrorIs(t, err, engine.ErrPayloadInvalid)
+
+	payload, err := driver.ProduceBlock(ctx, engine.BuildOptions{})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), payload.ExecutionPayload.Number)
+}
+
+// TestFaultProxyReorder verifies a held call is answered after a later call.
+func TestFaultProxyReorder(t *testing.T) {
+	ctx, manager := unittest.StartExternalNodes(t, 1)
+	proxy, err := manager.StartEngineFaultProxy(0)
+	require.NoError(t, err)
+	require.NoError(t, proxy.AddRule(engine.Rule{Method: model.EthChainID, Action: engine.ActionReorder, Times: 1}))
+
+	jwtSecret, err := manager.GetJWTSecret(0)
+	require.NoError(t, err)
+	client, err := engine.Dial(ctx, manager.GetEngineEndpoint(0), jwtSecret)
+	require.NoError(t, err)
+	defer client.Close()
+
+	var (
+		mu    sync.Mutex
+		order []string
+		wg    sync.WaitGroup
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		var chainID string
+		require.NoError(t, client.CallContext(ctx, &chainID, model.EthChainID))
+		mu.Lock()
+		order = append(order, model.EthChainID)
+		mu.Unlock()
+	}()
+
+	// Give the first call time to reach the proxy.
+	time.Sleep(200 * time.Millisecond)
+	var blockNumber string
+	require.NoError(t, client.CallContext(ctx, &blockNumber, model.EthBlockNumber))
+	mu.Lock()
+	order = append(order, model.EthBlockNumber)
+	mu.Unlock()
+
+	wg.Wait()
+	require.Equal(t, []string{model.EthBlockNumber, model.EthChainID}, order)
+}
+
+// TestFaultProxyRequiresNodeSecret verifies the proxy rejects tokens not signed with the node's secret.
+func TestFaultProxyRequiresNodeSecret(t *testing.T) {
+	ctx, manager := unittest.StartExternalNodes(t, 1)
+	_, err := manager.StartEngineFaultProxy(0)
+	require.NoError(t, err)
+
+	_, err = manager.StartEngineFaultProxy(0)
+	require.ErrorContains(t, err, "already started")
+
+	otherSecret := make([]byte, 32)
+	_, err = rand.Read(otherSecret)
+	require.NoError(t, err)
+	client, err := engine.Dial(ctx, manager.GetEngineEndpoint(0), []byte(hex.EncodeToString(otherSecret)))
+	require.NoError(t, err)
+	defer client.Close()
+
+	var blockNumber string
+	err = client.CallContext(ctx, &blockNumber, model.EthBlockNumber)
+	require.ErrorContains(t, err, "401")
+}
+
+// TestFaultProxyRejectsMalformedRules verifies AddRule validates rules.
+func TestFaultProxyRejectsMalformedRules(t *testing.T) {
+	_, manager := unittest.StartExternalNodes(t, 1)
+	proxy, err := manager.StartEngineFaultProxy(0)
+	require.NoError(t, err)
+
+	require.ErrorContains(t, proxy.AddRule(engine.Rule{Action: engine.ActionDelay}), "positive delay")
+	require.ErrorContains(t, proxy.AddRule(engine.Rule{Action: "explode"}), "unknown fault action")
+	require.ErrorContains(t, proxy.AddRule(engine.Rule{Action: engine.ActionDrop, Times: -1}), "must not be negative")
+}
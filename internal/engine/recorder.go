@@ -0,0 +1,280 @@
+package engine
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// maxRecordLineSize bounds a single line of a recorded session. Payloads with
+// blobs are several hundred kilobytes, so the default bufio limit is too small.
+const maxRecordLineSize = 64 * 1024 * 1024
+
+// Record is a single Engine API call captured by a Recorder. A recorded
+// session is stored as JSONL, one Record per line, in the order the calls
+// completed.
+type Record struct {
+	// Time is when the call arrived at the recorder.
+	Time time.Time `json:"time"`
+	// Duration is the round trip to the node in nanoseconds.
+	Duration time.Duration `json:"duration"`
+	// HTTPStatus is the status code the node answered with, e.g. 401 for a rejected JWT.
+	HTTPStatus int `json:"httpStatus"`
+	// Method is the JSON-RPC method, e.g. engine_newPayloadV4.
+	Method string `json:"method"`
+	// Params are the raw JSON-RPC params.
+	Params json.RawMessage `json:"params,omitempty"`
+	// Result is the raw JSON-RPC result; empty if the call failed.
+	Result json.RawMessage `json:"result,omitempty"`
+	// Error is the raw JSON-RPC error object; empty if the call succeeded.
+	Error json.RawMessage `json:"error,omitempty"`
+}
+
+// jsonrpcMessage is the subset of a JSON-RPC 2.0 request or response the recorder inspects.
+type jsonrpcMessage struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  json.RawMessage `json:"error,omitempty"`
+}
+
+// Recorder is an HTTP proxy between a consensus client and a node's
+// authenticated Engine API that records every call to a JSONL file.
+//
+// Requests are forwarded unchanged, including the JWT Authorization header,
+// so the consensus client authenticates with the node's secret as usual and
+// the node remains the one enforcing authentication.
+type Recorder struct {
+	logger   zerolog.Logger
+	upstream string
+	client   *http.Client
+	listener net.Listener
+	server   *http.Server
+
+	mu   sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+// NewRecorder starts a Recorder listening on listenAddr and forwarding to upstream.
+//
+// Args:
+//   - logger: logger for proxy errors
+//   - listenAddr: address the consensus client connects to, e.g. 127.0.0.1:8552
+//   - upstream: Engine API URL of the node, e.g. http://127.0.0.1:8551
+//   - path: JSONL file the session is written to; it is truncated if it exists
+//
+// Returns the running Recorder. The caller closes it to flush the session.
+// All errors are CRITICAL and indicate the proxy could not be started.
+func NewRecorder(logger zerolog.Logger, listenAddr string, upstream string, path string) (*Recorder, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("open record file: %w", err)
+	}
+
+	listener, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("listen on %s: %w", listenAddr, err)
+	}
+
+	r := &Recorder{
+		logger:   logger.With().Str("component", "engine-recorder").Str("upstream", upstream).Logger(),
+		upstream: upstream,
+		client:   &http.Client{},
+		listener: listener,
+		file:     file,
+		enc:      json.NewEncoder(file),
+	}
+	r.server = &http.Server{Handler: http.HandlerFunc(r.serveHTTP), ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := r.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			r.logger.Error().Err(err).Msg("engine recorder stopped")
+		}
+	}()
+	return r, nil
+}
+
+// Endpoint returns the URL consensus clients connect to instead of the node's Engine API.
+func (r *Recorder) Endpoint() string {
+	return "http://" + r.listener.Addr().String()
+}
+
+// Close stops the proxy and closes the session file.
+func (r *Recorder) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	shutdownErr := r.server.Shutdown(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("close record file: %w", err)
+	}
+	if shutdownErr != nil {
+		return fmt.Errorf("shutdown engine recorder: %w", shutdownErr)
+	}
+	return nil
+}
+
+// serveHTTP forwards a request to the node and records the calls it contains.
+func (r *Recorder) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, "read request body", http.StatusBadRequest)
+		return
+	}
+
+	start := time.Now()
+	status, header, respBody, err := forward(req.Context(), r.client, r.upstream, req.Method, req.Header.Clone(), body)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("forward engine api request")
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	duration := time.Since(start)
+
+	writeResponse(w, status, header, respBody)
+	r.record(start, duration, status, body, respBody)
+}
+
+// record appends the calls of one HTTP exchange to the session file.
+func (r *Recorder) record(start time.Time, duration time.Duration, status int, reqBody, respBody []byte) {
+	records := pairMessages(reqBody, respBody)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, rec := range records {
+		rec.Time = start
+		rec.Duration = duration
+		rec.HTTPStatus = status
+		if err := r.enc.Encode(rec); err != nil {
+			r.logger.Error().Err(err).Str("method", rec.Method).Msg("write engine api record")
+		}
+	}
+}
+
+// forward sends body to upstream with the given headers and returns the response.
+func forward(ctx context.Context, client *http.Client, upstream string, method string, header http.Header, body []byte) (int, http.Header, []byte, error) {
+	out, err := http.NewRequestWithContext(ctx, method, upstream, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("build upstream request: %w", err)
+	}
+	out.Header = header
+	// Let the transport negotiate compression so the response can be inspected.
+	out.Header.Del("Accept-Encoding")
+
+	resp, err := client.Do(out)
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("upstream request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("read upstream response: %w", err)
+	}
+	return resp.StatusCode, resp.Header, respBody, nil
+}
+
+// writeResponse writes an upstream response back to the consensus client.
+func writeResponse(w http.ResponseWriter, status int, header http.Header, body []byte) {
+	for k, values := range header {
+		if k == "Content-Length" {
+			continue
+		}
+		for _, v := range values {
+			w.Header().Add(k, v)
+		}
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+// pairMessages splits a single or batch JSON-RPC exchange into Records,
+// matching responses to requests by id. Unparseable requests yield no records.
+func pairMessages(reqBody, respBody []byte) []Record {
+	requests, err := decodeMessages(reqBody)
+	if err != nil {
+		return nil
+	}
+	// The response is not JSON-RPC when the node rejects the request at the
+	// HTTP level, e.g. with 401; the calls are then recorded without a result.
+	responses, _ := decodeMessages(respBody)
+	byID := make(map[string]jsonrpcMessage, len(responses))
+	for _, resp := range responses {
+		byID[string(resp.ID)] = resp
+	}
+
+	records := make([]Record, 0, len(requests))
+	for _, req := range requests {
+		rec := Record{Method: req.Method, Params: req.Params}
+		if resp, ok := byID[string(req.ID)]; ok {
+			rec.Result = resp.Result
+			rec.Error = resp.Error
+		}
+		records = append(records, rec)
+	}
+	return records
+}
+
+// decodeMessages decodes a single JSON-RPC message or a batch of them.
+func decodeMessages(body []byte) ([]jsonrpcMessage, error) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var batch []jsonrpcMessage
+		if err := json.Unmarshal(trimmed, &batch); err != nil {
+			return nil, err
+		}
+		return batch, nil
+	}
+	var msg jsonrpcMessage
+	if err := json.Unmarshal(trimmed, &msg); err != nil {
+		return nil, err
+	}
+	return []jsonrpcMessage{msg}, nil
+}
+
+// ReadRecords reads a session written by a Recorder.
+// All errors are CRITICAL and indicate the file is missing or malformed.
+func ReadRecords(path string) ([]Record, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open record file: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	records := make([]Record, 0)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRecordLineSize)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("decode record on line %d: %w", line, err)
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read record file: %w", err)
+	}
+	return records, nil
+}
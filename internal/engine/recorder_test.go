@@ -0,0 +1,101 @@
+package engine_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+	"github.com/thep2p/go-eth-localnet/internal/engine"
+	"github.com/thep2p/go-eth-localnet/internal/model"
+	"github.com/thep2p/go-eth-localnet/internal/node"
+	"github.com/thep2p/go-eth-localnet/internal/unittest"
+	"github.com/thep2p/go-eth-localnet/internal/utils"
+)
+
+// TestRecordAndReplaySession verifies a session recorded in front of one node
+// replays into a fresh node without mismatches and leaves it on the same head.
+func TestRecordAndReplaySession(t *testing.T) {
+	ctx, manager := unittest.StartExternalNodes(t, 2)
+	sessionPath := filepath.Join(t.TempDir(), "session.jsonl")
+
+	recorder, err := manager.StartEngineRecorder(0, sessionPath)
+	require.NoError(t, err)
+
+	jwtSecret, err := manager.GetJWTSecret(0)
+	require.NoError(t, err)
+	driver, err := engine.NewDriver(ctx, unittest.Logger(t), recorder.Endpoint(), jwtSecret)
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		_, err := driver.ProduceBlock(ctx, engine.BuildOptions{FeeRecipient: unittest.RandomAddress(t)})
+		require.NoError(t, err)
+	}
+	driver.Close()
+	require.NoError(t, recorder.Close())
+
+	records, err := engine.ReadRecords(sessionPath)
+	require.NoError(t, err)
+	methods := make(map[string]int)
+	for _, rec := range records {
+		methods[rec.Method]++
+		require.Equal(t, 200, rec.HTTPStatus)
+		require.False(t, rec.Time.IsZero())
+		require.Positive(t, rec.Duration)
+		require.NotEmpty(t, rec.Result, "call %s should have a result", rec.Method)
+	}
+	require.Equal(t, 3, methods[engine.MethodGetPayload])
+	require.Equal(t, 3, methods[engine.MethodNewPayload])
+	require.Equal(t, 6, methods[engine.MethodForkchoiceUpdated])
+
+	mismatches, err := manager.ReplayEngineSession(ctx, 1, sessionPath, engine.ReplayOptions{})
+	require.NoError(t, err)
+	require.Empty(t, mismatches)
+
+	head := func(index int) interface{} {
+		client, err := rpc.DialContext(ctx, utils.LocalAddress(manager.GetRPCPort(index)))
+		require.NoError(t, err)
+		defer client.Close()
+		return getBlock(t, ctx, client, model.EthBlockLatest)[model.BlockHash]
+	}
+	require.Equal(t, head(0), head(1))
+	require.Equal(t, driver.Forkchoice().HeadBlockHash.Hex(), head(1))
+}
+
+// TestReplayReportsMismatches verifies replaying a session into a node that has
+// moved on reports the calls whose results changed.
+func TestReplayReportsMismatches(t *testing.T) {
+	ctx, manager := unittest.StartExternalNodes(t, 1)
+	sessionPath := filepath.Join(t.TempDir(), "session.jsonl")
+
+	recorder, err := manager.StartEngineRecorder(0, sessionPath)
+	require.NoError(t, err)
+	jwtSecret, err := manager.GetJWTSecret(0)
+	require.NoError(t, err)
+	driver, err := engine.NewDriver(ctx, unittest.Logger(t), recorder.Endpoint(), jwtSecret)
+	require.NoError(t, err)
+	_, err = driver.ProduceBlock(ctx, engine.BuildOptions{})
+	require.NoError(t, err)
+	driver.Close()
+	require.NoError(t, recorder.Close())
+
+	// The node's head is now the recorded block instead of genesis.
+	mismatches, err := manager.ReplayEngineSession(ctx, 0, sessionPath, engine.ReplayOptions{})
+	require.NoError(t, err)
+	require.NotEmpty(t, mismatches)
+	require.Equal(t, 0, mismatches[0].Index)
+	require.Equal(t, model.EthGetBlockByNumber, mismatches[0].Method)
+}
+
+// TestStartEngineRecorderRequiresEngineAPI verifies a recorder cannot be placed
+// in front of a node without an Engine API.
+func TestStartEngineRecorderRequiresEngineAPI(t *testing.T) {
+	t.Parallel()
+
+	manager := node.NewNodeManager(
+		unittest.Logger(t), node.NewLauncher(unittest.Logger(t)), t.TempDir(), func() int {
+			return unittest.NewPort(t)
+		},
+	)
+	_, err := manager.StartEngineRecorder(0, filepath.Join(t.TempDir(), "session.jsonl"))
+	require.ErrorContains(t, err, "engine api not enabled")
+}
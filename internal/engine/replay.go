@@ -0,0 +1,146 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ReplayOptions controls how a recorded session is fed into a node.
+type ReplayOptions struct {
+	// PreserveTiming waits between calls as long as the gaps in the recording.
+	// By default calls are sent back to back.
+	PreserveTiming bool
+}
+
+// Mismatch is a replayed call whose outcome differs from the recording.
+type Mismatch struct {
+	// Index is the position of the call in the recorded session.
+	Index int
+	// Method is the JSON-RPC method of the call.
+	Method string
+	// Expected is the recorded result, or the recorded error object if the call failed.
+	Expected json.RawMessage
+	// Actual is the replayed result, or the error message if the call failed.
+	Actual json.RawMessage
+}
+
+// Replay sends the calls of a recorded session to a node in order and reports
+// every call whose result or error differs from the recording.
+//
+// Calls the node rejected at the HTTP level (e.g. with 401) are skipped, since
+// they never reached the Engine API. Results are compared as JSON values, so
+// formatting differences are ignored.
+//
+// Args:
+//   - ctx: context for the replayed calls
+//   - client: authenticated Engine API client of a fresh node (see Dial)
+//   - records: the session, e.g. from ReadRecords
+//   - opts: replay options
+//
+// Returns the mismatches in call order. Errors are CRITICAL and indicate the
+// node could not be reached or a record could not be decoded; a call that
+// fails with a JSON-RPC error is compared like any other outcome.
+func Replay(ctx context.Context, client *rpc.Client, records []Record, opts ReplayOptions) ([]Mismatch, error) {
+	mismatches := make([]Mismatch, 0)
+	var previous time.Time
+	for i, rec := range records {
+		if rec.HTTPStatus != 0 && rec.HTTPStatus != http.StatusOK {
+			continue
+		}
+
+		if opts.PreserveTiming && !previous.IsZero() {
+			if gap := rec.Time.Sub(previous); gap > 0 {
+				select {
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				case <-time.After(gap):
+				}
+			}
+		}
+		previous = rec.Time
+
+		params := make([]json.RawMessage, 0)
+		if len(rec.Params) > 0 {
+			if err := json.Unmarshal(rec.Params, &params); err != nil {
+				return nil, fmt.Errorf("decode params of record %d (%s): %w", i, rec.Method, err)
+			}
+		}
+		args := make([]interface{}, len(params))
+		for j, p := range params {
+			args[j] = p
+		}
+
+		var result json.RawMessage
+		callErr := client.CallContext(ctx, &result, rec.Method, args...)
+		var rpcErr rpc.Error
+		if callErr != nil && !errors.As(callErr, &rpcErr) {
+			return nil, fmt.Errorf("replay record %d (%s): %w", i, rec.Method, callErr)
+		}
+
+		if mismatch, ok := compareOutcome(i, rec, result, rpcErr); !ok {
+			mismatches = append(mismatches, mismatch)
+		}
+	}
+	return mismatches, nil
+}
+
+// compareOutcome compares a replayed call with its record. It returns false
+// and the mismatch if they differ.
+func compareOutcome(index int, rec Record, result json.RawMessage, rpcErr rpc.Error) (Mismatch, bool) {
+	mismatch := Mismatch{Index: index, Method: rec.Method}
+
+	if rpcErr != nil || len(rec.Error) > 0 {
+		if rpcErr == nil {
+			mismatch.Expected = rec.Error
+			mismatch.Actual = result
+			return mismatch, false
+		}
+		actual, _ := json.Marshal(rpcErr.Error())
+		var recorded struct {
+			Code int `json:"code"`
+		}
+		if len(rec.Error) == 0 || json.Unmarshal(rec.Error, &recorded) != nil || recorded.Code != rpcErr.ErrorCode() {
+			mismatch.Expected = rec.Result
+			if len(rec.Error) > 0 {
+				mismatch.Expected = rec.Error
+			}
+			mismatch.Actual = actual
+			return mismatch, false
+		}
+		return mismatch, true
+	}
+
+	if !jsonEqual(rec.Result, result) {
+		mismatch.Expected = rec.Result
+		mismatch.Actual = result
+		return mismatch, false
+	}
+	return mismatch, true
+}
+
+// jsonEqual reports whether a and b encode the same JSON value.
+func jsonEqual(a, b json.RawMessage) bool {
+	var va, vb interface{}
+	if err := json.Unmarshal(orNull(a), &va); err != nil {
+		return false
+	}
+	if err := json.Unmarshal(orNull(b), &vb); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// orNull returns raw, or the JSON null literal if raw is empty.
+func orNull(raw json.RawMessage) json.RawMessage {
+	if len(raw) == 0 {
+		return json.RawMessage("null")
+	}
+	return raw
+}
@@ -16,13 +16,17 @@ type Config struct {
 	P2PPort int
 	// RPCPort defines the port for remote procedure calls.
 	RPCPort int
+	// WSPort is the port of the websocket RPC endpoint, which serves
+	// subscriptions such as eth_subscribe. Zero disables the endpoint.
+	WSPort int
 	// PrivateKey is the private key used for signing transactions and messages.
 	PrivateKey *ecdsa.PrivateKey
 
 	StaticNodes []string // enode URLs of peers
 
 	// Mine determines whether this node should produce blocks using the
-	// simulated beacon. Only one node in the network may enable mining.
+	// simulated beacon. Only one node in the network may enable mining;
+	// node.Manager rejects a second miner.
 	Mine bool
 
 	// Engine API configuration for EL-CL communication.
@@ -39,4 +43,10 @@ type Config struct {
 	// The file should contain a 32-byte hex-encoded secret.
 	// Only used when EnableEngineAPI is true.
 	JWTSecretPath string
+
+	// ExternalConsensus determines whether the node is driven by an external
+	// consensus client instead of a simulated beacon. When true, Mine is ignored
+	// and the node only advances when forkchoice updates arrive over the Engine
+	// API, so EnableEngineAPI must be true.
+	ExternalConsensus bool
 }
@@ -29,9 +29,18 @@ const (
 	// CallContextTo represents the "to" field in the call context, typically specifying the recipient address of the call.
 	CallContextTo = "to"
 
+	// CallContextFrom represents the "from" field in the call context, specifying the sender address of the call.
+	CallContextFrom = "from"
+
+	// CallContextValue represents the "value" field in the call context, specifying the wei sent with the call.
+	CallContextValue = "value"
+
 	// CallContextData is a constant representing the "data" field in Ethereum call context requests.
 	CallContextData = "data"
 
+	// CallContextGas represents the "gas" field in the call context, capping the gas the call may use.
+	CallContextGas = "gas"
+
 	// ReceiptContractAddress represents the key for accessing the contract address from a transaction receipt.
 	ReceiptContractAddress = "contractAddress"
 
@@ -55,9 +64,33 @@ const (
 
 	// BlockDifficulty represents the difficulty field in a blockchain block.
 	BlockDifficulty = "difficulty"
+
+	// BlockBaseFeePerGas represents the EIP-1559 base fee field in a blockchain block.
+	BlockBaseFeePerGas = "baseFeePerGas"
+
+	// BlockNumber represents the number field in a blockchain block.
+	BlockNumber = "number"
+
+	// BlockHash represents the hash field in a blockchain block.
+	BlockHash = "hash"
+
+	// BlockTimestamp represents the timestamp field in a blockchain block.
+	BlockTimestamp = "timestamp"
 	// Ethereum-related method constants
 	// EthLatestBlock represents the latest block identifier in Ethereum.
 	EthBlockLatest = "latest"
+	// EthBlockPending represents the pending block identifier in Ethereum, which
+	// includes transactions in the transaction pool.
+	EthBlockPending = "pending"
+	// EthBlockSafe represents the safe block identifier in Ethereum: the head
+	// the consensus layer considers unlikely to be reorged (justified).
+	EthBlockSafe = "safe"
+	// EthBlockFinalized represents the finalized block identifier in Ethereum.
+	EthBlockFinalized = "finalized"
+	// EthBlockEarliest represents the genesis block identifier in Ethereum.
+	EthBlockEarliest = "earliest"
+	// EthChainID represents the method for retrieving the chain ID.
+	EthChainID = "eth_chainId"
 	// EthBlockNumber represents the method for retrieving the current block number.
 	EthBlockNumber = "eth_blockNumber"
 
@@ -70,9 +103,38 @@ const (
 	// EthGetTransactionReceipt represents the method for retrieving a transaction receipt.
 	EthGetTransactionReceipt = "eth_getTransactionReceipt"
 
+	// EthGetTransactionByHash represents the method for retrieving a transaction,
+	// including pending transactions in the node's pool, by its hash.
+	EthGetTransactionByHash = "eth_getTransactionByHash"
+
+	// EthEstimateGas represents the method for estimating the gas a transaction needs.
+	EthEstimateGas = "eth_estimateGas"
+
+	// EthMaxPriorityFeePerGas represents the method for retrieving a suggested priority fee (tip).
+	EthMaxPriorityFeePerGas = "eth_maxPriorityFeePerGas"
+
 	// EthSendRawTransaction represents the method for sending a raw transaction to the network.
 	EthSendRawTransaction = "eth_sendRawTransaction"
 
+	// EthGetLogs represents the method for retrieving the logs matching a filter.
+	EthGetLogs = "eth_getLogs"
+
+	// EthSubscriptionLogs represents the eth_subscribe subscription streaming
+	// the logs matching a filter as they are mined.
+	EthSubscriptionLogs = "logs"
+
+	// LogFilterAddress is the key of the contract addresses in a log filter.
+	LogFilterAddress = "address"
+
+	// LogFilterTopics is the key of the topics in a log filter.
+	LogFilterTopics = "topics"
+
+	// LogFilterFromBlock is the key of the first block in a log filter.
+	LogFilterFromBlock = "fromBlock"
+
+	// LogFilterToBlock is the key of the last block in a log filter.
+	LogFilterToBlock = "toBlock"
+
 	// EthWeb3ClientVersion represents the method for retrieving the client version.
 	EthWeb3ClientVersion = "web3_clientVersion"
 
@@ -0,0 +1,318 @@
+// Package netem shapes TCP traffic between in-process nodes to emulate wide
+// area network conditions without OS-level tooling.
+package netem
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// DefaultRetransmitTimeout is the delay added to a lost chunk when
+	// Conditions.RetransmitTimeout is zero. It matches the minimum TCP
+	// retransmission timeout of Linux.
+	DefaultRetransmitTimeout = 200 * time.Millisecond
+	// chunkSize is the largest amount of data read and shaped at once.
+	chunkSize = 16 * 1024
+	// chunkQueueSize bounds the chunks in flight per direction of a connection.
+	chunkQueueSize = 1024
+)
+
+// Conditions describe the network conditions applied to each direction of a
+// relayed connection. The zero value forwards traffic unchanged.
+type Conditions struct {
+	// Latency is the one-way delay added to every chunk of data.
+	Latency time.Duration
+	// Jitter is the maximum random deviation from Latency, drawn uniformly
+	// from [-Jitter, +Jitter]. Data is never reordered, so a chunk is never
+	// delivered before the chunk preceding it.
+	Jitter time.Duration
+	// Bandwidth caps the throughput of each direction in bytes per second.
+	// Zero means unlimited.
+	Bandwidth int
+	// LossRate is the probability in [0, 1) that a chunk is lost. TCP hides
+	// loss from the application, so a lost chunk is delivered after an extra
+	// RetransmitTimeout rather than dropped, stalling the chunks behind it as
+	// a retransmission would.
+	LossRate float64
+	// RetransmitTimeout is the extra delay of a lost chunk. Zero means
+	// DefaultRetransmitTimeout.
+	RetransmitTimeout time.Duration
+}
+
+// Validate returns an error if any of the conditions is out of range.
+func (c Conditions) Validate() error {
+	if c.Latency < 0 || c.Jitter < 0 || c.RetransmitTimeout < 0 {
+		return fmt.Errorf("latency, jitter and retransmit timeout must not be negative")
+	}
+	if c.Jitter > c.Latency {
+		return fmt.Errorf("jitter %s must not exceed latency %s", c.Jitter, c.Latency)
+	}
+	if c.Bandwidth < 0 {
+		return fmt.Errorf("bandwidth must not be negative, got %d", c.Bandwidth)
+	}
+	if c.LossRate < 0 || c.LossRate >= 1 {
+		return fmt.Errorf("loss rate must be in [0, 1), got %v", c.LossRate)
+	}
+	return nil
+}
+
+// delay returns the time a chunk of data spends in flight, excluding transmission.
+func (c Conditions) delay() time.Duration {
+	d := c.Latency
+	if c.Jitter > 0 {
+		d += time.Duration(rand.Int64N(int64(2*c.Jitter)+1)) - c.Jitter
+	}
+	if c.LossRate > 0 && rand.Float64() < c.LossRate {
+		if c.RetransmitTimeout > 0 {
+			d += c.RetransmitTimeout
+		} else {
+			d += DefaultRetransmitTimeout
+		}
+	}
+	return d
+}
+
+// transmission returns the time needed to send n bytes at the bandwidth cap.
+func (c Conditions) transmission(n int) time.Duration {
+	if c.Bandwidth == 0 {
+		return 0
+	}
+	return time.Duration(int64(n) * int64(time.Second) / int64(c.Bandwidth))
+}
+
+// Relay is a TCP proxy that forwards every accepted connection to an upstream
+// address and applies Conditions to both directions of the traffic.
+//
+// Conditions may be changed while connections are open; they apply to data
+// read after the change.
+type Relay struct {
+	logger   zerolog.Logger
+	upstream string
+	listener net.Listener
+
+	mu         sync.RWMutex
+	conditions Conditions
+	conns      map[net.Conn]struct{}
+	closed     bool
+	wg         sync.WaitGroup
+}
+
+// NewRelay starts a Relay listening on listenAddr and forwarding to upstream.
+//
+// Args:
+//   - logger: logger for relay errors
+//   - listenAddr: address peers connect to, e.g. 127.0.0.1:0 for a free port
+//   - upstream: address connections are forwarded to, e.g. 127.0.0.1:30303
+//   - conditions: network conditions applied to the relayed traffic
+//
+// Returns the running Relay. The caller closes it to drop relayed connections.
+// All errors are CRITICAL and indicate invalid conditions or that the relay
+// could not be started.
+func NewRelay(logger zerolog.Logger, listenAddr string, upstream string, conditions Conditions) (*Relay, error) {
+	if err := conditions.Validate(); err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		return nil, fmt.Errorf("listen on %s: %w", listenAddr, err)
+	}
+
+	r := &Relay{
+		logger:     logger.With().Str("component", "netem-relay").Str("upstream", upstream).Logger(),
+		upstream:   upstream,
+		listener:   listener,
+		conditions: conditions,
+		conns:      make(map[net.Conn]struct{}),
+	}
+	r.wg.Add(1)
+	go r.acceptLoop()
+	return r, nil
+}
+
+// Addr returns the address peers connect to instead of upstream.
+func (r *Relay) Addr() *net.TCPAddr {
+	return r.listener.Addr().(*net.TCPAddr)
+}
+
+// Conditions returns the network conditions currently applied.
+func (r *Relay) Conditions() Conditions {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.conditions
+}
+
+// SetConditions replaces the network conditions applied to relayed traffic.
+// Returns an error if the conditions are invalid.
+func (r *Relay) SetConditions(conditions Conditions) error {
+	if err := conditions.Validate(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.conditions = conditions
+	r.mu.Unlock()
+	return nil
+}
+
+// Close stops accepting connections, closes the relayed connections and waits
+// for their forwarding goroutines to return.
+func (r *Relay) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	err := r.listener.Close()
+	for conn := range r.conns {
+		_ = conn.Close()
+	}
+	r.mu.Unlock()
+
+	r.wg.Wait()
+	if err != nil {
+		return fmt.Errorf("close relay listener: %w", err)
+	}
+	return nil
+}
+
+// acceptLoop relays connections until the listener is closed.
+func (r *Relay) acceptLoop() {
+	defer r.wg.Done()
+	for {
+		conn, err := r.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				r.logger.Error().Err(err).Msg("accept relay connection")
+			}
+			return
+		}
+		r.wg.Add(1)
+		go r.relay(conn)
+	}
+}
+
+// relay forwards a downstream connection to upstream until either side closes it.
+func (r *Relay) relay(downstream net.Conn) {
+	defer r.wg.Done()
+
+	upstream, err := net.Dial("tcp", r.upstream)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("dial relay upstream")
+		_ = downstream.Close()
+		return
+	}
+	if !r.track(downstream, upstream) {
+		return
+	}
+	defer r.untrack(downstream, upstream)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		r.pipe(upstream, downstream)
+	}()
+	go func() {
+		defer wg.Done()
+		r.pipe(downstream, upstream)
+	}()
+	wg.Wait()
+}
+
+// track registers the connections of a relayed pair so Close can drop them.
+// Returns false, after closing both, if the relay is already closed.
+func (r *Relay) track(conns ...net.Conn) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		for _, conn := range conns {
+			_ = conn.Close()
+		}
+		return false
+	}
+	for _, conn := range conns {
+		r.conns[conn] = struct{}{}
+	}
+	return true
+}
+
+// untrack closes and unregisters the connections of a relayed pair.
+func (r *Relay) untrack(conns ...net.Conn) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, conn := range conns {
+		_ = conn.Close()
+		delete(r.conns, conn)
+	}
+}
+
+// chunk is data read from one side of a relayed pair, due at the other side at deliverAt.
+type chunk struct {
+	data      []byte
+	deliverAt time.Time
+}
+
+// pipe shapes data read from src and writes it to dst. When either side fails,
+// both are closed so the opposite direction stops as well.
+func (r *Relay) pipe(dst, src net.Conn) {
+	queue := make(chan chunk, chunkQueueSize)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		for c := range queue {
+			time.Sleep(time.Until(c.deliverAt))
+			if _, err := dst.Write(c.data); err != nil {
+				_ = src.Close()
+				// Drain so the reader is never blocked on a full queue.
+				for range queue {
+				}
+				return
+			}
+		}
+	}()
+
+	// linkFree is when the link finishes transmitting the previous chunk and
+	// last is when that chunk is delivered; chunks are delivered in order.
+	var linkFree, last time.Time
+	buf := make([]byte, chunkSize)
+	for {
+		n, err := src.Read(buf)
+		if n > 0 {
+			conditions := r.Conditions()
+			now := time.Now()
+			start := now
+			if linkFree.After(start) {
+				start = linkFree
+			}
+			linkFree = start.Add(conditions.transmission(n))
+			deliverAt := linkFree.Add(conditions.delay())
+			if deliverAt.Before(last) {
+				deliverAt = last
+			}
+			last = deliverAt
+
+			data := make([]byte, n)
+			copy(data, buf[:n])
+			queue <- chunk{data: data, deliverAt: deliverAt}
+		}
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				r.logger.Debug().Err(err).Msg("relay connection closed")
+			}
+			break
+		}
+	}
+	close(queue)
+	<-done
+	_ = dst.Close()
+}
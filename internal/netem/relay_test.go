@@ -0,0 +1,159 @@
+package netem_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/thep2p/go-eth-localnet/internal/netem"
+	"github.com/thep2p/go-eth-localnet/internal/unittest"
+)
+
+// startEchoServer starts a TCP server that writes back everything it reads and returns its address.
+func startEchoServer(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() {
+					_ = conn.Close()
+				}()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+// startRelay starts a relay in front of an echo server and returns a connection through it.
+func startRelay(t *testing.T, conditions netem.Conditions) (*netem.Relay, net.Conn) {
+	t.Helper()
+
+	relay, err := netem.NewRelay(
+		unittest.Logger(t), fmt.Sprintf("127.0.0.1:%d", unittest.NewPort(t)), startEchoServer(t), conditions,
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, relay.Close())
+	})
+
+	conn, err := net.Dial("tcp", relay.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return relay, conn
+}
+
+// roundTrip writes data through conn, reads the echo back and returns the elapsed time.
+func roundTrip(t *testing.T, conn net.Conn, data []byte) time.Duration {
+	t.Helper()
+
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := conn.Write(data)
+		errCh <- err
+	}()
+
+	echo := make([]byte, len(data))
+	_, err := io.ReadFull(conn, echo)
+	require.NoError(t, err)
+	require.NoError(t, <-errCh)
+	require.True(t, bytes.Equal(data, echo), "echoed data should match")
+	return time.Since(start)
+}
+
+// TestRelayForwardsUnchanged verifies the zero conditions forward data unchanged and without delay.
+func TestRelayForwardsUnchanged(t *testing.T) {
+	_, conn := startRelay(t, netem.Conditions{})
+
+	data := make([]byte, 256*1024)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+	require.Less(t, roundTrip(t, conn, data), time.Second)
+}
+
+// TestRelayLatency verifies latency is added to each direction and can be
+// changed on an open connection.
+func TestRelayLatency(t *testing.T) {
+	relay, conn := startRelay(t, netem.Conditions{Latency: 100 * time.Millisecond, Jitter: 20 * time.Millisecond})
+
+	elapsed := roundTrip(t, conn, []byte("ping"))
+	require.GreaterOrEqual(t, elapsed, 160*time.Millisecond, "both directions should be delayed")
+	require.Less(t, elapsed, time.Second)
+
+	require.NoError(t, relay.SetConditions(netem.Conditions{}))
+	require.Less(t, roundTrip(t, conn, []byte("ping")), 100*time.Millisecond)
+}
+
+// TestRelayBandwidth verifies the throughput of each direction is capped.
+func TestRelayBandwidth(t *testing.T) {
+	_, conn := startRelay(t, netem.Conditions{Bandwidth: 256 * 1024})
+
+	// 128 KiB at 256 KiB/s takes half a second per direction, and the echo
+	// overlaps with the upload, so the round trip takes at least that long.
+	elapsed := roundTrip(t, conn, make([]byte, 128*1024))
+	require.GreaterOrEqual(t, elapsed, 450*time.Millisecond)
+	require.Less(t, elapsed, 3*time.Second)
+}
+
+// TestRelayLoss verifies lost chunks are delayed by the retransmission timeout.
+func TestRelayLoss(t *testing.T) {
+	_, conn := startRelay(t, netem.Conditions{LossRate: 0.999999, RetransmitTimeout: 150 * time.Millisecond})
+
+	elapsed := roundTrip(t, conn, []byte("ping"))
+	require.GreaterOrEqual(t, elapsed, 300*time.Millisecond, "the chunk should be retransmitted in both directions")
+	require.Less(t, elapsed, time.Second)
+}
+
+// TestRelayClose verifies closing the relay drops relayed connections and stops accepting new ones.
+func TestRelayClose(t *testing.T) {
+	relay, conn := startRelay(t, netem.Conditions{})
+	roundTrip(t, conn, []byte("ping"))
+
+	require.NoError(t, relay.Close())
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	_, err := conn.Read(make([]byte, 1))
+	require.True(t, errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) || isReset(err), "unexpected error %v", err)
+
+	_, err = net.DialTimeout("tcp", relay.Addr().String(), time.Second)
+	require.Error(t, err)
+	require.NoError(t, relay.Close(), "closing twice should be a no-op")
+}
+
+// isReset reports whether err is a connection reset by the peer.
+func isReset(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && !opErr.Timeout()
+}
+
+// TestConditionsValidate verifies out-of-range conditions are rejected.
+func TestConditionsValidate(t *testing.T) {
+	require.NoError(t, netem.Conditions{Latency: time.Second, Jitter: time.Second, Bandwidth: 1, LossRate: 0.5}.Validate())
+
+	require.ErrorContains(t, netem.Conditions{Latency: -time.Second}.Validate(), "must not be negative")
+	require.ErrorContains(t, netem.Conditions{Latency: time.Millisecond, Jitter: time.Second}.Validate(), "must not exceed latency")
+	require.ErrorContains(t, netem.Conditions{Bandwidth: -1}.Validate(), "bandwidth")
+	require.ErrorContains(t, netem.Conditions{LossRate: 1}.Validate(), "loss rate")
+
+	_, err := netem.NewRelay(unittest.Logger(t), "127.0.0.1:0", "127.0.0.1:1", netem.Conditions{LossRate: -0.1})
+	require.ErrorContains(t, err, "loss rate")
+}
@@ -0,0 +1,156 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/rs/zerolog"
+	"github.com/thep2p/go-eth-localnet/internal/contracts"
+	"github.com/thep2p/go-eth-localnet/internal/model"
+)
+
+// eventBufferSize is the number of logs buffered between the websocket
+// connection and the decoder of an EventSubscription.
+const eventBufferSize = 64
+
+// EventSubscription streams contract events decoded from the logs a node
+// pushes over its websocket endpoint (see Manager.SubscribeEvents).
+type EventSubscription struct {
+	logger  zerolog.Logger
+	client  *rpc.Client
+	sub     *rpc.ClientSubscription
+	decoder *contracts.EventDecoder
+	events  chan *contracts.Event
+	err     chan error
+	stop    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+}
+
+// SubscribeEvents subscribes to the logs matching filter on the node at the
+// given index and streams them decoded with decoder, in the order they are
+// mined. Logs of blocks dropped by a reorg are streamed again with
+// Event.Log.Removed set. The block range of filter is ignored: only logs
+// mined after the call are streamed; use EventDecoder.FilterLogs for past ones.
+//
+// The subscription ends when Unsubscribe is called, ctx is done, or it
+// fails, e.g. because the node stopped.
+//
+// Args:
+//   - ctx: bounds the subscription
+//   - index: the node to subscribe to
+//   - decoder: decodes the logs, e.g. BoundContract.Events
+//   - filter: the contract addresses and events to stream
+//
+// Returns the subscription. All errors are CRITICAL and indicate the node
+// is not running, the filter is invalid, or the websocket connection failed.
+func (m *Manager) SubscribeEvents(
+	ctx context.Context,
+	index int,
+	decoder *contracts.EventDecoder,
+	filter contracts.LogFilter,
+) (*EventSubscription, error) {
+	if _, err := m.runningNode(index); err != nil {
+		return nil, err
+	}
+	endpoint := m.GetWSEndpoint(index)
+	if endpoint == "" {
+		return nil, fmt.Errorf("node %d has no websocket endpoint", index)
+	}
+
+	filter.FromBlock, filter.ToBlock = nil, nil
+	arg, err := decoder.FilterArg(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := rpc.DialWebsocket(ctx, endpoint, "")
+	if err != nil {
+		return nil, fmt.Errorf("dial websocket of node %d: %w", index, err)
+	}
+	logs := make(chan types.Log, eventBufferSize)
+	sub, err := client.EthSubscribe(ctx, logs, model.EthSubscriptionLogs, arg)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("subscribe to logs of node %d: %w", index, err)
+	}
+
+	s := &EventSubscription{
+		logger:  m.logger.With().Str("component", "event-subscription").Int("node_index", index).Logger(),
+		client:  client,
+		sub:     sub,
+		decoder: decoder,
+		events:  make(chan *contracts.Event),
+		err:     make(chan error, 1),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go s.run(ctx, logs)
+	return s, nil
+}
+
+// Events returns the channel of decoded events. It is closed when the subscription ends.
+func (s *EventSubscription) Events() <-chan *contracts.Event {
+	return s.events
+}
+
+// Err returns a channel that receives the error ending the subscription, if
+// any, e.g. a dropped connection or a log that cannot be decoded. It is not
+// sent to when the subscription ends through Unsubscribe or ctx.
+func (s *EventSubscription) Err() <-chan error {
+	return s.err
+}
+
+// Unsubscribe ends the subscription and closes its websocket connection.
+// It blocks until Events is closed and is safe to call more than once.
+func (s *EventSubscription) Unsubscribe() {
+	s.once.Do(func() {
+		close(s.stop)
+	})
+	<-s.stopped
+}
+
+// run decodes logs and forwards them to Events until the subscription ends.
+func (s *EventSubscription) run(ctx context.Context, logs <-chan types.Log) {
+	defer close(s.stopped)
+	defer close(s.events)
+	defer s.client.Close()
+	defer s.sub.Unsubscribe()
+
+	for {
+		select {
+		case log := <-logs:
+			event, err := s.decoder.Decode(log)
+			if errors.Is(err, contracts.ErrUnknownEvent) {
+				// An ABI without events filters no topics, so any log of
+				// the addresses arrives; logs of unknown events are skipped.
+				continue
+			}
+			if err != nil {
+				s.err <- fmt.Errorf("decode log %d of tx %s: %w", log.Index, log.TxHash.Hex(), err)
+				return
+			}
+			select {
+			case s.events <- event:
+			case <-s.stop:
+				return
+			case <-ctx.Done():
+				return
+			}
+		case err := <-s.sub.Err():
+			if err != nil {
+				s.logger.Warn().Err(err).Msg("event subscription failed")
+				s.err <- fmt.Errorf("log subscription: %w", err)
+			}
+			return
+		case <-s.stop:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
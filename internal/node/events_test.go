@@ -0,0 +1,84 @@
+package node_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+	"github.com/thep2p/go-eth-localnet/internal/contracts"
+	"github.com/thep2p/go-eth-localnet/internal/contracts/bindings/storage"
+	"github.com/thep2p/go-eth-localnet/internal/node"
+	"github.com/thep2p/go-eth-localnet/internal/unittest"
+	"github.com/thep2p/go-eth-localnet/internal/utils"
+)
+
+// TestSubscribeEvents tests that events are streamed decoded, in order, over the websocket endpoint.
+func TestSubscribeEvents(t *testing.T) {
+	unittest.RequireSolc(t)
+
+	key := unittest.PrivateKeyFixture(t)
+	oneEth := big.NewInt(params.Ether)
+	ctx, cancel, manager := startNodes(t, 1, node.WithPreFundGenesisAccount(crypto.PubkeyToAddress(key.PublicKey), oneEth))
+	defer cancel()
+
+	client, err := rpc.DialContext(ctx, utils.LocalAddress(manager.RPCPort()))
+	require.NoError(t, err)
+	defer client.Close()
+
+	// cf. internal/contracts/SimpleStorageContract.sol
+	artifacts, err := contracts.Compile(contracts.CompileOptions{}, "../contracts/SimpleStorageContract.sol")
+	require.NoError(t, err)
+	artifact, err := artifacts.Contract("Storage")
+	require.NoError(t, err)
+	s, err := contracts.Deploy(ctx, client, key, artifact)
+	require.NoError(t, err)
+
+	sub, err := manager.SubscribeEvents(ctx, 0, s.Events(), contracts.LogFilter{Addresses: []common.Address{s.Address()}})
+	require.NoError(t, err)
+
+	for _, v := range []int64{7, 8} {
+		_, err := s.Transact(ctx, "set", big.NewInt(v))
+		require.NoError(t, err)
+	}
+
+	for _, expected := range []int64{7, 8} {
+		select {
+		case event := <-sub.Events():
+			require.Equal(t, "ValueChanged", event.Name)
+			require.False(t, event.Log.Removed)
+			var changed storage.StorageValueChanged
+			require.NoError(t, event.Unpack(&changed))
+			require.Equal(t, expected, changed.NewValue.Int64())
+		case err := <-sub.Err():
+			require.NoError(t, err)
+		case <-time.After(node.OperationTimeout):
+			require.Fail(t, "event not received")
+		}
+	}
+
+	sub.Unsubscribe()
+	_, open := <-sub.Events()
+	require.False(t, open, "events should be closed after unsubscribing")
+	sub.Unsubscribe()
+}
+
+// TestSubscribeEvents_Errors tests that subscriptions to missing nodes or events are rejected.
+func TestSubscribeEvents_Errors(t *testing.T) {
+	ctx, cancel, manager := startNodes(t, 1)
+	defer cancel()
+	decoder, err := contracts.NewEventDecoder(&contracts.Artifact{Name: "Storage", ABI: storage.StorageMetaData.ABI})
+	require.NoError(t, err)
+
+	_, err = manager.SubscribeEvents(ctx, 1, decoder, contracts.LogFilter{})
+	require.ErrorContains(t, err, "out of range")
+	_, err = manager.SubscribeEvents(ctx, 0, decoder, contracts.LogFilter{Events: []string{"Missing"}})
+	require.ErrorContains(t, err, `event "Missing" not found`)
+
+	require.NotEmpty(t, manager.GetWSEndpoint(0))
+	require.Empty(t, manager.GetWSEndpoint(1))
+}
@@ -0,0 +1,232 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/thep2p/go-eth-localnet/internal/consensus"
+	"github.com/thep2p/go-eth-localnet/internal/model"
+	"github.com/thep2p/go-eth-localnet/internal/utils"
+)
+
+// BlocksPerEpoch is the number of blocks per epoch of the simulated beacon.
+// It finalizes the block at every multiple of BlocksPerEpoch, matching the
+// slots per epoch of a beacon chain with one block per slot.
+const BlocksPerEpoch = 32
+
+// forkchoicePollInterval is the delay between forkchoice progress checks.
+const forkchoicePollInterval = 250 * time.Millisecond
+
+const (
+	// LayerExecution identifies a NodeLag of the EL node.
+	LayerExecution = "el"
+	// LayerConsensus identifies a NodeLag of the paired beacon node.
+	LayerConsensus = "cl"
+)
+
+// NodeLag describes a node that has not reached a forkchoice target.
+type NodeLag struct {
+	// Index is the node index in the Manager.
+	Index int
+	// Layer is LayerExecution or LayerConsensus.
+	Layer string
+	// Have is the block number (EL) or epoch (CL) the node has reached.
+	Have uint64
+	// Want is the block number (EL) or epoch (CL) waited for.
+	Want uint64
+	// Err is set if the node could not be queried; Have is then meaningless.
+	Err error
+}
+
+// describe describes the lag, e.g. "node 1 el finalized block 12 < 64".
+func (l NodeLag) describe(label string) string {
+	unit := "block"
+	if l.Layer == LayerConsensus {
+		unit = "epoch"
+	}
+	if l.Err != nil {
+		return fmt.Sprintf("node %d %s %s %s unavailable: %v", l.Index, l.Layer, label, unit, l.Err)
+	}
+	return fmt.Sprintf("node %d %s %s %s %d < %d", l.Index, l.Layer, label, unit, l.Have, l.Want)
+}
+
+// BehindError is returned by WaitForFinality and WaitForSafeHead when some nodes
+// have not reached the target before the context is done.
+type BehindError struct {
+	// Label is the forkchoice label waited for: "finalized" or "safe".
+	Label string
+	// Nodes lists every node and layer that was behind at the last check.
+	Nodes []NodeLag
+	// Cause is the context error that stopped the wait.
+	Cause error
+}
+
+// Error lists the lagging nodes.
+func (e *BehindError) Error() string {
+	lags := make([]string, len(e.Nodes))
+	for i, lag := range e.Nodes {
+		lags[i] = lag.describe(e.Label)
+	}
+	return fmt.Sprintf("%s head not reached: %v: %s", e.Label, e.Cause, strings.Join(lags, "; "))
+}
+
+// Unwrap returns the context error that stopped the wait.
+func (e *BehindError) Unwrap() error {
+	return e.Cause
+}
+
+// WaitForFinality blocks until every node has finalized the given number of epochs.
+//
+// A node has finalized once eth_getBlockByNumber("finalized") on its EL is at
+// least epochs*BlocksPerEpoch and, if it is paired with a beacon node (see
+// SetBeaconEndpoint), the beacon node's finalized checkpoint epoch is at least epochs.
+//
+// Args:
+//   - ctx: bounds the wait; use a deadline of at least epochs*BlocksPerEpoch block times
+//   - epochs: number of epochs that must be finalized
+//
+// Returns nil once all nodes have finalized, or a *BehindError describing which
+// nodes are behind when ctx is done.
+func (m *Manager) WaitForFinality(ctx context.Context, epochs uint64) error {
+	return m.waitForForkchoice(
+		ctx, model.EthBlockFinalized, epochs*BlocksPerEpoch, epochs,
+		func(c *consensus.BeaconFinalityCheckpoints) uint64 { return c.Finalized.Epoch },
+	)
+}
+
+// WaitForSafeHead blocks until every node's safe head has reached the given block.
+//
+// A node's safe head has reached blockNumber once eth_getBlockByNumber("safe") on
+// its EL is at least blockNumber and, if it is paired with a beacon node, the
+// beacon node's current justified checkpoint covers blockNumber.
+//
+// Args:
+//   - ctx: bounds the wait
+//   - blockNumber: EL block number the safe head must reach
+//
+// Returns nil once all safe heads have reached blockNumber, or a *BehindError
+// describing which nodes are behind when ctx is done.
+func (m *Manager) WaitForSafeHead(ctx context.Context, blockNumber uint64) error {
+	// The justified checkpoint of epoch e is the block at e*BlocksPerEpoch, so
+	// blockNumber is justified from the first epoch starting at or after it.
+	epoch := (blockNumber + BlocksPerEpoch - 1) / BlocksPerEpoch
+	return m.waitForForkchoice(
+		ctx, model.EthBlockSafe, blockNumber, epoch,
+		func(c *consensus.BeaconFinalityCheckpoints) uint64 { return c.CurrentJustified.Epoch },
+	)
+}
+
+// forkchoiceTarget is a node checked by waitForForkchoice.
+type forkchoiceTarget struct {
+	index          int
+	client         *rpc.Client
+	beaconEndpoint string
+}
+
+// waitForForkchoice polls every node until its EL block at label reaches
+// wantBlock and its paired beacon node's clEpoch reaches wantEpoch.
+func (m *Manager) waitForForkchoice(
+	ctx context.Context,
+	label string,
+	wantBlock uint64,
+	wantEpoch uint64,
+	clEpoch func(*consensus.BeaconFinalityCheckpoints) uint64,
+) error {
+	m.mu.RLock()
+	targets := make([]forkchoiceTarget, len(m.configs))
+	for i, cfg := range m.configs {
+		targets[i] = forkchoiceTarget{index: i, beaconEndpoint: m.beaconEndpoints[i]}
+		client, err := rpc.DialContext(ctx, utils.LocalAddress(cfg.RPCPort))
+		if err != nil {
+			m.mu.RUnlock()
+			closeTargets(targets)
+			return fmt.Errorf("dial node %d: %w", i, err)
+		}
+		targets[i].client = client
+	}
+	m.mu.RUnlock()
+	defer closeTargets(targets)
+
+	if len(targets) == 0 {
+		return fmt.Errorf("no nodes started")
+	}
+
+	for {
+		var lagging []NodeLag
+		for _, target := range targets {
+			lagging = append(lagging, checkForkchoice(ctx, target, label, wantBlock, wantEpoch, clEpoch)...)
+		}
+		if len(lagging) == 0 {
+			m.logger.Info().Str("label", label).Uint64("block", wantBlock).Msg("forkchoice target reached by all nodes")
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return &BehindError{Label: label, Nodes: lagging, Cause: ctx.Err()}
+		case <-time.After(forkchoicePollInterval):
+		}
+	}
+}
+
+// checkForkchoice returns the layers of target that have not reached wantBlock (EL) or wantEpoch (CL).
+func checkForkchoice(
+	ctx context.Context,
+	target forkchoiceTarget,
+	label string,
+	wantBlock uint64,
+	wantEpoch uint64,
+	clEpoch func(*consensus.BeaconFinalityCheckpoints) uint64,
+) []NodeLag {
+	var lagging []NodeLag
+
+	number, err := labeledBlockNumber(ctx, target.client, label)
+	if err != nil || number < wantBlock {
+		lagging = append(lagging, NodeLag{Index: target.index, Layer: LayerExecution, Have: number, Want: wantBlock, Err: err})
+	}
+
+	if target.beaconEndpoint != "" {
+		checkpoints, err := consensus.GetBeaconFinalityCheckpoints(ctx, target.beaconEndpoint)
+		var epoch uint64
+		if err == nil {
+			epoch = clEpoch(checkpoints)
+		}
+		if err != nil || epoch < wantEpoch {
+			lagging = append(lagging, NodeLag{Index: target.index, Layer: LayerConsensus, Have: epoch, Want: wantEpoch, Err: err})
+		}
+	}
+
+	return lagging
+}
+
+// labeledBlockNumber returns the number of the block at a forkchoice label such as "finalized".
+func labeledBlockNumber(ctx context.Context, client *rpc.Client, label string) (uint64, error) {
+	ctx, cancel := context.WithTimeout(ctx, OperationTimeout)
+	defer cancel()
+
+	var block map[string]interface{}
+	if err := client.CallContext(ctx, &block, model.EthGetBlockByNumber, label, false); err != nil {
+		return 0, err
+	}
+	if block == nil {
+		return 0, fmt.Errorf("no %s block", label)
+	}
+	numberHex, ok := block[model.BlockNumber].(string)
+	if !ok {
+		return 0, fmt.Errorf("block has no number")
+	}
+	return hexutil.DecodeUint64(numberHex)
+}
+
+// closeTargets closes the RPC clients of targets.
+func closeTargets(targets []forkchoiceTarget) {
+	for _, target := range targets {
+		if target.client != nil {
+			target.client.Close()
+		}
+	}
+}
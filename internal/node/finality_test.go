@@ -0,0 +1,106 @@
+package node_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+	"github.com/thep2p/go-eth-localnet/internal/model"
+	"github.com/thep2p/go-eth-localnet/internal/node"
+	"github.com/thep2p/go-eth-localnet/internal/unittest"
+	"github.com/thep2p/go-eth-localnet/internal/utils"
+)
+
+// TestWaitForSafeHead verifies the safe head of a block-producing node advances.
+func TestWaitForSafeHead(t *testing.T) {
+	ctx, cancel, manager := startNodes(t, 1)
+	defer cancel()
+
+	waitCtx, waitCancel := context.WithTimeout(ctx, 3*node.OperationTimeout)
+	defer waitCancel()
+	require.NoError(t, manager.WaitForSafeHead(waitCtx, 3))
+
+	client, err := rpc.DialContext(ctx, utils.LocalAddress(manager.RPCPort()))
+	require.NoError(t, err)
+	defer client.Close()
+
+	var safe map[string]interface{}
+	require.NoError(t, client.CallContext(ctx, &safe, model.EthGetBlockByNumber, model.EthBlockSafe, false))
+	require.GreaterOrEqual(t, unittest.HexToBigInt(t, safe[model.BlockNumber].(string)).Uint64(), uint64(3))
+}
+
+// TestWaitForFinality verifies a block-producing node finalizes its first epoch.
+//
+// The simulated beacon produces one block per second and finalizes every
+// node.BlocksPerEpoch blocks, so this takes a little over 30 seconds.
+func TestWaitForFinality(t *testing.T) {
+	ctx, cancel, manager := startNodes(t, 1)
+	defer cancel()
+
+	waitCtx, waitCancel := context.WithTimeout(ctx, 2*node.BlocksPerEpoch*time.Second)
+	defer waitCancel()
+	require.NoError(t, manager.WaitForFinality(waitCtx, 1))
+
+	client, err := rpc.DialContext(ctx, utils.LocalAddress(manager.RPCPort()))
+	require.NoError(t, err)
+	defer client.Close()
+
+	var finalized map[string]interface{}
+	require.NoError(t, client.CallContext(ctx, &finalized, model.EthGetBlockByNumber, model.EthBlockFinalized, false))
+	require.GreaterOrEqual(t, unittest.HexToBigInt(t, finalized[model.BlockNumber].(string)).Uint64(), uint64(node.BlocksPerEpoch))
+}
+
+// TestWaitForSafeHead_ReportsLaggingNode verifies the error names the node that is behind.
+// Peer nodes do not produce blocks and, without a consensus client, never advance
+// past genesis, so the second node is reported as behind.
+func TestWaitForSafeHead_ReportsLaggingNode(t *testing.T) {
+	ctx, cancel, manager := startNodes(t, 2)
+	defer cancel()
+
+	waitCtx, waitCancel := context.WithTimeout(ctx, 3*time.Second)
+	defer waitCancel()
+	err := manager.WaitForSafeHead(waitCtx, 1)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	var behind *node.BehindError
+	require.True(t, errors.As(err, &behind))
+	require.Equal(t, model.EthBlockSafe, behind.Label)
+	require.Len(t, behind.Nodes, 1)
+	require.Equal(t, 1, behind.Nodes[0].Index)
+	require.Equal(t, node.LayerExecution, behind.Nodes[0].Layer)
+	require.Equal(t, uint64(1), behind.Nodes[0].Want)
+	require.Contains(t, err.Error(), "node 1 el safe block 0 < 1")
+}
+
+// TestWaitForSafeHead_BeaconBehind verifies the paired beacon node's justified
+// checkpoint is part of the safe head check.
+func TestWaitForSafeHead_BeaconBehind(t *testing.T) {
+	manager := newManager(t)
+	beacon := unittest.NewFakeBeacon(t)
+	require.NoError(t, manager.SetBeaconEndpoint(0, beacon.URL()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	t.Cleanup(func() {
+		unittest.RequireCallMustReturnWithinTimeout(t, manager.Done, node.ShutdownTimeout, "node shutdown failed")
+	})
+	require.NoError(t, manager.Start(ctx, 1))
+
+	waitCtx, waitCancel := context.WithTimeout(ctx, 3*time.Second)
+	defer waitCancel()
+	err := manager.WaitForSafeHead(waitCtx, 1)
+	var behind *node.BehindError
+	require.True(t, errors.As(err, &behind))
+	require.Len(t, behind.Nodes, 1)
+	require.Equal(t, node.LayerConsensus, behind.Nodes[0].Layer)
+	require.Contains(t, err.Error(), "node 0 cl safe epoch 0 < 1")
+
+	// Once the beacon node justifies the first epoch, the safe head is reached.
+	beacon.SetFinality(1, 0)
+	waitCtx, waitCancel = context.WithTimeout(ctx, 3*node.OperationTimeout)
+	defer waitCancel()
+	require.NoError(t, manager.WaitForSafeHead(waitCtx, 1))
+}
@@ -12,10 +12,14 @@ import (
 	"github.com/ethereum/go-ethereum/eth"
 	"github.com/ethereum/go-ethereum/eth/catalyst"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/eth/filters"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/prysmaticlabs/prysm/v5/runtime/interop"
 	"github.com/rs/zerolog"
+	"github.com/thep2p/go-eth-localnet/internal/contracts"
 	"github.com/thep2p/go-eth-localnet/internal/model"
 )
 
@@ -26,12 +30,17 @@ const (
 	ShutdownTimeout = 5 * time.Second
 	// OperationTimeout is the maximum time to wait for an operation to complete, e.g., RPC calls, block fetch, etc.
 	OperationTimeout = 5 * time.Second
+	// maxPeers is the peer limit of every node. Geth reserves a third of it for
+	// dialed connections, so it must leave inbound slots for the nodes that
+	// dial in at startup and for links added later by Connect and Partition.
+	maxPeers = 50
 )
 
 // Launcher starts a Geth node, parsing StaticNodes from cfg and adding them to the P2P configuration.
+// It does not coordinate block production across nodes; Manager ensures only
+// one launched node mines.
 type Launcher struct {
-	logger     zerolog.Logger
-	minerCount int
+	logger zerolog.Logger
 }
 
 // LaunchOption mutates the genesis block before the node starts.
@@ -52,6 +61,71 @@ func WithPreFundGenesisAccount(addr common.Address, bal *big.Int) LaunchOption {
 	}
 }
 
+// DefaultDepositContractAddress is the conventional deposit contract address of
+// local and interop networks.
+var DefaultDepositContractAddress = common.HexToAddress("0x4242424242424242424242424242424242424242")
+
+// WithDepositContract predeploys the beacon chain deposit contract at addr.
+//
+// The contract is installed with the storage of an empty deposit tree (the
+// precomputed zero hashes of each tree level), so get_deposit_root matches an
+// empty beacon chain deposit tree and the first deposit lands at index 0.
+// The address is also recorded as the chain's deposit contract, which makes
+// the node surface deposit logs as EIP-6110 deposit requests.
+// Record the same address in consensus.Config.DepositContractAddress.
+func WithDepositContract(addr common.Address) LaunchOption {
+	return func(gen *core.Genesis) {
+		if gen.Alloc == nil {
+			gen.Alloc = types.GenesisAlloc{}
+		}
+		storage := make(map[common.Hash]common.Hash, len(interop.DefaultDepositContractStorage))
+		for k, v := range interop.DefaultDepositContractStorage {
+			storage[common.HexToHash(k)] = common.HexToHash(v)
+		}
+		gen.Alloc[addr] = types.Account{
+			Code:    common.FromHex(interop.DepositContractCode),
+			Storage: storage,
+			Balance: new(big.Int),
+			Nonce:   1,
+		}
+
+		// Copy the chain config so genesis blocks never share it.
+		chainConfig := *gen.Config
+		chainConfig.DepositContractAddress = addr
+		gen.Config = &chainConfig
+	}
+}
+
+// WithDeterministicDeployer predeploys the standard deterministic deployment
+// proxy at contracts.DeterministicDeployerAddress, where public networks have
+// it too. Contracts deployed through it with contracts.DeployDeterministic
+// get the same CREATE2 address on every run, whichever account deploys them.
+func WithDeterministicDeployer() LaunchOption {
+	return func(gen *core.Genesis) {
+		if gen.Alloc == nil {
+			gen.Alloc = types.GenesisAlloc{}
+		}
+		gen.Alloc[contracts.DeterministicDeployerAddress] = types.Account{
+			Code:    common.FromHex(contracts.DeterministicDeployerCode),
+			Balance: new(big.Int),
+			Nonce:   1,
+		}
+	}
+}
+
+// NewGenesis returns the genesis block every launched node starts from, with
+// the given options applied. Use it to derive artifacts that must agree with
+// the execution genesis, such as the beacon chain genesis state.
+func NewGenesis(opts ...LaunchOption) *core.Genesis {
+	// Creates a genesis block for a development network.
+	// Setting the gas limit to 30 million which is typical for Ethereum blocks.
+	genesis := core.DeveloperGenesisBlock(30_000_000, nil)
+	for _, opt := range opts {
+		opt(genesis)
+	}
+	return genesis
+}
+
 // NewLauncher returns a Launcher.
 func NewLauncher(logger zerolog.Logger) *Launcher {
 	return &Launcher{logger: logger.With().Str("component", "node-launcher").Logger()}
@@ -59,6 +133,10 @@ func NewLauncher(logger zerolog.Logger) *Launcher {
 
 // Launch creates, configures, and starts a Geth node with static peers.
 func (l *Launcher) Launch(cfg model.Config, opts ...LaunchOption) (*node.Node, error) {
+	if cfg.ExternalConsensus && !cfg.EnableEngineAPI {
+		return nil, fmt.Errorf("external consensus requires the engine api")
+	}
+
 	// ensure datadir
 	if err := os.MkdirAll(cfg.DataDir, 0755); err != nil {
 		return nil, fmt.Errorf("mkdir datadir: %w", err)
@@ -70,7 +148,7 @@ func (l *Launcher) Launch(cfg model.Config, opts ...LaunchOption) (*node.Node, e
 		PrivateKey:  cfg.PrivateKey,
 		NoDiscovery: true,
 		StaticNodes: make([]*enode.Node, 0, len(cfg.StaticNodes)),
-		MaxPeers:    len(cfg.StaticNodes) + 1,
+		MaxPeers:    maxPeers,
 	}
 	for _, url := range cfg.StaticNodes {
 		n, err := enode.Parse(enode.ValidSchemes, url)
@@ -91,6 +169,12 @@ func (l *Launcher) Launch(cfg model.Config, opts ...LaunchOption) (*node.Node, e
 		UseLightweightKDF: true,
 	}
 
+	if cfg.WSPort != 0 {
+		nodeCfg.WSHost = "127.0.0.1"
+		nodeCfg.WSPort = cfg.WSPort
+		nodeCfg.WSModules = nodeCfg.HTTPModules
+	}
+
 	// Configure authenticated RPC for Engine API if enabled
 	if cfg.EnableEngineAPI {
 		nodeCfg.AuthAddr = "127.0.0.1"
@@ -104,12 +188,7 @@ func (l *Launcher) Launch(cfg model.Config, opts ...LaunchOption) (*node.Node, e
 		return nil, fmt.Errorf("new node: %w", err)
 	}
 
-	// Creates a genesis block for a development network.
-	// Setting the gas limit to 30 million which is typical for Ethereum blocks.
-	genesis := core.DeveloperGenesisBlock(30_000_000, nil)
-	for _, opt := range opts {
-		opt(genesis)
-	}
+	genesis := NewGenesis(opts...)
 	ethCfg := &ethconfig.Config{
 		// Network Ids are used to differentiate between different Ethereum networks.
 		// The mainnet uses 1, and private networks often use 1337.
@@ -124,16 +203,34 @@ func (l *Launcher) Launch(cfg model.Config, opts ...LaunchOption) (*node.Node, e
 
 		return nil, fmt.Errorf("attach eth: %w", err)
 	}
+	// The log filtering API (eth_getLogs, eth_subscribe logs) is not part of
+	// the eth service and must be registered separately.
+	stack.RegisterAPIs([]rpc.API{{
+		Namespace: "eth",
+		Service:   filters.NewFilterAPI(filters.NewFilterSystem(ethService.APIBackend, filters.Config{})),
+	}})
+
+	if cfg.ExternalConsensus {
+		// Without a simulated beacon the node only follows forkchoice updates
+		// sent to its Engine API.
+		if err := catalyst.Register(stack, ethService); err != nil {
+			return nil, fmt.Errorf("register catalyst: %w", err)
+		}
+		if err := stack.Start(); err != nil {
+			return nil, fmt.Errorf("start node: %w", err)
+		}
+		l.logger.Info().Str("enode", stack.Server().NodeInfo().Enode).Str(
+			"id",
+			cfg.ID.String(),
+		).Msg("node started with external consensus")
+		return stack, nil
+	}
 
 	var (
 		simBeacon *catalyst.SimulatedBeacon
 		beaconErr error
 	)
 	if cfg.Mine {
-		l.minerCount++
-		if l.minerCount > 1 {
-			l.logger.Warn().Int("miner_count", l.minerCount).Msg("multiple miners detected - only one should produce blocks to avoid conflicts")
-		}
 		simBeacon, beaconErr = catalyst.NewSimulatedBeacon(1, common.Address{}, ethService)
 	} else {
 		simBeacon, beaconErr = catalyst.NewSimulatedBeacon(0, common.Address{}, ethService)
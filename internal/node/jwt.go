@@ -4,13 +4,21 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/thep2p/go-eth-localnet/internal/engine"
 )
 
 const (
 	// JWTFileName is the name of the file containing the JWT secret for Engine API authentication.
 	JWTFileName = "jwt.hex"
+	// JWTSecretLength is the length in bytes of an Engine API JWT secret.
+	JWTSecretLength = engine.JWTSecretLength
 )
 
 // GenerateJWTSecret creates a 32-byte random JWT secret for Engine API auth.
@@ -18,21 +26,114 @@ const (
 // with 0600 permissions for security. The data directory will be created if it
 // doesn't exist. Returns the path to the JWT secret file.
 func GenerateJWTSecret(dataDir string) (string, error) {
-	// Ensure the data directory exists
-	if err := os.MkdirAll(dataDir, 0755); err != nil {
-		return "", fmt.Errorf("create data dir: %w", err)
+	secret, err := NewJWTSecret()
+	if err != nil {
+		return "", err
 	}
+	return WriteJWTSecret(dataDir, secret)
+}
 
-	secret := make([]byte, 32)
+// NewJWTSecret returns a random 32-byte JWT secret.
+func NewJWTSecret() ([]byte, error) {
+	secret := make([]byte, JWTSecretLength)
 	if _, err := rand.Read(secret); err != nil {
-		return "", fmt.Errorf("generate jwt secret: %w", err)
+		return nil, fmt.Errorf("generate jwt secret: %w", err)
 	}
+	return secret, nil
+}
 
+// WriteJWTSecret writes a predetermined 32-byte JWT secret, hex-encoded, to a
+// file named "jwt.hex" in the specified data directory with 0600 permissions.
+// The data directory will be created if it doesn't exist. Returns the path to
+// the JWT secret file.
+func WriteJWTSecret(dataDir string, secret []byte) (string, error) {
 	jwtPath := filepath.Join(dataDir, JWTFileName)
+	if err := writeJWTSecretFile(jwtPath, secret); err != nil {
+		return "", err
+	}
+	return jwtPath, nil
+}
+
+// writeJWTSecretFile writes a 32-byte JWT secret, hex-encoded, to jwtPath,
+// creating its directory if needed.
+func writeJWTSecretFile(jwtPath string, secret []byte) error {
+	if len(secret) != JWTSecretLength {
+		return fmt.Errorf("jwt secret must be %d bytes, got %d", JWTSecretLength, len(secret))
+	}
+
+	// Ensure the data directory exists
+	if err := os.MkdirAll(filepath.Dir(jwtPath), 0755); err != nil {
+		return fmt.Errorf("create data dir: %w", err)
+	}
+
 	content := hex.EncodeToString(secret)
 	if err := os.WriteFile(jwtPath, []byte(content), 0600); err != nil {
-		return "", fmt.Errorf("write jwt secret: %w", err)
+		return fmt.Errorf("write jwt secret: %w", err)
 	}
+	return nil
+}
 
-	return jwtPath, nil
+// ReadJWTSecret reads a hex-encoded 32-byte JWT secret, optionally 0x-prefixed,
+// from jwtPath and returns the decoded secret.
+func ReadJWTSecret(jwtPath string) ([]byte, error) {
+	content, err := os.ReadFile(jwtPath)
+	if err != nil {
+		return nil, fmt.Errorf("read jwt secret: %w", err)
+	}
+	secret, err := engine.DecodeJWTSecret(content)
+	if err != nil {
+		return nil, err
+	}
+	return secret[:], nil
+}
+
+// JWTClaims are the claims of a token issued by IssueJWTToken.
+type JWTClaims struct {
+	// IssuedAt is the iat claim. Geth rejects tokens issued more than 60 seconds
+	// before or after its clock, so shift it to test stale tokens and clock skew.
+	IssuedAt time.Time
+	// ID is the optional id claim, identifying the consensus client.
+	ID string
+	// ExpiresAt is the optional exp claim. Zero omits it.
+	ExpiresAt time.Time
+}
+
+// IssueJWTToken signs an HS256 token for Engine API authentication with the
+// given claims, unlike the fresh tokens Engine API clients create per request.
+// Use it with JWTTokenAuth to test how the node handles expired, stale or
+// skewed tokens.
+//
+// Args:
+//   - jwtSecret: hex-encoded 32-byte JWT secret, as returned by Manager.GetJWTSecret
+//   - claims: the claims of the token
+//
+// Returns the signed token. All errors indicate a malformed secret.
+func IssueJWTToken(jwtSecret []byte, claims JWTClaims) (string, error) {
+	secret, err := engine.DecodeJWTSecret(jwtSecret)
+	if err != nil {
+		return "", err
+	}
+
+	mapClaims := jwt.MapClaims{"iat": &jwt.NumericDate{Time: claims.IssuedAt}}
+	if claims.ID != "" {
+		mapClaims["id"] = claims.ID
+	}
+	if !claims.ExpiresAt.IsZero() {
+		mapClaims["exp"] = &jwt.NumericDate{Time: claims.ExpiresAt}
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, mapClaims).SignedString(secret[:])
+	if err != nil {
+		return "", fmt.Errorf("sign jwt token: %w", err)
+	}
+	return signed, nil
+}
+
+// JWTTokenAuth returns an rpc.HTTPAuth that sends the given token with every
+// request, e.g. for rpc.DialOptions(ctx, endpoint, rpc.WithHTTPAuth(JWTTokenAuth(token))).
+func JWTTokenAuth(token string) rpc.HTTPAuth {
+	return func(h http.Header) error {
+		h.Set("Authorization", "Bearer "+token)
+		return nil
+	}
 }
@@ -1,14 +1,20 @@
 package node_test
 
 import (
+	"bytes"
+	"context"
 	"encoding/hex"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/stretchr/testify/require"
+	"github.com/thep2p/go-eth-localnet/internal/model"
 	"github.com/thep2p/go-eth-localnet/internal/node"
 	"github.com/thep2p/go-eth-localnet/internal/unittest"
+	"github.com/thep2p/go-eth-localnet/internal/utils"
 )
 
 // TestGenerateJWTSecret_Success validates that JWT secret generation works
@@ -116,3 +122,186 @@ func TestGenerateJWTSecret_Idempotency(t *testing.T) {
 	// Content should be different (new random secret)
 	require.NotEqual(t, content1, content2, "JWT secrets should be different after regeneration")
 }
+
+// TestWriteJWTSecret_Predetermined validates that a predetermined secret is
+// written hex-encoded and read back unchanged, and that secrets of the wrong
+// length are rejected.
+func TestWriteJWTSecret_Predetermined(t *testing.T) {
+	tempDir := unittest.NewTempDir(t)
+	defer tempDir.Remove()
+
+	secret := bytes.Repeat([]byte{0xab}, node.JWTSecretLength)
+	jwtPath, err := node.WriteJWTSecret(tempDir.Path(), secret)
+	require.NoError(t, err)
+	require.Equal(t, node.JWTFileName, filepath.Base(jwtPath))
+
+	content, err := os.ReadFile(jwtPath)
+	require.NoError(t, err)
+	require.Equal(t, hex.EncodeToString(secret), string(content))
+
+	read, err := node.ReadJWTSecret(jwtPath)
+	require.NoError(t, err)
+	require.Equal(t, secret, read)
+
+	_, err = node.WriteJWTSecret(tempDir.Path(), []byte("short"))
+	require.ErrorContains(t, err, "must be 32 bytes")
+}
+
+// newEngineAPIManager returns a Manager with the Engine API enabled that has not
+// started any nodes, so per-node JWT settings can still be applied.
+func newEngineAPIManager(t *testing.T) (context.Context, *node.Manager) {
+	t.Helper()
+
+	tmp := unittest.NewTempDir(t)
+	manager := node.NewNodeManager(
+		unittest.Logger(t), node.NewLauncher(unittest.Logger(t)), tmp.Path(), func() int {
+			return unittest.NewPort(t)
+		},
+	)
+	require.NoError(t, manager.EnableEngineAPI())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(tmp.Remove)
+	t.Cleanup(
+		func() {
+			cancel()
+			unittest.RequireCallMustReturnWithinTimeout(
+				t, manager.Done, node.ShutdownTimeout, "node shutdown failed",
+			)
+		},
+	)
+	return ctx, manager
+}
+
+// callEngineWithToken calls eth_chainId on the node's Engine API with a fixed token.
+func callEngineWithToken(t *testing.T, ctx context.Context, manager *node.Manager, index int, token string) error {
+	t.Helper()
+
+	client, err := rpc.DialOptions(
+		ctx, utils.LocalAddress(manager.GetEnginePort(index)), rpc.WithHTTPAuth(node.JWTTokenAuth(token)),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	var chainID string
+	return client.CallContext(ctx, &chainID, model.EthChainID)
+}
+
+// TestManagerPredeterminedAndSharedJWTSecret validates that a node uses a
+// predetermined secret, and that a node can share the secret file of its
+// consensus client.
+func TestManagerPredeterminedAndSharedJWTSecret(t *testing.T) {
+	ctx, manager := newEngineAPIManager(t)
+
+	secret := bytes.Repeat([]byte{0x01}, node.JWTSecretLength)
+	require.NoError(t, manager.SetJWTSecret(0, secret))
+
+	clDir := t.TempDir()
+	sharedSecret := bytes.Repeat([]byte{0x02}, node.JWTSecretLength)
+	sharedPath, err := node.WriteJWTSecret(clDir, sharedSecret)
+	require.NoError(t, err)
+	require.NoError(t, manager.SetJWTSecretPath(1, sharedPath))
+
+	require.NoError(t, manager.Start(ctx, 2))
+
+	got, err := manager.GetJWTSecret(0)
+	require.NoError(t, err)
+	require.Equal(t, hex.EncodeToString(secret), string(got))
+
+	require.Equal(t, sharedPath, manager.GetJWTSecretPath(1))
+	got, err = manager.GetJWTSecret(1)
+	require.NoError(t, err)
+	require.Equal(t, hex.EncodeToString(sharedSecret), string(got))
+
+	token, err := node.IssueJWTToken([]byte(hex.EncodeToString(sharedSecret)), node.JWTClaims{IssuedAt: time.Now()})
+	require.NoError(t, err)
+	require.NoError(t, callEngineWithToken(t, ctx, manager, 1, token))
+
+	// Settings of started nodes cannot change anymore.
+	require.ErrorContains(t, manager.SetJWTSecret(0, secret), "before it starts")
+	require.ErrorContains(t, manager.SetJWTSecretPath(1, sharedPath), "before it starts")
+}
+
+// TestIssueJWTToken_AuthEdgeCases validates that the Engine API accepts tokens
+// within the allowed clock drift and rejects stale, future, expired and
+// foreign tokens.
+func TestIssueJWTToken_AuthEdgeCases(t *testing.T) {
+	ctx, manager := newEngineAPIManager(t)
+	require.NoError(t, manager.Start(ctx, 1))
+
+	jwtSecret, err := manager.GetJWTSecret(0)
+	require.NoError(t, err)
+	otherSecret, err := node.NewJWTSecret()
+	require.NoError(t, err)
+
+	now := time.Now()
+	cases := []struct {
+		name   string
+		secret []byte
+		claims node.JWTClaims
+		valid  bool
+	}{
+		{"current", jwtSecret, node.JWTClaims{IssuedAt: now, ID: "prysm"}, true},
+		{"skewed within drift", jwtSecret, node.JWTClaims{IssuedAt: now.Add(-30 * time.Second)}, true},
+		{"stale", jwtSecret, node.JWTClaims{IssuedAt: now.Add(-2 * time.Minute)}, false},
+		{"future", jwtSecret, node.JWTClaims{IssuedAt: now.Add(2 * time.Minute)}, false},
+		{"expired", jwtSecret, node.JWTClaims{IssuedAt: now, ExpiresAt: now.Add(-time.Second)}, false},
+		{"foreign secret", []byte(hex.EncodeToString(otherSecret)), node.JWTClaims{IssuedAt: now}, false},
+	}
+	for _, tc := range cases {
+		token, err := node.IssueJWTToken(tc.secret, tc.claims)
+		require.NoError(t, err, tc.name)
+
+		err = callEngineWithToken(t, ctx, manager, 0, token)
+		if tc.valid {
+			require.NoError(t, err, tc.name)
+		} else {
+			require.ErrorContains(t, err, "401", tc.name)
+		}
+	}
+
+	_, err = node.IssueJWTToken([]byte("abcd"), node.JWTClaims{IssuedAt: now})
+	require.ErrorContains(t, err, "must be 32 bytes")
+}
+
+// TestRotateJWTSecret validates that rotating a node's secret restarts it with
+// the new secret, keeping its chain, and invalidates the old secret.
+func TestRotateJWTSecret(t *testing.T) {
+	ctx, manager := newEngineAPIManager(t)
+	require.NoError(t, manager.Start(ctx, 1))
+	unittest.RequireRpcReadyWithinTimeout(t, ctx, manager.RPCPort(), node.OperationTimeout)
+
+	client, err := rpc.DialContext(ctx, utils.LocalAddress(manager.RPCPort()))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		var number string
+		return client.CallContext(ctx, &number, model.EthBlockNumber) == nil && number != "0x0"
+	}, node.OperationTimeout, 100*time.Millisecond, "node should produce blocks before rotation")
+	client.Close()
+
+	oldSecret, err := manager.GetJWTSecret(0)
+	require.NoError(t, err)
+	newSecret, err := manager.RotateJWTSecret(ctx, 0, nil)
+	require.NoError(t, err)
+	require.Len(t, newSecret, node.JWTSecretLength)
+
+	got, err := manager.GetJWTSecret(0)
+	require.NoError(t, err)
+	require.Equal(t, hex.EncodeToString(newSecret), string(got))
+
+	oldToken, err := node.IssueJWTToken(oldSecret, node.JWTClaims{IssuedAt: time.Now()})
+	require.NoError(t, err)
+	require.ErrorContains(t, callEngineWithToken(t, ctx, manager, 0, oldToken), "401")
+
+	newToken, err := node.IssueJWTToken(got, node.JWTClaims{IssuedAt: time.Now()})
+	require.NoError(t, err)
+	require.NoError(t, callEngineWithToken(t, ctx, manager, 0, newToken))
+
+	// The restarted node keeps its chain.
+	client, err = rpc.DialContext(ctx, utils.LocalAddress(manager.RPCPort()))
+	require.NoError(t, err)
+	defer client.Close()
+	var number string
+	require.NoError(t, client.CallContext(ctx, &number, model.EthBlockNumber))
+	require.NotEqual(t, "0x0", number)
+}
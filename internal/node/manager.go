@@ -4,6 +4,7 @@ package node
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
 	"math/big"
 	"os"
@@ -16,7 +17,11 @@ import (
 	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/rs/zerolog"
+	"github.com/thep2p/go-eth-localnet/internal/consensus"
+	"github.com/thep2p/go-eth-localnet/internal/engine"
 	"github.com/thep2p/go-eth-localnet/internal/model"
+	"github.com/thep2p/go-eth-localnet/internal/netem"
+	"github.com/thep2p/go-eth-localnet/internal/utils"
 )
 
 // localNetChainID represents the chain ID for a local Ethereum network (1337).
@@ -34,11 +39,39 @@ type Manager struct {
 	mu              sync.RWMutex
 	nodes           []*gethnode.Node
 	configs         []model.Config
+	launchOpts      [][]LaunchOption
 	shutdown        chan struct{}
 	cancel          context.CancelFunc
 	enableEngineAPI bool
+	// externalConsensus launches nodes without a simulated beacon (see EnableExternalConsensus).
+	externalConsensus bool
+	// beaconEndpoints maps node index to the Beacon API of its paired consensus client.
+	beaconEndpoints map[int]string
+	// faultProxies maps node index to the fault proxy in front of its Engine API.
+	faultProxies map[int]*engine.FaultProxy
+	// jwtSecrets maps node index to a predetermined JWT secret (see SetJWTSecret).
+	jwtSecrets map[int][]byte
+	// jwtSecretPaths maps node index to an existing JWT secret file (see SetJWTSecretPath).
+	jwtSecretPaths map[int]string
+	// producer is the index of the node producing blocks, noProducer, or
+	// producerStarting while a producer is being started.
+	producer int
+	// stopped holds the indices of nodes stopped with StopNode.
+	stopped map[int]struct{}
+	// cutLinks holds the connections dropped by Partition or Disconnect, restored by Heal.
+	cutLinks map[link]struct{}
+	// shapedLinks maps a link to the relay its connection is routed through (see SetLinkConditions).
+	shapedLinks map[link]*netem.Relay
 }
 
+const (
+	// noProducer marks that no node of a Manager produces blocks.
+	noProducer = -1
+	// producerStarting marks that the block producer role is reserved for a
+	// node that is being started (see startSingleNode).
+	producerStarting = -2
+)
+
 // NewNodeManager constructs a Manager that will launch multiple nodes.
 func NewNodeManager(
 	logger zerolog.Logger,
@@ -46,29 +79,48 @@ func NewNodeManager(
 	baseDataDir string,
 	assignNewPort func() int) *Manager {
 	return &Manager{
-		logger:        logger.With().Str("component", "node-manager").Logger(),
-		baseDataDir:   baseDataDir,
-		launcher:      launcher,
-		assignNewPort: assignNewPort,
-		shutdown:      make(chan struct{}),
-		chainID:       big.NewInt(localNetChainID),
-		nodes:         make([]*gethnode.Node, 0),
-		configs:       make([]model.Config, 0),
+		logger:          logger.With().Str("component", "node-manager").Logger(),
+		baseDataDir:     baseDataDir,
+		launcher:        launcher,
+		assignNewPort:   assignNewPort,
+		shutdown:        make(chan struct{}),
+		chainID:         big.NewInt(localNetChainID),
+		nodes:           make([]*gethnode.Node, 0),
+		configs:         make([]model.Config, 0),
+		beaconEndpoints: make(map[int]string),
+		faultProxies:    make(map[int]*engine.FaultProxy),
+		jwtSecrets:      make(map[int][]byte),
+		jwtSecretPaths:  make(map[int]string),
+		producer:        noProducer,
+		stopped:         make(map[int]struct{}),
+		cutLinks:        make(map[link]struct{}),
+		shapedLinks:     make(map[link]*netem.Relay),
 	}
 }
 
-// Start launches the specified number of nodes. The first node will mine blocks,
-// and subsequent nodes will connect to the first node as peers.
+// Start launches the specified number of nodes. The first node will mine blocks
+// unless another node already is the block producer, and subsequent nodes will
+// connect to the first node as peers.
+//
+// If a beacon endpoint is set for any of the started nodes (see SetBeaconEndpoint),
+// Start blocks until each such EL+CL pair is ready before returning.
 func (m *Manager) Start(ctx context.Context, nodeCount int, opts ...LaunchOption) error {
 	if nodeCount <= 0 {
 		return fmt.Errorf("node count must be positive, got %d", nodeCount)
 	}
 
-	ctx, m.cancel = context.WithCancel(ctx)
-	go m.handleShutdown(ctx)
+	m.mu.RLock()
+	firstIndex := len(m.nodes)
+	mine := m.producer == noProducer
+	m.mu.RUnlock()
+
+	if m.cancel == nil {
+		ctx, m.cancel = context.WithCancel(ctx)
+		go m.handleShutdown(ctx)
+	}
 
 	// Start the first node (miner)
-	if err := m.startSingleNode(ctx, true, nil, opts...); err != nil {
+	if err := m.startSingleNode(ctx, mine, nil, opts...); err != nil {
 		return fmt.Errorf("failed to start miner node: %w", err)
 	}
 
@@ -76,7 +128,7 @@ func (m *Manager) Start(ctx context.Context, nodeCount int, opts ...LaunchOption
 	var staticNodes []string
 	if nodeCount > 1 {
 		m.mu.RLock()
-		staticNodes = []string{m.nodes[0].Server().NodeInfo().Enode}
+		staticNodes = []string{m.nodes[firstIndex].Server().NodeInfo().Enode}
 		m.mu.RUnlock()
 	}
 
@@ -87,6 +139,18 @@ func (m *Manager) Start(ctx context.Context, nodeCount int, opts ...LaunchOption
 		}
 	}
 
+	for i := firstIndex; i < firstIndex+nodeCount; i++ {
+		m.mu.RLock()
+		_, paired := m.beaconEndpoints[i]
+		m.mu.RUnlock()
+		if !paired {
+			continue
+		}
+		if err := m.WaitForPairReady(ctx, i); err != nil {
+			return fmt.Errorf("node %d: %w", i, err)
+		}
+	}
+
 	m.logger.Info().Int("node_count", nodeCount).Msg("all nodes started successfully")
 	return nil
 }
@@ -95,7 +159,8 @@ func (m *Manager) Start(ctx context.Context, nodeCount int, opts ...LaunchOption
 //
 // Parameters:
 //   - ctx: Context for cancellation and timeout.
-//   - mine: If true, the node will mine blocks.
+//   - mine: If true, the node will mine blocks. Only one node may produce blocks,
+//     so this fails if another node is already the block producer (see SetBlockProducer).
 //   - staticNodes: List of enode URLs for peers to connect to.
 //   - opts: Optional launch options for node configuration.
 //
@@ -119,28 +184,50 @@ func (m *Manager) startSingleNode(ctx context.Context, mine bool, staticNodes []
 		return fmt.Errorf("generate key: %w", err)
 	}
 
-	m.mu.RLock()
+	// Reserve the producer role before launching, so concurrent starts cannot
+	// both claim it.
+	m.mu.Lock()
 	nodeIndex := len(m.nodes)
-	m.mu.RUnlock()
+	externalConsensus := m.externalConsensus
+	produce := mine && !externalConsensus
+	if produce {
+		switch producer := m.producer; producer {
+		case noProducer:
+			m.producer = producerStarting
+		case producerStarting:
+			m.mu.Unlock()
+			return fmt.Errorf("another block producer is starting")
+		default:
+			m.mu.Unlock()
+			return fmt.Errorf("node %d is already the block producer", producer)
+		}
+	}
+	m.mu.Unlock()
+	if produce {
+		defer m.releaseProducerReservation()
+	}
+
 	cfg := model.Config{
 		ID:          enode.PubkeyToIDV4(&priv.PublicKey),
 		DataDir:     filepath.Join(m.baseDataDir, fmt.Sprintf("node%d", nodeIndex)),
 		P2PPort:     m.assignNewPort(),
 		RPCPort:     m.assignNewPort(),
+		WSPort:      m.assignNewPort(),
 		PrivateKey:  priv,
 		StaticNodes: staticNodes,
 		Mine:        mine,
 	}
 
-	// Generate JWT secret and configure Engine API if enabled
+	// Prepare JWT secret and configure Engine API if enabled
 	if m.enableEngineAPI {
-		jwtPath, err := GenerateJWTSecret(cfg.DataDir)
+		jwtPath, err := m.prepareJWTSecret(nodeIndex, cfg.DataDir)
 		if err != nil {
-			return fmt.Errorf("generate jwt secret: %w", err)
+			return err
 		}
 		cfg.JWTSecretPath = jwtPath
 		cfg.EnableEngineAPI = true
 		cfg.EnginePort = m.assignNewPort()
+		cfg.ExternalConsensus = externalConsensus
 	}
 
 	n, err := m.launcher.Launch(cfg, opts...)
@@ -149,27 +236,133 @@ func (m *Manager) startSingleNode(ctx context.Context, mine bool, staticNodes []
 	}
 
 	m.mu.Lock()
+	nodeIndex = len(m.nodes)
 	m.nodes = append(m.nodes, n)
 	m.configs = append(m.configs, cfg)
+	m.launchOpts = append(m.launchOpts, opts)
+	if produce {
+		m.producer = nodeIndex
+	}
 	m.mu.Unlock()
 
-	rpcURL := fmt.Sprintf("http://127.0.0.1:%d", cfg.RPCPort)
+	if err := waitForRPC(ctx, cfg.RPCPort); err != nil {
+		_ = n.Close()
+		m.markStopped(nodeIndex)
+		return err
+	}
+
+	m.logger.Info().Int("node_index", nodeIndex).Str("enode", n.Server().NodeInfo().Enode).Msg("node started")
+	return nil
+}
+
+// releaseProducerReservation gives up the producer role reserved by
+// startSingleNode if the node did not start.
+func (m *Manager) releaseProducerReservation() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.producer == producerStarting {
+		m.producer = noProducer
+	}
+}
+
+// markStopped records the node at the given index as stopped after it failed,
+// and takes the block producer role from it, as StopNode does.
+func (m *Manager) markStopped(index int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stopped[index] = struct{}{}
+	if m.producer == index {
+		m.producer = noProducer
+		m.configs[index].Mine = false
+	}
+}
+
+// prepareJWTSecret returns the JWT secret file of the node at the given index:
+// the file set with SetJWTSecretPath, the secret set with SetJWTSecret written
+// to dataDir, or a fresh random secret written to dataDir.
+func (m *Manager) prepareJWTSecret(index int, dataDir string) (string, error) {
+	m.mu.RLock()
+	secretPath, shared := m.jwtSecretPaths[index]
+	secret, predetermined := m.jwtSecrets[index]
+	m.mu.RUnlock()
+
+	switch {
+	case shared:
+		if _, err := ReadJWTSecret(secretPath); err != nil {
+			return "", fmt.Errorf("shared jwt secret %s: %w", secretPath, err)
+		}
+		return secretPath, nil
+	case predetermined:
+		jwtPath, err := WriteJWTSecret(dataDir, secret)
+		if err != nil {
+			return "", fmt.Errorf("write jwt secret: %w", err)
+		}
+		return jwtPath, nil
+	default:
+		jwtPath, err := GenerateJWTSecret(dataDir)
+		if err != nil {
+			return "", fmt.Errorf("generate jwt secret: %w", err)
+		}
+		return jwtPath, nil
+	}
+}
+
+// restartNode closes the node at the given index and launches it again with the
+// same configuration and data directory, so it keeps its chain, keys and ports.
+// If the node was closed but cannot be launched again, it is marked stopped.
+func (m *Manager) restartNode(ctx context.Context, index int) error {
+	m.mu.RLock()
+	if index < 0 || index >= len(m.nodes) {
+		numNodes := len(m.nodes)
+		m.mu.RUnlock()
+		return fmt.Errorf("node index %d out of range [0, %d)", index, numNodes)
+	}
+	if _, ok := m.stopped[index]; ok {
+		m.mu.RUnlock()
+		return fmt.Errorf("node %d is stopped", index)
+	}
+	n := m.nodes[index]
+	cfg := m.configs[index]
+	opts := m.launchOpts[index]
+	m.mu.RUnlock()
+
+	if err := n.Close(); err != nil {
+		return fmt.Errorf("close node %d: %w", index, err)
+	}
+	restarted, err := m.launcher.Launch(cfg, opts...)
+	if err != nil {
+		m.markStopped(index)
+		return fmt.Errorf("relaunch node %d: %w", index, err)
+	}
+
+	m.mu.Lock()
+	m.nodes[index] = restarted
+	m.mu.Unlock()
+
+	if err := waitForRPC(ctx, cfg.RPCPort); err != nil {
+		return fmt.Errorf("node %d: %w", index, err)
+	}
+
+	m.logger.Info().Int("node_index", index).Msg("node restarted")
+	return nil
+}
+
+// waitForRPC blocks until the RPC server on the given port accepts connections
+// or StartupTimeout elapses.
+func waitForRPC(ctx context.Context, port int) error {
+	rpcURL := utils.LocalAddress(port)
 	deadline := time.Now().Add(StartupTimeout)
 	for {
 		if time.Now().After(deadline) {
-			_ = n.Close()
 			return fmt.Errorf("rpc %q never came up", rpcURL)
 		}
 		client, err := rpc.DialContext(ctx, rpcURL)
 		if err == nil {
 			client.Close()
-			break
+			return nil
 		}
 		time.Sleep(100 * time.Millisecond)
 	}
-
-	m.logger.Info().Int("node_index", nodeIndex).Str("enode", n.Server().NodeInfo().Enode).Msg("node started")
-	return nil
 }
 
 func (m *Manager) handleShutdown(ctx context.Context) {
@@ -177,9 +370,35 @@ func (m *Manager) handleShutdown(ctx context.Context) {
 	m.mu.RLock()
 	nodes := make([]*gethnode.Node, len(m.nodes))
 	copy(nodes, m.nodes)
+	stopped := make(map[int]struct{}, len(m.stopped))
+	for i := range m.stopped {
+		stopped[i] = struct{}{}
+	}
+	proxies := make(map[int]*engine.FaultProxy, len(m.faultProxies))
+	for i, p := range m.faultProxies {
+		proxies[i] = p
+	}
+	relays := make(map[link]*netem.Relay, len(m.shapedLinks))
+	for l, r := range m.shapedLinks {
+		relays[l] = r
+	}
 	m.mu.RUnlock()
 
+	for l, r := range relays {
+		if err := r.Close(); err != nil {
+			m.logger.Error().Err(err).Ints("link", l[:]).Msg("failed to close link relay")
+		}
+	}
+
+	for i, p := range proxies {
+		if err := p.Close(); err != nil {
+			m.logger.Error().Err(err).Int("node_index", i).Msg("failed to close engine fault proxy")
+		}
+	}
 	for i, n := range nodes {
+		if _, ok := stopped[i]; ok {
+			continue
+		}
 		if err := n.Close(); err != nil {
 			m.logger.Error().Err(err).Int("node_index", i).Msg("failed to close geth node")
 		}
@@ -187,6 +406,128 @@ func (m *Manager) handleShutdown(ctx context.Context) {
 	close(m.shutdown)
 }
 
+// StopNode closes the node at the given index. Its index, configuration and
+// data directory are kept, so indices of other nodes do not change. If the
+// node was the block producer, no node produces blocks until another one is
+// assigned with SetBlockProducer.
+// Returns an error if the index is invalid or the node is already stopped.
+func (m *Manager) StopNode(index int) error {
+	m.mu.Lock()
+	if index < 0 || index >= len(m.nodes) {
+		numNodes := len(m.nodes)
+		m.mu.Unlock()
+		return fmt.Errorf("node index %d out of range [0, %d)", index, numNodes)
+	}
+	if _, ok := m.stopped[index]; ok {
+		m.mu.Unlock()
+		return fmt.Errorf("node %d is already stopped", index)
+	}
+	n := m.nodes[index]
+	m.stopped[index] = struct{}{}
+	if m.producer == index {
+		m.producer = noProducer
+		m.configs[index].Mine = false
+	}
+	m.mu.Unlock()
+
+	if err := n.Close(); err != nil {
+		return fmt.Errorf("close node %d: %w", index, err)
+	}
+	m.logger.Info().Int("node_index", index).Msg("node stopped")
+	return nil
+}
+
+// BlockProducer returns the index of the node producing blocks with the
+// simulated beacon. Returns false if no node produces blocks, e.g. after the
+// producer was stopped or when consensus is external.
+func (m *Manager) BlockProducer() (int, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.producer < 0 {
+		return noProducer, false
+	}
+	return m.producer, true
+}
+
+// SetBlockProducer hands the block producer role to the running node at the
+// given index. The current producer, if running, is restarted without mining
+// before the new producer is restarted with mining, so two nodes never produce
+// blocks at the same time. Both nodes keep their chain, keys and ports.
+//
+// Returns an error if the index is invalid, the node is stopped, or nodes are
+// driven by an external consensus client.
+func (m *Manager) SetBlockProducer(ctx context.Context, index int) error {
+	m.mu.RLock()
+	if m.externalConsensus {
+		m.mu.RUnlock()
+		return fmt.Errorf("block production is driven by external consensus")
+	}
+	if index < 0 || index >= len(m.nodes) {
+		numNodes := len(m.nodes)
+		m.mu.RUnlock()
+		return fmt.Errorf("node index %d out of range [0, %d)", index, numNodes)
+	}
+	_, stopped := m.stopped[index]
+	previous := m.producer
+	m.mu.RUnlock()
+
+	if stopped {
+		return fmt.Errorf("node %d is stopped", index)
+	}
+	if previous == producerStarting {
+		return fmt.Errorf("a block producer is starting")
+	}
+	if previous == index {
+		return nil
+	}
+
+	if previous != noProducer {
+		if err := m.setMining(ctx, previous, false); err != nil {
+			return fmt.Errorf("demote block producer %d: %w", previous, err)
+		}
+	}
+	if err := m.setMining(ctx, index, true); err != nil {
+		return fmt.Errorf("promote block producer %d: %w", index, err)
+	}
+
+	m.logger.Info().Int("previous", previous).Int("node_index", index).Msg("block producer changed")
+	return nil
+}
+
+// setMining restarts the node at the given index with mining enabled or
+// disabled and records it as the block producer accordingly. If the restart
+// fails, the previous mining setting and block producer are restored, unless
+// the node could not be launched again and is stopped now, so only a running
+// node is recorded as the block producer.
+func (m *Manager) setMining(ctx context.Context, index int, mine bool) error {
+	m.mu.Lock()
+	previousMine, previousProducer := m.configs[index].Mine, m.producer
+	m.configs[index].Mine = mine
+	switch {
+	case mine:
+		m.producer = index
+	case m.producer == index:
+		m.producer = noProducer
+	}
+	m.mu.Unlock()
+
+	if err := m.restartNode(ctx, index); err != nil {
+		m.mu.Lock()
+		if _, stopped := m.stopped[index]; stopped {
+			m.configs[index].Mine = false
+			if m.producer == index {
+				m.producer = noProducer
+			}
+		} else {
+			m.configs[index].Mine = previousMine
+			m.producer = previousProducer
+		}
+		m.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
 // GethNode returns the first running node instance or nil if no nodes are started.
 // For multi-node setups, use GetNode(index) or GethNodes() to access specific nodes.```
 func (m *Manager) GethNode() *gethnode.Node {
@@ -241,6 +582,18 @@ func (m *Manager) GetRPCPort(index int) int {
 	return m.configs[index].RPCPort
 }
 
+// GetWSEndpoint returns the websocket RPC endpoint of the node at the given index,
+// which serves subscriptions such as eth_subscribe.
+// Returns an empty string if the index is invalid.
+func (m *Manager) GetWSEndpoint(index int) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if index < 0 || index >= len(m.configs) || m.configs[index].WSPort == 0 {
+		return ""
+	}
+	return utils.LocalWSAddress(m.configs[index].WSPort)
+}
+
 // NodeCount returns the number of running nodes.
 func (m *Manager) NodeCount() int {
 	m.mu.RLock()
@@ -267,6 +620,128 @@ func (m *Manager) EnableEngineAPI() error {
 	return nil
 }
 
+// EnableExternalConsensus launches all nodes of this Manager without a simulated
+// beacon, so they only produce blocks when driven over the Engine API, e.g. by
+// an engine.Driver (see NewEngineDriver) or a consensus client.
+// Implies EnableEngineAPI. Must be called before starting any nodes.
+func (m *Manager) EnableExternalConsensus() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.nodes) > 0 {
+		return fmt.Errorf("external consensus must be enabled before starting nodes")
+	}
+	m.enableEngineAPI = true
+	m.externalConsensus = true
+	return nil
+}
+
+// NewEngineDriver returns an engine.Driver for the node at the given index,
+// authenticated with the node's JWT secret. The driver connects to the node's
+// Engine API endpoint (see GetEngineEndpoint). The caller closes the driver.
+// Returns an error if the index is invalid or the Engine API is not enabled.
+func (m *Manager) NewEngineDriver(ctx context.Context, index int) (*engine.Driver, error) {
+	jwtSecret, err := m.GetJWTSecret(index)
+	if err != nil {
+		return nil, err
+	}
+
+	driver, err := engine.NewDriver(ctx, m.logger, m.GetEngineEndpoint(index), jwtSecret)
+	if err != nil {
+		return nil, fmt.Errorf("engine driver for node %d: %w", index, err)
+	}
+	return driver, nil
+}
+
+// StartEngineRecorder starts an engine.Recorder in front of the Engine API of the
+// node at the given index, writing the session to path. Point the consensus
+// client at the recorder's Endpoint instead of the node's Engine API port.
+// The caller closes the recorder to flush the session.
+// Returns an error if the index is invalid or the Engine API is not enabled.
+func (m *Manager) StartEngineRecorder(index int, path string) (*engine.Recorder, error) {
+	enginePort := m.GetEnginePort(index)
+	if enginePort == 0 {
+		return nil, fmt.Errorf("engine api not enabled for node %d", index)
+	}
+
+	listenAddr := fmt.Sprintf("127.0.0.1:%d", m.assignNewPort())
+	recorder, err := engine.NewRecorder(m.logger, listenAddr, utils.LocalAddress(enginePort), path)
+	if err != nil {
+		return nil, fmt.Errorf("engine recorder for node %d: %w", index, err)
+	}
+
+	m.logger.Info().Int("node_index", index).Str("endpoint", recorder.Endpoint()).Str("path", path).Msg("engine recorder started")
+	return recorder, nil
+}
+
+// ReplayEngineSession feeds a session recorded by an engine.Recorder into the node
+// at the given index, authenticated with the node's JWT secret.
+// Returns the calls whose outcome differs from the recording (see engine.Replay).
+func (m *Manager) ReplayEngineSession(ctx context.Context, index int, path string, opts engine.ReplayOptions) ([]engine.Mismatch, error) {
+	records, err := engine.ReadRecords(path)
+	if err != nil {
+		return nil, err
+	}
+	jwtSecret, err := m.GetJWTSecret(index)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := engine.Dial(ctx, utils.LocalAddress(m.GetEnginePort(index)), jwtSecret)
+	if err != nil {
+		return nil, fmt.Errorf("engine replay for node %d: %w", index, err)
+	}
+	defer client.Close()
+
+	return engine.Replay(ctx, client, records, opts)
+}
+
+// StartEngineFaultProxy places an engine.FaultProxy in front of the Engine API of
+// the node at the given index. From then on GetEngineEndpoint returns the proxy,
+// so consensus clients and drivers wired through the Manager connect to it
+// instead of the node. Add rules to the returned proxy to inject faults.
+// The Manager closes the proxy on shutdown.
+// Returns an error if the index is invalid, the Engine API is not enabled, or
+// the node already has a fault proxy.
+func (m *Manager) StartEngineFaultProxy(index int) (*engine.FaultProxy, error) {
+	jwtSecret, err := m.GetJWTSecret(index)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.faultProxies[index]; ok {
+		return nil, fmt.Errorf("engine fault proxy already started for node %d", index)
+	}
+
+	listenAddr := fmt.Sprintf("127.0.0.1:%d", m.assignNewPort())
+	upstream := utils.LocalAddress(m.configs[index].EnginePort)
+	proxy, err := engine.NewFaultProxy(m.logger, listenAddr, upstream, jwtSecret)
+	if err != nil {
+		return nil, fmt.Errorf("engine fault proxy for node %d: %w", index, err)
+	}
+	m.faultProxies[index] = proxy
+
+	m.logger.Info().Int("node_index", index).Str("endpoint", proxy.Endpoint()).Msg("engine fault proxy started")
+	return proxy, nil
+}
+
+// GetEngineEndpoint returns the URL consensus clients use to reach the Engine API
+// of the node at the given index: the fault proxy if one was started (see
+// StartEngineFaultProxy), otherwise the node's Engine API port.
+// Returns an empty string if the index is invalid or Engine API is not enabled.
+func (m *Manager) GetEngineEndpoint(index int) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if index < 0 || index >= len(m.configs) || m.configs[index].EnginePort == 0 {
+		return ""
+	}
+	if proxy, ok := m.faultProxies[index]; ok {
+		return proxy.Endpoint()
+	}
+	return utils.LocalAddress(m.configs[index].EnginePort)
+}
+
 // GetEnginePort returns the Engine API port for the node at the given index.
 // Returns 0 if the index is invalid or Engine API is not enabled.
 func (m *Manager) GetEnginePort(index int) int {
@@ -289,7 +764,7 @@ func (m *Manager) GetJWTSecret(index int) ([]byte, error) {
 	}
 	jwtPath := m.configs[index].JWTSecretPath
 	// Release lock before file I/O to avoid blocking other operations.
-	// JWT files are immutable once created, so the path remains valid.
+	// The path of a node's JWT file never changes, even when it is rotated.
 	m.mu.RUnlock()
 
 	if jwtPath == "" {
@@ -298,3 +773,142 @@ func (m *Manager) GetJWTSecret(index int) ([]byte, error) {
 
 	return os.ReadFile(jwtPath)
 }
+
+// GetJWTSecretPath returns the path of the JWT secret file of the node at the
+// given index, e.g. to start its consensus client with the same secret.
+// Returns an empty string if the index is invalid or Engine API is not enabled.
+func (m *Manager) GetJWTSecretPath(index int) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if index < 0 || index >= len(m.configs) {
+		return ""
+	}
+	return m.configs[index].JWTSecretPath
+}
+
+// SetJWTSecret sets the predetermined 32-byte JWT secret of the node at the given
+// index instead of a random one. It is written to the node's data directory
+// when the node starts, so it must be called before the node is started.
+func (m *Manager) SetJWTSecret(index int, secret []byte) error {
+	if len(secret) != JWTSecretLength {
+		return fmt.Errorf("jwt secret must be %d bytes, got %d", JWTSecretLength, len(secret))
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if index < len(m.nodes) {
+		return fmt.Errorf("jwt secret of node %d must be set before it starts", index)
+	}
+	m.jwtSecrets[index] = append([]byte(nil), secret...)
+	return nil
+}
+
+// SetJWTSecretPath makes the node at the given index use the JWT secret in an
+// existing file instead of its own, e.g. the secret file of the consensus client
+// it is paired with, so the EL/CL pair shares one secret. Rotating the node's
+// secret (see RotateJWTSecret) rewrites this file. Must be called before the
+// node is started.
+func (m *Manager) SetJWTSecretPath(index int, path string) error {
+	if path == "" {
+		return fmt.Errorf("jwt secret path must not be empty")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if index < len(m.nodes) {
+		return fmt.Errorf("jwt secret path of node %d must be set before it starts", index)
+	}
+	m.jwtSecretPaths[index] = path
+	return nil
+}
+
+// RotateJWTSecret replaces the JWT secret of the running node at the given index
+// and restarts the node so its Engine API only accepts the new secret. The node
+// keeps its chain, keys and ports. A nil secret rotates to a random one. The
+// fault proxy in front of the node, if any, is switched to the new secret.
+// Consensus clients must be restarted with the new secret as well.
+//
+// Returns the new 32-byte secret, or an error if the index is invalid, the
+// Engine API is not enabled or the node fails to restart.
+func (m *Manager) RotateJWTSecret(ctx context.Context, index int, secret []byte) ([]byte, error) {
+	jwtPath := m.GetJWTSecretPath(index)
+	if jwtPath == "" {
+		return nil, fmt.Errorf("engine api: jwt not configured for node %d", index)
+	}
+
+	if secret == nil {
+		var err error
+		if secret, err = NewJWTSecret(); err != nil {
+			return nil, err
+		}
+	}
+	if err := writeJWTSecretFile(jwtPath, secret); err != nil {
+		return nil, err
+	}
+	if err := m.restartNode(ctx, index); err != nil {
+		return nil, fmt.Errorf("rotate jwt secret: %w", err)
+	}
+
+	m.mu.RLock()
+	proxy, proxied := m.faultProxies[index]
+	m.mu.RUnlock()
+	if proxied {
+		if err := proxy.SetJWTSecret([]byte(hex.EncodeToString(secret))); err != nil {
+			return nil, fmt.Errorf("rotate fault proxy jwt secret: %w", err)
+		}
+	}
+
+	m.logger.Info().Int("node_index", index).Msg("jwt secret rotated")
+	return secret, nil
+}
+
+// SetBeaconEndpoint pairs the node at the given index with the Beacon API of its
+// consensus client, e.g. http://127.0.0.1:4000. May be called before the node
+// is started; Start then waits for the pair to be ready before returning.
+func (m *Manager) SetBeaconEndpoint(index int, endpoint string) error {
+	if index < 0 {
+		return fmt.Errorf("node index must not be negative, got %d", index)
+	}
+	if endpoint == "" {
+		return fmt.Errorf("beacon endpoint must not be empty")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.beaconEndpoints[index] = endpoint
+	return nil
+}
+
+// GetBeaconEndpoint returns the Beacon API endpoint paired with the node at the given index.
+// Returns an empty string if no endpoint is set.
+func (m *Manager) GetBeaconEndpoint(index int) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.beaconEndpoints[index]
+}
+
+// WaitForPairReady blocks until the node at the given index and its paired beacon
+// node are both ready, or consensus.BeaconStartupTimeout elapses.
+// Returns an error if the node does not exist or has no beacon endpoint.
+func (m *Manager) WaitForPairReady(ctx context.Context, index int) error {
+	m.mu.RLock()
+	if index < 0 || index >= len(m.configs) {
+		numConfigs := len(m.configs)
+		m.mu.RUnlock()
+		return fmt.Errorf("node index %d out of range [0, %d)", index, numConfigs)
+	}
+	rpcPort := m.configs[index].RPCPort
+	beaconEndpoint, ok := m.beaconEndpoints[index]
+	m.mu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("no beacon endpoint set for node %d", index)
+	}
+
+	if err := consensus.WaitForPairReady(ctx, utils.LocalAddress(rpcPort), beaconEndpoint, consensus.BeaconStartupTimeout); err != nil {
+		return fmt.Errorf("el+cl pair not ready: %w", err)
+	}
+
+	m.logger.Info().Int("node_index", index).Str("beacon_endpoint", beaconEndpoint).Msg("el+cl pair ready")
+	return nil
+}
@@ -0,0 +1,409 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	gethnode "github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/thep2p/go-eth-localnet/internal/netem"
+)
+
+// peerPollInterval is the delay between peer connection checks.
+const peerPollInterval = 50 * time.Millisecond
+
+// link identifies the connection between two nodes by their indices, lower index first.
+type link [2]int
+
+// newLink returns the link between the nodes at indices a and b.
+func newLink(a, b int) link {
+	return link{min(a, b), max(a, b)}
+}
+
+// Disconnect drops the connection between the nodes at indices a and b and
+// keeps them from reconnecting until Connect or Heal is called. Nodes run
+// without discovery and only dial peers the Manager adds, so the link stays
+// cut. Heal only restores the link if the nodes were connected, as with
+// Partition. Returns an error if either node is invalid or stopped, or a == b.
+func (m *Manager) Disconnect(a, b int) error {
+	if a == b {
+		return fmt.Errorf("cannot disconnect node %d from itself", a)
+	}
+	nodeA, err := m.runningNode(a)
+	if err != nil {
+		return err
+	}
+	nodeB, err := m.runningNode(b)
+	if err != nil {
+		return err
+	}
+	wasConnected := connected(nodeA, nodeB.Server().Self().ID())
+	if err := m.disconnectNodes(a, b); err != nil {
+		return err
+	}
+
+	if wasConnected {
+		m.mu.Lock()
+		m.cutLinks[newLink(a, b)] = struct{}{}
+		m.mu.Unlock()
+	}
+
+	m.logger.Info().Int("node_a", a).Int("node_b", b).Msg("nodes disconnected")
+	return nil
+}
+
+// Connect connects the nodes at indices a and b as static and trusted peers of
+// each other and blocks until the connection is up or OperationTimeout elapses.
+// Returns an error if either node is invalid or stopped, a == b, or the nodes
+// do not connect.
+func (m *Manager) Connect(a, b int) error {
+	if a == b {
+		return fmt.Errorf("cannot connect node %d to itself", a)
+	}
+	if err := m.connectNodes(context.Background(), a, b); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	delete(m.cutLinks, newLink(a, b))
+	m.mu.Unlock()
+
+	m.logger.Info().Int("node_a", a).Int("node_b", b).Msg("nodes connected")
+	return nil
+}
+
+// Partition splits the running nodes into groups that cannot reach each other.
+// Every connection between nodes of different groups is dropped, and the nodes
+// of each group are connected to each other, so transactions still propagate
+// within a group. Every running node must be in exactly one group. Heal
+// restores the dropped connections.
+func (m *Manager) Partition(groups ...[]int) error {
+	groupOf := make(map[int]int)
+	for g, group := range groups {
+		for _, index := range group {
+			if _, err := m.runningNode(index); err != nil {
+				return err
+			}
+			if other, ok := groupOf[index]; ok {
+				return fmt.Errorf("node %d is in groups %d and %d", index, other, g)
+			}
+			groupOf[index] = g
+		}
+	}
+
+	m.mu.RLock()
+	nodeCount := len(m.nodes)
+	running := make([]int, 0, nodeCount)
+	for i := 0; i < nodeCount; i++ {
+		if _, ok := m.stopped[i]; !ok {
+			running = append(running, i)
+		}
+	}
+	m.mu.RUnlock()
+	for _, index := range running {
+		if _, ok := groupOf[index]; !ok {
+			return fmt.Errorf("node %d is not in any group", index)
+		}
+	}
+
+	for i, a := range running {
+		for _, b := range running[i+1:] {
+			if groupOf[a] == groupOf[b] {
+				if err := m.connectNodes(context.Background(), b, a); err != nil {
+					return fmt.Errorf("connect group %d: %w", groupOf[a], err)
+				}
+				continue
+			}
+
+			nodeA, _ := m.runningNode(a)
+			nodeB, _ := m.runningNode(b)
+			wasConnected := connected(nodeA, nodeB.Server().Self().ID())
+			if err := m.disconnectNodes(a, b); err != nil {
+				return err
+			}
+			if wasConnected {
+				m.mu.Lock()
+				m.cutLinks[newLink(a, b)] = struct{}{}
+				m.mu.Unlock()
+			}
+		}
+	}
+
+	m.logger.Info().Interface("groups", groups).Msg("network partitioned")
+	return nil
+}
+
+// Heal reconnects every pair of nodes disconnected by Partition or Disconnect,
+// skipping stopped nodes, and blocks until the connections are up.
+// Returns an error if a pair does not reconnect within OperationTimeout.
+func (m *Manager) Heal() error {
+	m.mu.RLock()
+	cut := make([]link, 0, len(m.cutLinks))
+	for l := range m.cutLinks {
+		cut = append(cut, l)
+	}
+	m.mu.RUnlock()
+
+	for _, l := range cut {
+		_, errA := m.runningNode(l[0])
+		_, errB := m.runningNode(l[1])
+		if errA != nil || errB != nil {
+			continue
+		}
+		// Dial from the higher index, as Start does.
+		if err := m.Connect(l[1], l[0]); err != nil {
+			return fmt.Errorf("heal: %w", err)
+		}
+	}
+
+	m.logger.Info().Int("links", len(cut)).Msg("network healed")
+	return nil
+}
+
+// SetLinkConditions emulates the given network conditions on the link between
+// the nodes at indices a and b, e.g. to measure transaction propagation over
+// a slow or lossy WAN. The first call routes the link through an in-process
+// netem.Relay in front of the P2P port of the node with the lower index;
+// later calls update the relay's conditions in place. If the nodes are
+// connected, they are reconnected through the relay, and links restored by
+// Connect or Heal keep using it until ClearLinkConditions is called.
+// The Manager closes the relays on shutdown.
+//
+// Conditions apply to the link only; traffic to other nodes is not affected,
+// so a partial mesh is needed to force propagation over a shaped link.
+//
+// Returns an error if either node is invalid or stopped, a == b, the
+// conditions are invalid, or the nodes do not reconnect. All errors are CRITICAL.
+func (m *Manager) SetLinkConditions(a, b int, conditions netem.Conditions) error {
+	if a == b {
+		return fmt.Errorf("cannot shape the link of node %d to itself", a)
+	}
+	if err := conditions.Validate(); err != nil {
+		return err
+	}
+	nodeA, err := m.runningNode(a)
+	if err != nil {
+		return err
+	}
+	nodeB, err := m.runningNode(b)
+	if err != nil {
+		return err
+	}
+
+	l := newLink(a, b)
+	m.mu.Lock()
+	if relay, ok := m.shapedLinks[l]; ok {
+		m.mu.Unlock()
+		if err := relay.SetConditions(conditions); err != nil {
+			return err
+		}
+		m.logger.Info().Int("node_a", a).Int("node_b", b).Interface("conditions", conditions).Msg("link conditions updated")
+		return nil
+	}
+	listenAddr := fmt.Sprintf("127.0.0.1:%d", m.assignNewPort())
+	upstream := fmt.Sprintf("127.0.0.1:%d", m.configs[l[0]].P2PPort)
+	relay, err := netem.NewRelay(m.logger, listenAddr, upstream, conditions)
+	if err != nil {
+		m.mu.Unlock()
+		return fmt.Errorf("link relay between node %d and node %d: %w", a, b, err)
+	}
+	m.shapedLinks[l] = relay
+	m.mu.Unlock()
+
+	if err := m.reroute(l, connected(nodeA, nodeB.Server().Self().ID())); err != nil {
+		return err
+	}
+	m.logger.Info().Int("node_a", a).Int("node_b", b).Interface("conditions", conditions).Msg("link conditions set")
+	return nil
+}
+
+// ClearLinkConditions removes the relay SetLinkConditions placed on the link
+// between the nodes at indices a and b and reconnects the nodes directly if
+// they were connected. It is a no-op if the link is not shaped.
+// Returns an error if either node is invalid or stopped, or the nodes do not
+// reconnect. All errors are CRITICAL.
+func (m *Manager) ClearLinkConditions(a, b int) error {
+	nodeA, err := m.runningNode(a)
+	if err != nil {
+		return err
+	}
+	nodeB, err := m.runningNode(b)
+	if err != nil {
+		return err
+	}
+
+	l := newLink(a, b)
+	m.mu.Lock()
+	relay, ok := m.shapedLinks[l]
+	delete(m.shapedLinks, l)
+	m.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	wasConnected := connected(nodeA, nodeB.Server().Self().ID())
+	if err := relay.Close(); err != nil {
+		return fmt.Errorf("close link relay between node %d and node %d: %w", a, b, err)
+	}
+	if err := m.reroute(l, wasConnected); err != nil {
+		return err
+	}
+	m.logger.Info().Int("node_a", a).Int("node_b", b).Msg("link conditions cleared")
+	return nil
+}
+
+// reroute drops the connection of link l, which also clears the static peer
+// entry pointing at its old route, and reconnects the nodes over the current
+// route if reconnect is set.
+func (m *Manager) reroute(l link, reconnect bool) error {
+	if err := m.disconnectNodes(l[0], l[1]); err != nil {
+		return err
+	}
+	if !reconnect {
+		return nil
+	}
+	return m.connectNodes(context.Background(), l[1], l[0])
+}
+
+// ConnectedPeers returns the indices of the nodes the node at the given index
+// is connected to, in ascending order. Returns an error if the node is invalid or stopped.
+func (m *Manager) ConnectedPeers(index int) ([]int, error) {
+	n, err := m.runningNode(index)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	nodes := make([]*gethnode.Node, len(m.nodes))
+	copy(nodes, m.nodes)
+	stopped := make(map[int]struct{}, len(m.stopped))
+	for i := range m.stopped {
+		stopped[i] = struct{}{}
+	}
+	m.mu.RUnlock()
+
+	peers := make([]int, 0)
+	for i, other := range nodes {
+		if _, ok := stopped[i]; ok || i == index {
+			continue
+		}
+		if connected(n, other.Server().Self().ID()) {
+			peers = append(peers, i)
+		}
+	}
+	return peers, nil
+}
+
+// runningNode returns the node at the given index, or an error if the index is
+// invalid or the node is stopped.
+func (m *Manager) runningNode(index int) (*gethnode.Node, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if index < 0 || index >= len(m.nodes) {
+		return nil, fmt.Errorf("node index %d out of range [0, %d)", index, len(m.nodes))
+	}
+	if _, ok := m.stopped[index]; ok {
+		return nil, fmt.Errorf("node %d is stopped", index)
+	}
+	return m.nodes[index], nil
+}
+
+// disconnectNodes drops the connection between the nodes at indices a and b and
+// removes them from each other's static and trusted peers, so neither side
+// redials. Blocks until both sides have dropped the connection.
+func (m *Manager) disconnectNodes(a, b int) error {
+	nodeA, err := m.runningNode(a)
+	if err != nil {
+		return err
+	}
+	nodeB, err := m.runningNode(b)
+	if err != nil {
+		return err
+	}
+
+	nodeA.Server().RemoveTrustedPeer(nodeB.Server().Self())
+	nodeB.Server().RemoveTrustedPeer(nodeA.Server().Self())
+	// RemovePeer blocks until the peer is gone from the local peer set.
+	nodeA.Server().RemovePeer(nodeB.Server().Self())
+	nodeB.Server().RemovePeer(nodeA.Server().Self())
+	return nil
+}
+
+// connectNodes adds the node at index b as a static peer of the node at index a,
+// makes both trusted peers of each other so peer limits never refuse the
+// connection, and blocks until they are connected, ctx is done or
+// OperationTimeout elapses. If the link is shaped (see SetLinkConditions), the
+// node of the higher index dials through the link's relay instead.
+//
+// The connection is opened directly rather than left to the dial scheduler,
+// which refuses to redial a node for about 35 seconds after the last dial.
+func (m *Manager) connectNodes(ctx context.Context, a, b int) error {
+	m.mu.RLock()
+	relay, shaped := m.shapedLinks[newLink(a, b)]
+	m.mu.RUnlock()
+	if shaped && a < b {
+		// The relay forwards to the node of the lower index.
+		a, b = b, a
+	}
+
+	nodeA, err := m.runningNode(a)
+	if err != nil {
+		return err
+	}
+	nodeB, err := m.runningNode(b)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, OperationTimeout)
+	defer cancel()
+
+	m.mu.RLock()
+	dest := nodeB.Server().Self()
+	addr := fmt.Sprintf("127.0.0.1:%d", m.configs[b].P2PPort)
+	m.mu.RUnlock()
+	if shaped {
+		// Static peers are keyed by ID, so the record pointing at the relay
+		// replaces any direct one and redials go through the relay as well.
+		dest = enode.NewV4(dest.Pubkey(), relay.Addr().IP, relay.Addr().Port, 0)
+		addr = relay.Addr().String()
+	}
+
+	nodeA.Server().AddTrustedPeer(dest)
+	nodeB.Server().AddTrustedPeer(nodeA.Server().Self())
+	nodeA.Server().AddPeer(dest)
+
+	if !connected(nodeA, dest.ID()) {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return fmt.Errorf("dial node %d from node %d: %w", b, a, err)
+		}
+		// A concurrent static dial may win the race, so a failed handshake is
+		// only an error if the nodes end up unconnected.
+		_ = nodeA.Server().SetupConn(conn, 0, dest)
+	}
+
+	ticker := time.NewTicker(peerPollInterval)
+	defer ticker.Stop()
+	for !connected(nodeA, dest.ID()) {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("connect node %d to node %d: %w", a, b, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// connected reports whether n has an open connection to the node with the given ID.
+func connected(n *gethnode.Node, id enode.ID) bool {
+	for _, p := range n.Server().Peers() {
+		if p.ID() == id {
+			return true
+		}
+	}
+	return false
+}
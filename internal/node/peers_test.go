@@ -0,0 +1,170 @@
+package node_test
+
+import (
+	"context"
+	"math/big"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+	"github.com/thep2p/go-eth-localnet/internal/model"
+	"github.com/thep2p/go-eth-localnet/internal/netem"
+	"github.com/thep2p/go-eth-localnet/internal/node"
+	"github.com/thep2p/go-eth-localnet/internal/unittest"
+	"github.com/thep2p/go-eth-localnet/internal/utils"
+)
+
+// requirePeers fails unless the node at the given index is connected to exactly the expected nodes.
+func requirePeers(t *testing.T, manager *node.Manager, index int, expected ...int) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		peers, err := manager.ConnectedPeers(index)
+		require.NoError(t, err)
+		return slices.Equal(peers, expected)
+	}, node.OperationTimeout, 50*time.Millisecond, "node %d should be connected to %v", index, expected)
+}
+
+// hasTransaction reports whether the node at the given index knows the transaction, pending or mined.
+func hasTransaction(t *testing.T, ctx context.Context, manager *node.Manager, index int, txHash common.Hash) bool {
+	t.Helper()
+
+	client, err := rpc.DialContext(ctx, utils.LocalAddress(manager.GetRPCPort(index)))
+	require.NoError(t, err)
+	defer client.Close()
+
+	var tx map[string]interface{}
+	// Geth reports an error rather than no transaction while its transaction index is still being built.
+	if err := client.CallContext(ctx, &tx, model.EthGetTransactionByHash, txHash); err != nil {
+		return false
+	}
+	return tx != nil
+}
+
+// markSynced sends each node a forkchoice update for its current head. Geth
+// drops transactions gossiped by peers until its consensus client has done so.
+func markSynced(t *testing.T, ctx context.Context, manager *node.Manager) {
+	t.Helper()
+
+	for i := 0; i < manager.NodeCount(); i++ {
+		driver, err := manager.NewEngineDriver(ctx, i)
+		require.NoError(t, err)
+		require.NoError(t, driver.UpdateForkchoice(ctx, driver.Forkchoice()))
+		driver.Close()
+	}
+}
+
+// TestPartitionAndHeal verifies transactions only propagate within a partition
+// and reach the other side once the network is healed.
+func TestPartitionAndHeal(t *testing.T) {
+	key := unittest.PrivateKeyFixture(t)
+	ctx, manager := unittest.StartExternalNodes(
+		t, 4,
+		node.WithPreFundGenesisAccount(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(params.Ether)),
+	)
+	requirePeers(t, manager, 0, 1, 2, 3)
+	markSynced(t, ctx, manager)
+
+	require.NoError(t, manager.Partition([]int{0, 1}, []int{2, 3}))
+	requirePeers(t, manager, 0, 1)
+	requirePeers(t, manager, 1, 0)
+	requirePeers(t, manager, 2, 3)
+	requirePeers(t, manager, 3, 2)
+
+	txHash := sendTransfer(t, ctx, manager, 2, key)
+	require.Eventually(t, func() bool {
+		return hasTransaction(t, ctx, manager, 3, txHash)
+	}, node.OperationTimeout, 100*time.Millisecond, "transaction should propagate within the partition")
+	time.Sleep(time.Second)
+	require.False(t, hasTransaction(t, ctx, manager, 0, txHash))
+	require.False(t, hasTransaction(t, ctx, manager, 1, txHash))
+
+	require.NoError(t, manager.Heal())
+	requirePeers(t, manager, 0, 1, 2, 3)
+	require.Eventually(t, func() bool {
+		return hasTransaction(t, ctx, manager, 0, txHash) && hasTransaction(t, ctx, manager, 1, txHash)
+	}, node.OperationTimeout, 100*time.Millisecond, "transaction should propagate after healing")
+}
+
+// TestDisconnectAndConnect verifies single links can be cut and restored
+// repeatedly without waiting for the dial throttle.
+func TestDisconnectAndConnect(t *testing.T) {
+	_, manager := unittest.StartExternalNodes(t, 3)
+	requirePeers(t, manager, 1, 0)
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, manager.Disconnect(0, 1))
+		requirePeers(t, manager, 1)
+		requirePeers(t, manager, 0, 2)
+
+		require.NoError(t, manager.Connect(1, 0))
+		requirePeers(t, manager, 1, 0)
+	}
+
+	// Nodes 1 and 2 were never linked, so Heal does not link them.
+	require.NoError(t, manager.Disconnect(1, 2))
+	require.NoError(t, manager.Heal())
+	requirePeers(t, manager, 1, 0)
+	requirePeers(t, manager, 2, 0)
+
+	// Connect them directly.
+	require.NoError(t, manager.Connect(1, 2))
+	requirePeers(t, manager, 2, 0, 1)
+
+	require.NoError(t, manager.Disconnect(1, 2))
+	require.NoError(t, manager.Heal())
+	requirePeers(t, manager, 2, 0, 1)
+
+	require.ErrorContains(t, manager.Disconnect(1, 1), "itself")
+	require.ErrorContains(t, manager.Connect(0, 7), "out of range")
+}
+
+// TestPartitionRequiresEveryNode verifies partitions must place every running node in exactly one group.
+func TestPartitionRequiresEveryNode(t *testing.T) {
+	_, manager := unittest.StartExternalNodes(t, 3)
+
+	require.ErrorContains(t, manager.Partition([]int{0}, []int{1}), "node 2 is not in any group")
+	require.ErrorContains(t, manager.Partition([]int{0, 1}, []int{1, 2}), "node 1 is in groups 0 and 1")
+}
+
+// TestLinkConditionsDelayPropagation verifies a shaped link delays transaction
+// propagation and keeps the nodes connected while conditions change.
+func TestLinkConditionsDelayPropagation(t *testing.T) {
+	key := unittest.PrivateKeyFixture(t)
+	ctx, manager := unittest.StartExternalNodes(
+		t, 2,
+		node.WithPreFundGenesisAccount(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(params.Ether)),
+	)
+	requirePeers(t, manager, 0, 1)
+	markSynced(t, ctx, manager)
+
+	latency := 750 * time.Millisecond
+	require.NoError(t, manager.SetLinkConditions(0, 1, netem.Conditions{Latency: latency}))
+	requirePeers(t, manager, 0, 1)
+	requirePeers(t, manager, 1, 0)
+
+	start := time.Now()
+	txHash := sendTransfer(t, ctx, manager, 1, key)
+	require.Eventually(t, func() bool {
+		return hasTransaction(t, ctx, manager, 0, txHash)
+	}, node.OperationTimeout, 20*time.Millisecond, "transaction should propagate over the shaped link")
+	require.GreaterOrEqual(t, time.Since(start), latency)
+
+	// Links restored after a disconnect keep their conditions.
+	require.NoError(t, manager.Disconnect(0, 1))
+	require.NoError(t, manager.SetLinkConditions(1, 0, netem.Conditions{Latency: latency, Bandwidth: 1 << 20}))
+	require.NoError(t, manager.Heal())
+	requirePeers(t, manager, 0, 1)
+
+	require.NoError(t, manager.ClearLinkConditions(0, 1))
+	requirePeers(t, manager, 0, 1)
+	require.NoError(t, manager.ClearLinkConditions(0, 1), "clearing an unshaped link should be a no-op")
+
+	require.ErrorContains(t, manager.SetLinkConditions(0, 1, netem.Conditions{LossRate: 2}), "loss rate")
+	require.ErrorContains(t, manager.SetLinkConditions(1, 1, netem.Conditions{}), "itself")
+}
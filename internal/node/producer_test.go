@@ -0,0 +1,152 @@
+package node_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+	"github.com/thep2p/go-eth-localnet/internal/model"
+	"github.com/thep2p/go-eth-localnet/internal/node"
+	"github.com/thep2p/go-eth-localnet/internal/unittest"
+	"github.com/thep2p/go-eth-localnet/internal/utils"
+)
+
+// blockNumber returns the latest block number of the node at the given index.
+func blockNumber(t *testing.T, ctx context.Context, manager *node.Manager, index int) uint64 {
+	t.Helper()
+
+	client, err := rpc.DialContext(ctx, utils.LocalAddress(manager.GetRPCPort(index)))
+	require.NoError(t, err)
+	defer client.Close()
+
+	var number string
+	require.NoError(t, client.CallContext(ctx, &number, model.EthBlockNumber))
+	return unittest.HexToBigInt(t, number).Uint64()
+}
+
+// requireProducing fails unless the node at the given index produces a new block within the timeout.
+func requireProducing(t *testing.T, ctx context.Context, manager *node.Manager, index int) {
+	t.Helper()
+
+	start := blockNumber(t, ctx, manager, index)
+	require.Eventually(t, func() bool {
+		return blockNumber(t, ctx, manager, index) > start
+	}, node.OperationTimeout, 100*time.Millisecond, "node %d should produce blocks", index)
+}
+
+// requireNotProducing fails if the node at the given index produces a block during the wait.
+func requireNotProducing(t *testing.T, ctx context.Context, manager *node.Manager, index int) {
+	t.Helper()
+
+	start := blockNumber(t, ctx, manager, index)
+	time.Sleep(2500 * time.Millisecond)
+	require.Equal(t, start, blockNumber(t, ctx, manager, index), "node %d should not produce blocks", index)
+}
+
+// TestSingleBlockProducer verifies the Manager rejects a second miner and
+// reports the node producing blocks.
+func TestSingleBlockProducer(t *testing.T) {
+	ctx, cancel, manager := startNodes(t, 2)
+	defer cancel()
+
+	producer, ok := manager.BlockProducer()
+	require.True(t, ok)
+	require.Equal(t, 0, producer)
+
+	err := manager.StartNode(ctx, true, nil)
+	require.ErrorContains(t, err, "node 0 is already the block producer")
+	require.Equal(t, 2, manager.NodeCount())
+
+	// Adding nodes with Start does not add another miner.
+	require.NoError(t, manager.Start(ctx, 1))
+	producer, ok = manager.BlockProducer()
+	require.True(t, ok)
+	require.Equal(t, 0, producer)
+	requireNotProducing(t, ctx, manager, 2)
+}
+
+// TestHandOverBlockProducer verifies the producer role moves between running
+// nodes and can be assigned again after the producer is stopped.
+func TestHandOverBlockProducer(t *testing.T) {
+	ctx, cancel, manager := startNodes(t, 3)
+	defer cancel()
+	requireProducing(t, ctx, manager, 0)
+
+	require.NoError(t, manager.SetBlockProducer(ctx, 1))
+	producer, ok := manager.BlockProducer()
+	require.True(t, ok)
+	require.Equal(t, 1, producer)
+	requireProducing(t, ctx, manager, 1)
+	requireNotProducing(t, ctx, manager, 0)
+
+	require.NoError(t, manager.StopNode(1))
+	_, ok = manager.BlockProducer()
+	require.False(t, ok)
+	require.ErrorContains(t, manager.StopNode(1), "already stopped")
+	require.ErrorContains(t, manager.SetBlockProducer(ctx, 1), "stopped")
+
+	require.NoError(t, manager.SetBlockProducer(ctx, 2))
+	producer, ok = manager.BlockProducer()
+	require.True(t, ok)
+	require.Equal(t, 2, producer)
+	requireProducing(t, ctx, manager, 2)
+}
+
+// TestSetBlockProducerRestartFailure verifies a producer that cannot be
+// launched again after it was closed is recorded as stopped, so no block
+// producer is reported rather than a node that is not running.
+func TestSetBlockProducerRestartFailure(t *testing.T) {
+	ctx, cancel, manager := startNodesWithEngineAPI(t, 2)
+	defer cancel()
+	producer, ok := manager.BlockProducer()
+	require.True(t, ok)
+	require.Equal(t, 0, producer)
+
+	// Geth reads the JWT secret on startup only, so an invalid one makes
+	// relaunching node 0 fail without disturbing it while it runs.
+	require.NoError(t, os.WriteFile(manager.GetJWTSecretPath(0), []byte("invalid"), 0600))
+
+	require.ErrorContains(t, manager.SetBlockProducer(ctx, 1), "demote block producer 0")
+	_, ok = manager.BlockProducer()
+	require.False(t, ok)
+	require.ErrorContains(t, manager.StopNode(0), "already stopped")
+	require.ErrorContains(t, manager.SetBlockProducer(ctx, 0), "stopped")
+
+	// The role can be handed to a running node.
+	require.NoError(t, manager.SetBlockProducer(ctx, 1))
+	producer, ok = manager.BlockProducer()
+	require.True(t, ok)
+	require.Equal(t, 1, producer)
+}
+
+// TestStartNodeSingleProducer verifies concurrent starts of mining nodes
+// start only one block producer.
+func TestStartNodeSingleProducer(t *testing.T) {
+	ctx, cancel, manager := startNodes(t, 1)
+	defer cancel()
+	require.NoError(t, manager.StopNode(0))
+
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			errs <- manager.StartNode(ctx, true, nil)
+		}()
+	}
+	var started int
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err == nil {
+			started++
+		} else {
+			require.ErrorContains(t, err, "block producer")
+		}
+	}
+	require.Equal(t, 1, started)
+
+	producer, ok := manager.BlockProducer()
+	require.True(t, ok)
+	require.Equal(t, 1, producer)
+	requireProducing(t, ctx, manager, producer)
+}
@@ -0,0 +1,85 @@
+package node_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/thep2p/go-eth-localnet/internal/node"
+	"github.com/thep2p/go-eth-localnet/internal/unittest"
+)
+
+// newManager returns a Manager whose nodes are shut down and whose data
+// directory is removed when the test finishes.
+func newManager(t *testing.T) *node.Manager {
+	t.Helper()
+
+	tmp := unittest.NewTempDir(t)
+	launcher := node.NewLauncher(unittest.Logger(t))
+	manager := node.NewNodeManager(
+		unittest.Logger(t), launcher, tmp.Path(), func() int {
+			return unittest.NewPort(t)
+		},
+	)
+	t.Cleanup(tmp.Remove)
+	return manager
+}
+
+// TestStartWaitsForPairReady verifies Start blocks until the paired beacon node
+// reports ready, and only then returns.
+func TestStartWaitsForPairReady(t *testing.T) {
+	manager := newManager(t)
+	beacon := unittest.NewFakeBeacon(t)
+	beacon.SetSyncing(true)
+	require.NoError(t, manager.SetBeaconEndpoint(0, beacon.URL()))
+	require.Equal(t, beacon.URL(), manager.GetBeaconEndpoint(0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	t.Cleanup(func() {
+		unittest.RequireCallMustReturnWithinTimeout(t, manager.Done, node.ShutdownTimeout, "node shutdown failed")
+	})
+
+	becameReady := time.Now().Add(time.Second)
+	go func() {
+		time.Sleep(time.Until(becameReady))
+		beacon.SetSyncing(false)
+	}()
+
+	require.NoError(t, manager.Start(ctx, 1))
+	require.False(t, time.Now().Before(becameReady), "start returned before the beacon node was ready")
+
+	// Once ready, the pair stays ready.
+	require.NoError(t, manager.WaitForPairReady(ctx, 0))
+}
+
+// TestStartFailsWhenPairNotReady verifies Start returns an error describing the
+// beacon node when the pair never becomes ready.
+func TestStartFailsWhenPairNotReady(t *testing.T) {
+	manager := newManager(t)
+	beacon := unittest.NewFakeBeacon(t)
+	beacon.SetELOffline(true)
+	require.NoError(t, manager.SetBeaconEndpoint(0, beacon.URL()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	t.Cleanup(func() {
+		unittest.RequireCallMustReturnWithinTimeout(t, manager.Done, node.ShutdownTimeout, "node shutdown failed")
+	})
+
+	err := manager.Start(ctx, 1)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "execution layer offline")
+}
+
+// TestWaitForPairReady_Validation verifies pair checks require a started node with a beacon endpoint.
+func TestWaitForPairReady_Validation(t *testing.T) {
+	ctx, cancel, manager := startNodes(t, 1)
+	defer cancel()
+
+	require.ErrorContains(t, manager.WaitForPairReady(ctx, 0), "no beacon endpoint")
+	require.ErrorContains(t, manager.WaitForPairReady(ctx, 5), "out of range")
+	require.Error(t, manager.SetBeaconEndpoint(-1, "http://127.0.0.1:4000"))
+	require.Error(t, manager.SetBeaconEndpoint(0, ""))
+}
@@ -0,0 +1,218 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	gethengine "github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/thep2p/go-eth-localnet/internal/engine"
+)
+
+// ReorgOptions configures SimulateReorg.
+type ReorgOptions struct {
+	// Producers are the indices of the two nodes that build competing branches.
+	// Both must be at the same head when SimulateReorg is called.
+	Producers [2]int
+	// Depths are the number of blocks each producer builds on the common head.
+	Depths [2]int
+	// Winner is the position in Producers (0 or 1) of the branch made canonical
+	// on both nodes once the partition is healed.
+	Winner int
+	// BeforeBlock is called before a producer builds each block of its branch,
+	// e.g. to submit transactions to that node. Height counts from 0 within the
+	// branch. Optional.
+	BeforeBlock func(ctx context.Context, nodeIndex int, height int) error
+}
+
+// ReorgReport describes the reorg performed by SimulateReorg.
+type ReorgReport struct {
+	// CommonAncestor is the head both branches were built on.
+	CommonAncestor common.Hash
+	// CommonAncestorNumber is the block number of CommonAncestor.
+	CommonAncestorNumber uint64
+	// Heads are the tips of the branches, in the order of ReorgOptions.Producers.
+	Heads [2]common.Hash
+	// CanonicalHead is the tip of the winning branch, now the head of both nodes.
+	CanonicalHead common.Hash
+	// Depth is the number of blocks reverted on the node that followed the losing branch.
+	Depth int
+	// DroppedTxs are the transactions of the losing branch not included in the
+	// winning branch, in block order. Geth returns them to the pool of the
+	// node that followed the losing branch.
+	DroppedTxs []common.Hash
+}
+
+// SimulateReorg builds two competing branches and forces one of them to become
+// canonical, so the node that followed the other branch reorgs.
+//
+// The second producer is disconnected from every peer, so transactions sent to
+// either producer cannot reach the other, not even relayed by a third node.
+// Each producer builds its branch over the Engine API, then the winning branch
+// is imported into the losing node, forkchoice on both nodes is set to the
+// winning head and the connections the second producer had when the call
+// started are restored; links cut before the call stay cut. Other nodes keep
+// their forkchoice. Requires EnableExternalConsensus, since nothing else may
+// drive block production of the producers meanwhile.
+//
+// Returns a report of the reorg. All errors are CRITICAL; the nodes may be left
+// partitioned or on different heads.
+func (m *Manager) SimulateReorg(ctx context.Context, opts ReorgOptions) (*ReorgReport, error) {
+	m.mu.RLock()
+	externalConsensus := m.externalConsensus
+	m.mu.RUnlock()
+	if !externalConsensus {
+		return nil, fmt.Errorf("reorg simulation requires external consensus")
+	}
+	if opts.Producers[0] == opts.Producers[1] {
+		return nil, fmt.Errorf("reorg simulation requires two distinct producers, got %d twice", opts.Producers[0])
+	}
+	if opts.Winner != 0 && opts.Winner != 1 {
+		return nil, fmt.Errorf("winner must be 0 or 1, got %d", opts.Winner)
+	}
+	if opts.Depths[0] < 0 || opts.Depths[1] < 0 || opts.Depths[opts.Winner] == 0 {
+		return nil, fmt.Errorf("branch depths must not be negative and the winning branch must not be empty, got %v", opts.Depths)
+	}
+
+	var drivers [2]*engine.Driver
+	for i, index := range opts.Producers {
+		driver, err := m.NewEngineDriver(ctx, index)
+		if err != nil {
+			return nil, err
+		}
+		defer driver.Close()
+		drivers[i] = driver
+	}
+
+	base := drivers[0].Forkchoice()
+	if other := drivers[1].Forkchoice(); other.HeadBlockHash != base.HeadBlockHash {
+		return nil, fmt.Errorf(
+			"producers must start on the same head: node %d at %s, node %d at %s",
+			opts.Producers[0], base.HeadBlockHash.Hex(), opts.Producers[1], other.HeadBlockHash.Hex(),
+		)
+	}
+
+	// Every running node is disconnected, not only the current peers, so a
+	// connection still being dialed cannot come up during the reorg.
+	isolated := opts.Producers[1]
+	peers, err := m.ConnectedPeers(isolated)
+	if err != nil {
+		return nil, fmt.Errorf("partition producers: %w", err)
+	}
+	m.mu.RLock()
+	nodeCount := len(m.nodes)
+	m.mu.RUnlock()
+	for index := 0; index < nodeCount; index++ {
+		if index == isolated {
+			continue
+		}
+		if _, err := m.runningNode(index); err != nil {
+			continue
+		}
+		if err := m.disconnectNodes(isolated, index); err != nil {
+			return nil, fmt.Errorf("partition producers: %w", err)
+		}
+	}
+
+	var branches [2][]*engine.Payload
+	var baseNumber uint64
+	for i, index := range opts.Producers {
+		for height := 0; height < opts.Depths[i]; height++ {
+			if opts.BeforeBlock != nil {
+				if err := opts.BeforeBlock(ctx, index, height); err != nil {
+					return nil, fmt.Errorf("before block %d of node %d: %w", height, index, err)
+				}
+			}
+			// A distinct randomness mix keeps branches apart even when their blocks
+			// are otherwise identical, e.g. empty and built at the same time.
+			payload, err := drivers[i].ProduceBlock(ctx, engine.BuildOptions{
+				PrevRandao: common.BigToHash(big.NewInt(int64(i + 1))),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("build block %d of node %d: %w", height, index, err)
+			}
+			if height == 0 {
+				baseNumber = payload.ExecutionPayload.Number - 1
+			}
+			branches[i] = append(branches[i], payload)
+		}
+	}
+
+	winner, loser := opts.Winner, 1-opts.Winner
+	for _, payload := range branches[winner] {
+		if err := drivers[loser].ImportPayload(ctx, payload); err != nil {
+			return nil, fmt.Errorf("import winning branch into node %d: %w", opts.Producers[loser], err)
+		}
+	}
+
+	report := &ReorgReport{
+		CommonAncestor:       base.HeadBlockHash,
+		CommonAncestorNumber: baseNumber,
+		Heads:                [2]common.Hash{tip(base.HeadBlockHash, branches[0]), tip(base.HeadBlockHash, branches[1])},
+		Depth:                len(branches[loser]),
+		DroppedTxs:           droppedTxs(branches[winner], branches[loser]),
+	}
+	report.CanonicalHead = report.Heads[winner]
+
+	state := gethengine.ForkchoiceStateV1{
+		HeadBlockHash:      report.CanonicalHead,
+		SafeBlockHash:      report.CanonicalHead,
+		FinalizedBlockHash: base.FinalizedBlockHash,
+	}
+	for i, driver := range drivers {
+		if err := driver.UpdateForkchoice(ctx, state); err != nil {
+			return nil, fmt.Errorf("set forkchoice of node %d: %w", opts.Producers[i], err)
+		}
+	}
+
+	for _, peer := range peers {
+		if err := m.connectNodes(ctx, max(isolated, peer), min(isolated, peer)); err != nil {
+			return nil, fmt.Errorf("heal partition: %w", err)
+		}
+	}
+
+	m.logger.Info().
+		Int("winner", opts.Producers[winner]).
+		Int("depth", report.Depth).
+		Int("dropped_txs", len(report.DroppedTxs)).
+		Str("head", report.CanonicalHead.Hex()).
+		Msg("reorg simulated")
+	return report, nil
+}
+
+// tip returns the hash of the last payload of a branch, or base if the branch is empty.
+func tip(base common.Hash, branch []*engine.Payload) common.Hash {
+	if len(branch) == 0 {
+		return base
+	}
+	return branch[len(branch)-1].ExecutionPayload.BlockHash
+}
+
+// droppedTxs returns the hashes of transactions in the losing branch that are
+// not included in the winning branch.
+func droppedTxs(winning, losing []*engine.Payload) []common.Hash {
+	included := make(map[common.Hash]struct{})
+	for _, payload := range winning {
+		for _, raw := range payload.ExecutionPayload.Transactions {
+			included[txHash(raw)] = struct{}{}
+		}
+	}
+
+	dropped := make([]common.Hash, 0)
+	for _, payload := range losing {
+		for _, raw := range payload.ExecutionPayload.Transactions {
+			hash := txHash(raw)
+			if _, ok := included[hash]; !ok {
+				dropped = append(dropped, hash)
+			}
+		}
+	}
+	return dropped
+}
+
+// txHash returns the hash of a binary-encoded transaction of an execution payload.
+func txHash(raw []byte) common.Hash {
+	return crypto.Keccak256Hash(raw)
+}
@@ -0,0 +1,167 @@
+package node_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+	"github.com/thep2p/go-eth-localnet/internal/model"
+	"github.com/thep2p/go-eth-localnet/internal/node"
+	"github.com/thep2p/go-eth-localnet/internal/unittest"
+	"github.com/thep2p/go-eth-localnet/internal/utils"
+)
+
+// sendTransfer signs a 1 wei transfer with nonce 0 from key and sends it to the node at the given index.
+func sendTransfer(
+	t *testing.T,
+	ctx context.Context,
+	manager *node.Manager,
+	index int,
+	key *ecdsa.PrivateKey,
+) common.Hash {
+	t.Helper()
+
+	to := unittest.RandomAddress(t)
+	tx, err := types.SignTx(
+		types.NewTx(&types.DynamicFeeTx{
+			ChainID:   manager.ChainID(),
+			Gas:       21_000,
+			GasTipCap: big.NewInt(params.GWei),
+			GasFeeCap: big.NewInt(2 * params.GWei),
+			To:        &to,
+			Value:     big.NewInt(1),
+		}),
+		types.LatestSignerForChainID(manager.ChainID()), key,
+	)
+	require.NoError(t, err)
+	txBytes, err := tx.MarshalBinary()
+	require.NoError(t, err)
+
+	client, err := rpc.DialContext(ctx, utils.LocalAddress(manager.GetRPCPort(index)))
+	require.NoError(t, err)
+	defer client.Close()
+
+	var txHash common.Hash
+	require.NoError(t, client.CallContext(ctx, &txHash, model.EthSendRawTransaction, utils.ByteToHex(txBytes)))
+	return txHash
+}
+
+// TestSimulateReorg verifies the losing node switches to the winning branch,
+// and that the report carries the reorg depth and the dropped transactions.
+// With a third node connected to both producers, which would relay
+// transactions between them if they were only disconnected from each other,
+// each branch still holds only the transaction sent to its producer, and the
+// connections are restored afterwards.
+func TestSimulateReorg(t *testing.T) {
+	for _, nodeCount := range []int{2, 3} {
+		t.Run(fmt.Sprintf("%d nodes", nodeCount), func(t *testing.T) {
+			testSimulateReorg(t, nodeCount)
+		})
+	}
+}
+
+// testSimulateReorg runs TestSimulateReorg on nodeCount nodes, with nodes 0 and 1 as producers.
+func testSimulateReorg(t *testing.T, nodeCount int) {
+	keys := []*ecdsa.PrivateKey{unittest.PrivateKeyFixture(t), unittest.PrivateKeyFixture(t)}
+	funds := new(big.Int).Mul(big.NewInt(10), big.NewInt(params.Ether))
+	ctx, manager := unittest.StartExternalNodes(
+		t, nodeCount,
+		node.WithPreFundGenesisAccount(crypto.PubkeyToAddress(keys[0].PublicKey), funds),
+		node.WithPreFundGenesisAccount(crypto.PubkeyToAddress(keys[1].PublicKey), funds),
+	)
+	if nodeCount == 3 {
+		// Start connects node 2 to node 0 only; a link to node 1 opens a relay path between the producers.
+		require.NoError(t, manager.Connect(2, 1))
+	}
+	peersBefore := make([][]int, nodeCount)
+	for i := range peersBefore {
+		for j := 0; j < nodeCount; j++ {
+			if j != i {
+				peersBefore[i] = append(peersBefore[i], j)
+			}
+		}
+	}
+	require.Eventually(
+		t, func() bool {
+			for i, expected := range peersBefore {
+				peers, err := manager.ConnectedPeers(i)
+				if err != nil || len(peers) != len(expected) {
+					return false
+				}
+			}
+			return true
+		}, node.OperationTimeout, 50*time.Millisecond, "nodes should form a full mesh",
+	)
+	// Geth only accepts gossiped transactions once a forkchoice update marks
+	// it synced, so without this no node would relay the transactions.
+	for i := 0; i < nodeCount; i++ {
+		driver, err := manager.NewEngineDriver(ctx, i)
+		require.NoError(t, err)
+		require.NoError(t, driver.UpdateForkchoice(ctx, driver.Forkchoice()))
+		driver.Close()
+	}
+
+	sent := make(map[int]common.Hash)
+	report, err := manager.SimulateReorg(ctx, node.ReorgOptions{
+		Producers: [2]int{0, 1},
+		Depths:    [2]int{3, 2},
+		Winner:    1,
+		BeforeBlock: func(ctx context.Context, nodeIndex int, height int) error {
+			if height == 0 {
+				sent[nodeIndex] = sendTransfer(t, ctx, manager, nodeIndex, keys[nodeIndex])
+			}
+			return nil
+		},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, uint64(0), report.CommonAncestorNumber)
+	require.Equal(t, 3, report.Depth)
+	require.Equal(t, report.Heads[1], report.CanonicalHead)
+	require.NotEqual(t, report.Heads[0], report.Heads[1])
+	require.Equal(t, []common.Hash{sent[0]}, report.DroppedTxs, "each branch should only hold the transaction sent to its producer")
+
+	for i := 0; i < 2; i++ {
+		client, err := rpc.DialContext(ctx, utils.LocalAddress(manager.GetRPCPort(i)))
+		require.NoError(t, err)
+
+		var head map[string]interface{}
+		require.NoError(t, client.CallContext(ctx, &head, model.EthGetBlockByNumber, model.EthBlockLatest, false))
+		require.Equal(t, report.CanonicalHead.Hex(), head[model.BlockHash])
+		require.Equal(t, "0x2", head[model.BlockNumber])
+
+		// Only the winning branch's transaction is included on either node.
+		var receipt map[string]interface{}
+		require.NoError(t, client.CallContext(ctx, &receipt, model.EthGetTransactionReceipt, sent[1]))
+		require.NotNil(t, receipt)
+		receipt = nil
+		require.NoError(t, client.CallContext(ctx, &receipt, model.EthGetTransactionReceipt, sent[0]))
+		require.Nil(t, receipt)
+		client.Close()
+	}
+
+	for i := range peersBefore {
+		peers, err := manager.ConnectedPeers(i)
+		require.NoError(t, err)
+		require.Equal(t, peersBefore[i], peers, "connections of node %d should be restored", i)
+	}
+}
+
+// TestSimulateReorgRequiresExternalConsensus verifies reorgs are only simulated
+// when nothing else produces blocks.
+func TestSimulateReorgRequiresExternalConsensus(t *testing.T) {
+	ctx, cancel, manager := startNodes(t, 2)
+	defer cancel()
+
+	_, err := manager.SimulateReorg(ctx, node.ReorgOptions{Producers: [2]int{0, 1}, Depths: [2]int{1, 1}})
+	require.ErrorContains(t, err, "requires external consensus")
+}
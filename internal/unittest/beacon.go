@@ -0,0 +1,213 @@
+package unittest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// FakeBeacon is a minimal in-process Beacon API server for testing code that
+// talks to a consensus client. Its responses are controlled by the test.
+//
+// A new FakeBeacon is healthy, synced, has its EL online and knows its genesis.
+type FakeBeacon struct {
+	server *httptest.Server
+
+	mu                    sync.RWMutex
+	healthStatus          int
+	syncing               bool
+	elOffline             bool
+	headSlot              uint64
+	genesisKnown          bool
+	genesisTime           time.Time
+	genesisValidatorsRoot common.Hash
+	justifiedEpoch        uint64
+	finalizedEpoch        uint64
+	states                map[string][]byte
+	blocks                map[string][]byte
+}
+
+// NewFakeBeacon starts a FakeBeacon that is closed when the test finishes.
+func NewFakeBeacon(t *testing.T) *FakeBeacon {
+	t.Helper()
+
+	f := &FakeBeacon{
+		healthStatus:          http.StatusOK,
+		genesisKnown:          true,
+		genesisTime:           time.Unix(1_700_000_000, 0),
+		genesisValidatorsRoot: common.HexToHash("0x01"),
+		states:                make(map[string][]byte),
+		blocks:                make(map[string][]byte),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/eth/v1/node/health", f.handleHealth)
+	mux.HandleFunc("/eth/v1/node/syncing", f.handleSyncing)
+	mux.HandleFunc("/eth/v1/beacon/genesis", f.handleGenesis)
+	mux.HandleFunc("/eth/v1/beacon/states/head/finality_checkpoints", f.handleFinalityCheckpoints)
+	mux.HandleFunc("/eth/v2/debug/beacon/states/{id}", f.handleState)
+	mux.HandleFunc("/eth/v2/beacon/blocks/{id}", f.handleBlock)
+	f.server = httptest.NewServer(mux)
+	t.Cleanup(f.server.Close)
+
+	return f
+}
+
+// URL returns the base URL of the fake Beacon API.
+func (f *FakeBeacon) URL() string {
+	return f.server.URL
+}
+
+// SetHealthStatus sets the HTTP status returned by /eth/v1/node/health
+// (200 ready, 206 syncing, 503 not initialized).
+func (f *FakeBeacon) SetHealthStatus(status int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.healthStatus = status
+}
+
+// SetSyncing sets is_syncing reported by /eth/v1/node/syncing.
+func (f *FakeBeacon) SetSyncing(syncing bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.syncing = syncing
+}
+
+// SetELOffline sets el_offline reported by /eth/v1/node/syncing.
+func (f *FakeBeacon) SetELOffline(offline bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.elOffline = offline
+}
+
+// SetGenesisKnown controls whether /eth/v1/beacon/genesis answers (true) or returns 404 (false).
+func (f *FakeBeacon) SetGenesisKnown(known bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.genesisKnown = known
+}
+
+// SetGenesis sets the genesis time and validators root served by /eth/v1/beacon/genesis.
+func (f *FakeBeacon) SetGenesis(genesisTime time.Time, genesisValidatorsRoot common.Hash) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.genesisTime = genesisTime
+	f.genesisValidatorsRoot = genesisValidatorsRoot
+}
+
+// SetFinality sets the current justified and finalized epochs served by
+// /eth/v1/beacon/states/head/finality_checkpoints.
+func (f *FakeBeacon) SetFinality(justifiedEpoch uint64, finalizedEpoch uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.justifiedEpoch = justifiedEpoch
+	f.finalizedEpoch = finalizedEpoch
+}
+
+// SetState serves an SSZ-encoded beacon state at /eth/v2/debug/beacon/states/{id},
+// where id is a named state ("genesis", "finalized", "head") or a slot.
+func (f *FakeBeacon) SetState(id string, ssz []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.states[id] = ssz
+}
+
+// SetBlock serves an SSZ-encoded signed beacon block at /eth/v2/beacon/blocks/{id},
+// where id is a named block ("genesis", "finalized", "head") or a slot.
+func (f *FakeBeacon) SetBlock(id string, ssz []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.blocks[id] = ssz
+}
+
+func (f *FakeBeacon) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	w.WriteHeader(f.healthStatus)
+}
+
+func (f *FakeBeacon) handleSyncing(w http.ResponseWriter, _ *http.Request) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	distance := 0
+	if f.syncing {
+		distance = 10
+	}
+	writeBeaconData(w, map[string]interface{}{
+		"head_slot":     fmt.Sprintf("%d", f.headSlot),
+		"sync_distance": fmt.Sprintf("%d", distance),
+		"is_syncing":    f.syncing,
+		"is_optimistic": false,
+		"el_offline":    f.elOffline,
+	})
+}
+
+func (f *FakeBeacon) handleGenesis(w http.ResponseWriter, _ *http.Request) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if !f.genesisKnown {
+		http.Error(w, `{"code":404,"message":"Chain genesis info is not yet known"}`, http.StatusNotFound)
+		return
+	}
+	writeBeaconData(w, map[string]interface{}{
+		"genesis_time":            fmt.Sprintf("%d", f.genesisTime.Unix()),
+		"genesis_validators_root": f.genesisValidatorsRoot.Hex(),
+		"genesis_fork_version":    "0x00000000",
+	})
+}
+
+func (f *FakeBeacon) handleFinalityCheckpoints(w http.ResponseWriter, _ *http.Request) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	checkpoint := func(epoch uint64) map[string]string {
+		return map[string]string{
+			"epoch": fmt.Sprintf("%d", epoch),
+			"root":  common.BigToHash(new(big.Int).SetUint64(epoch + 1)).Hex(),
+		}
+	}
+	previousJustified := f.justifiedEpoch
+	if previousJustified > 0 {
+		previousJustified--
+	}
+	writeBeaconData(w, map[string]interface{}{
+		"previous_justified": checkpoint(previousJustified),
+		"current_justified":  checkpoint(f.justifiedEpoch),
+		"finalized":          checkpoint(f.finalizedEpoch),
+	})
+}
+
+func (f *FakeBeacon) handleState(w http.ResponseWriter, r *http.Request) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	writeSSZ(w, f.states, r.PathValue("id"))
+}
+
+func (f *FakeBeacon) handleBlock(w http.ResponseWriter, r *http.Request) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	writeSSZ(w, f.blocks, r.PathValue("id"))
+}
+
+// writeSSZ writes the SSZ object stored under id, or a Beacon API 404 if there is none.
+func writeSSZ(w http.ResponseWriter, objects map[string][]byte, id string) {
+	ssz, ok := objects[id]
+	if !ok {
+		http.Error(w, fmt.Sprintf(`{"code":404,"message":"%s not found"}`, id), http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	_, _ = w.Write(ssz)
+}
+
+// writeBeaconData writes a Beacon API JSON response wrapping data in the "data" envelope.
+func writeBeaconData(w http.ResponseWriter, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+}
@@ -0,0 +1,39 @@
+package unittest
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/thep2p/go-eth-localnet/internal/consensus"
+	"github.com/thep2p/go-eth-localnet/internal/consensus/prysm"
+)
+
+// genesisTimeOffset makes the genesis time of every ConsensusConfigFixture unique.
+var genesisTimeOffset atomic.Int64
+
+// ConsensusConfigFixture returns a valid consensus configuration for a network
+// of validatorCount validators with deterministic interop keys, withdrawing to
+// random execution addresses. Each fixture gets its own genesis time, so
+// networks generated by parallel tests have distinct execution genesis blocks
+// and RANDAO seeds, which keeps prysm's seed-keyed committee caches apart.
+// Tests override the fields they exercise.
+func ConsensusConfigFixture(t *testing.T, validatorCount int) consensus.Config {
+	t.Helper()
+
+	validatorKeys, err := prysm.GenerateValidatorKeys(validatorCount)
+	require.NoError(t, err)
+
+	return consensus.Config{
+		DataDir:             "/tmp/test",
+		ChainID:             1337,
+		GenesisTime:         time.Unix(1_700_000_000+genesisTimeOffset.Add(1), 0),
+		BeaconPort:          4000,
+		P2PPort:             9000,
+		EngineEndpoint:      "http://localhost:8551",
+		JWTSecret:           []byte("secret"),
+		ValidatorKeys:       validatorKeys,
+		WithdrawalAddresses: RandomAddresses(t, validatorCount),
+	}
+}
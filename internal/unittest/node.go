@@ -0,0 +1,66 @@
+package unittest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+	"github.com/thep2p/go-eth-localnet/internal/node"
+	"github.com/thep2p/go-eth-localnet/internal/utils"
+)
+
+// StartNodes starts nodeCount nodes backed by the simulated beacon, with their
+// data in a temporary directory. When the test finishes the nodes are shut
+// down within node.ShutdownTimeout and the directory is removed.
+// Returns a context cancelled at the end of the test, and the manager.
+func StartNodes(t *testing.T, nodeCount int, opts ...node.LaunchOption) (context.Context, *node.Manager) {
+	t.Helper()
+	return startNodes(t, nodeCount, false, opts...)
+}
+
+// StartExternalNodes is StartNodes with external consensus enabled (see
+// node.Manager.EnableExternalConsensus), so the nodes only produce blocks
+// when driven over the Engine API.
+func StartExternalNodes(t *testing.T, nodeCount int, opts ...node.LaunchOption) (context.Context, *node.Manager) {
+	t.Helper()
+	return startNodes(t, nodeCount, true, opts...)
+}
+
+// startNodes starts nodeCount nodes with a new manager, with external consensus
+// if externalConsensus is set.
+func startNodes(t *testing.T, nodeCount int, externalConsensus bool, opts ...node.LaunchOption) (context.Context, *node.Manager) {
+	t.Helper()
+
+	tmp := NewTempDir(t)
+	manager := node.NewNodeManager(
+		Logger(t), node.NewLauncher(Logger(t)), tmp.Path(), func() int {
+			return NewPort(t)
+		},
+	)
+	if externalConsensus {
+		require.NoError(t, manager.EnableExternalConsensus())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(tmp.Remove)
+	t.Cleanup(
+		func() {
+			cancel()
+			RequireCallMustReturnWithinTimeout(t, manager.Done, node.ShutdownTimeout, "node shutdown failed")
+		},
+	)
+	require.NoError(t, manager.Start(ctx, nodeCount, opts...))
+	return ctx, manager
+}
+
+// DialNode connects to the RPC endpoint of the node at the given index.
+// The client is closed when the test finishes.
+func DialNode(t *testing.T, ctx context.Context, manager *node.Manager, index int) *rpc.Client {
+	t.Helper()
+
+	client, err := rpc.DialContext(ctx, utils.LocalAddress(manager.GetRPCPort(index)))
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return client
+}
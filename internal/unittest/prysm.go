@@ -0,0 +1,24 @@
+package unittest
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/thep2p/go-eth-localnet/internal/consensus/prysm"
+)
+
+// RequirePrysm skips the test unless the prysm beacon-chain and validator
+// binaries are in PATH, and returns their paths for prysm.NewLauncher.
+func RequirePrysm(t *testing.T) (string, string) {
+	t.Helper()
+
+	beaconBinary, err := exec.LookPath(prysm.BeaconChainBinary)
+	if err != nil {
+		t.Skipf("%s not found in PATH", prysm.BeaconChainBinary)
+	}
+	validatorBinary, err := exec.LookPath(prysm.ValidatorBinary)
+	if err != nil {
+		t.Skipf("%s not found in PATH", prysm.ValidatorBinary)
+	}
+	return beaconBinary, validatorBinary
+}
@@ -0,0 +1,26 @@
+package unittest
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// RequireSolc skips the test unless a solc compiler is available where
+// contracts.Compile looks for one: the solc-<version> binaries of the
+// directory named by SOLC_CACHE_DIR if set, otherwise solc on PATH.
+func RequireSolc(t *testing.T) {
+	t.Helper()
+
+	if dir := os.Getenv("SOLC_CACHE_DIR"); dir != "" {
+		binaries, err := filepath.Glob(filepath.Join(dir, "solc-*"))
+		if err != nil || len(binaries) == 0 {
+			t.Skipf("no solc binaries in SOLC_CACHE_DIR %s", dir)
+		}
+		return
+	}
+	if _, err := exec.LookPath("solc"); err != nil {
+		t.Skip("solc not found in PATH")
+	}
+}
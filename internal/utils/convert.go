@@ -14,3 +14,8 @@ func ByteToHex(b []byte) string {
 func LocalAddress(port int) string {
 	return fmt.Sprintf("http://127.0.0.1:%d", port)
 }
+
+// LocalWSAddress returns the local websocket address for a given port.
+func LocalWSAddress(port int) string {
+	return fmt.Sprintf("ws://127.0.0.1:%d", port)
+}